@@ -0,0 +1,142 @@
+package activitypub
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+const (
+	// Media type of ActivityPub requests and responses
+	ContentType = "application/activity+json"
+
+	// Collection addressing everyone
+	Public = "https://www.w3.org/ns/activitystreams#Public"
+
+	// JSON-LD context of the objects
+	ContextURL = "https://www.w3.org/ns/activitystreams"
+)
+
+// Activity types handled
+const (
+	TypeCreate = "Create"
+	TypeDelete = "Delete"
+	TypeFollow = "Follow"
+	TypeAccept = "Accept"
+	TypeUndo   = "Undo"
+)
+
+type (
+	// Recipients, which may be a single ID or an array of IDs in JSON
+	Addresses []string
+
+	Activity struct {
+		Context string `json:"@context,omitempty"`
+		ID      string `json:"id"`
+		Type    string `json:"type"`
+		Actor   string `json:"actor"`
+		// ID of the object or the object itself
+		Object    json.RawMessage `json:"object"`
+		To        Addresses       `json:"to,omitempty"`
+		CC        Addresses       `json:"cc,omitempty"`
+		Published *time.Time      `json:"published,omitempty"`
+	}
+
+	// Person actor
+	Actor struct {
+		Context           string  `json:"@context,omitempty"`
+		ID                string  `json:"id"`
+		Type              string  `json:"type"`
+		PreferredUsername string  `json:"preferredUsername"`
+		Name              *string `json:"name,omitempty"`
+		Summary           *string `json:"summary,omitempty"`
+		Inbox             string  `json:"inbox"`
+		Outbox            string  `json:"outbox,omitempty"`
+		Followers         string  `json:"followers,omitempty"`
+		Endpoints         *struct {
+			SharedInbox string `json:"sharedInbox,omitempty"`
+		} `json:"endpoints,omitempty"`
+		PublicKey *PublicKey `json:"publicKey,omitempty"`
+	}
+
+	// Key verifying HTTP Signatures of the actor
+	PublicKey struct {
+		ID           string `json:"id"`
+		Owner        string `json:"owner"`
+		PublicKeyPem string `json:"publicKeyPem"`
+	}
+
+	// Note object, or Tombstone of deleted one
+	Note struct {
+		Context      string     `json:"@context,omitempty"`
+		ID           string     `json:"id"`
+		Type         string     `json:"type"`
+		AttributedTo string     `json:"attributedTo,omitempty"`
+		Content      string     `json:"content,omitempty"`
+		Published    *time.Time `json:"published,omitempty"`
+		To           Addresses  `json:"to,omitempty"`
+		CC           Addresses  `json:"cc,omitempty"`
+	}
+
+	OrderedCollection struct {
+		Context      string        `json:"@context,omitempty"`
+		ID           string        `json:"id"`
+		Type         string        `json:"type"`
+		TotalItems   int64         `json:"totalItems"`
+		OrderedItems []interface{} `json:"orderedItems,omitempty"`
+	}
+)
+
+// encoding/json/Unmarshaler
+func (a *Addresses) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		*a = Addresses{s}
+		return nil
+	}
+	var ss []string
+	if err := json.Unmarshal(b, &ss); err != nil {
+		return err
+	}
+	*a = ss
+	return nil
+}
+
+// Check if id is one of the addresses
+func (a Addresses) Contains(id string) bool {
+	for _, v := range a {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+// ID of the activity object whether it is embedded or not
+func (a *Activity) ObjectID() (string, error) {
+	var id string
+	if err := json.Unmarshal(a.Object, &id); err == nil {
+		return id, nil
+	}
+	var obj struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(a.Object, &obj); err != nil {
+		return "", err
+	}
+	if obj.ID == "" {
+		return "", errors.New("object has no id")
+	}
+	return obj.ID, nil
+}
+
+// Decode the embedded activity object into v, false if the object is only referred by its ID
+func (a *Activity) DecodeObject(v interface{}) (bool, error) {
+	if len(a.Object) == 0 || a.Object[0] != '{' {
+		return false, nil
+	}
+	if err := json.Unmarshal(a.Object, v); err != nil {
+		return false, err
+	}
+	return true, nil
+}
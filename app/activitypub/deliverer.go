@@ -0,0 +1,138 @@
+package activitypub
+
+import (
+	"bytes"
+	"context"
+	"crypto/rsa"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/satorunooshie/Yatter/app/domain/object"
+	"github.com/satorunooshie/Yatter/app/httpsig"
+	"github.com/satorunooshie/Yatter/app/webhook"
+)
+
+const (
+	// Maximum number of deliveries attempted at once
+	batchSize = 100
+	// Attempts of a delivery before giving up
+	maxAttempts = 8
+
+	userAgent = "Yatter-ActivityPub"
+)
+
+// Deliver queued activities every interval until ctx is done
+func (f *Federation) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := f.DeliverDue(ctx); err != nil {
+			log.Printf("[WARN] activitypub::Run::DeliverDue(): %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Attempt deliveries whose next attempt is due, retrying failed ones with the same backoff as webhooks.
+//
+// Each attempt is made by one instance even if several run, since only the one which claims it delivers it.
+func (f *Federation) DeliverDue(ctx context.Context) error {
+	now := f.now()
+	repo := f.dao.ActivityDelivery()
+
+	due, err := repo.SelectDue(ctx, now, batchSize)
+	if err != nil {
+		return err
+	}
+
+	signers := make(map[object.AccountID]*object.Account)
+	for _, v := range due {
+		/* 応答がないまま落ちても、期限が過ぎれば再試行される */
+		claimed, err := repo.Claim(ctx, v.ID, v.Attempts, now.Add(2*requestTimeout))
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			continue
+		}
+		v.Attempts++
+
+		signer, ok := signers[v.AccountID]
+		if !ok {
+			signer, err = f.dao.Account().FindByID(ctx, v.AccountID)
+			if err != nil {
+				return err
+			}
+			signers[v.AccountID] = signer
+		}
+
+		if err := f.attempt(ctx, signer, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Post the activity signed by signer to the inbox and record the result
+func (f *Federation) attempt(ctx context.Context, signer *object.Account, v *object.ActivityDelivery) error {
+	repo := f.dao.ActivityDelivery()
+
+	/* 署名できなければ再試行しても変わらない */
+	if signer == nil || signer.PrivateKey == nil {
+		return repo.Fail(ctx, v.ID, "no key to sign the delivery", nil)
+	}
+	key, err := httpsig.ParsePrivateKey(*signer.PrivateKey)
+	if err != nil {
+		return repo.Fail(ctx, v.ID, err.Error(), nil)
+	}
+
+	err = f.post(ctx, f.KeyID(signer.Username), key, v)
+	if err == nil {
+		return repo.Succeed(ctx, v.ID)
+	}
+
+	var next *time.Time
+	if v.Attempts < maxAttempts {
+		t := f.now().Add(webhook.Backoff(v.Attempts))
+		next = &t
+	}
+	return repo.Fail(ctx, v.ID, err.Error(), next)
+}
+
+func (f *Federation) post(ctx context.Context, keyID string, key *rsa.PrivateKey, v *object.ActivityDelivery) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.InboxURL, bytes.NewReader(v.Activity))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", ContentType)
+	req.Header.Set("User-Agent", userAgent)
+	if err := httpsig.Sign(req, v.Activity, keyID, key, f.now()); err != nil {
+		return err
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		/* コネクションを再利用できるように読み切る */
+		_, _ = io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 1<<16))
+		if err := resp.Body.Close(); err != nil {
+			log.Printf("[WARN] activitypub::post::resp.Body.Close(): %v", err)
+		}
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected response: %s", resp.Status)
+	}
+	return nil
+}
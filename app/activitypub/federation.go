@@ -0,0 +1,264 @@
+package activitypub
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/satorunooshie/Yatter/app/dao"
+	"github.com/satorunooshie/Yatter/app/domain/content"
+	"github.com/satorunooshie/Yatter/app/domain/object"
+)
+
+// Time allowed for a remote server to respond
+const requestTimeout = 10 * time.Second
+
+// Federates local accounts and statuses with remote servers over ActivityPub
+type Federation struct {
+	dao     dao.Dao
+	baseURL string
+	client  *http.Client
+	now     func() time.Time
+	replays replayCache
+}
+
+// Create Federation for the server reachable at baseURL
+func New(d dao.Dao, baseURL string) *Federation {
+	return &Federation{
+		dao:     d,
+		baseURL: baseURL,
+		client:  &http.Client{Timeout: requestTimeout, Transport: publicTransport()},
+		now:     time.Now,
+	}
+}
+
+// Let the federation reach servers on loopback and private networks, which only development setups need
+func (f *Federation) AllowPrivateAddresses() {
+	f.client = &http.Client{Timeout: requestTimeout}
+}
+
+// Remote servers give URLs to fetch and deliver to, which must not make this server reach internal ones
+var errPrivateAddress = errors.New("address is not public")
+
+// Transport connecting only to public addresses.
+// The address is checked after the name is resolved so that DNS cannot point it elsewhere in between.
+func publicTransport() *http.Transport {
+	dialer := &net.Dialer{
+		Timeout: requestTimeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !isPublic(ip) {
+				return errPrivateAddress
+			}
+			return nil
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return transport
+}
+
+func isPublic(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified())
+}
+
+// URL the server is reachable at from other servers
+func (f *Federation) BaseURL() string {
+	return f.baseURL
+}
+
+// ID of the local account's actor
+func (f *Federation) ActorURI(username string) string {
+	return f.baseURL + "/users/" + url.PathEscape(username)
+}
+
+// ID of the key signing requests of the local account
+func (f *Federation) KeyID(username string) string {
+	return f.ActorURI(username) + "#main-key"
+}
+
+func (f *Federation) InboxURI(username string) string {
+	return f.ActorURI(username) + "/inbox"
+}
+
+func (f *Federation) OutboxURI(username string) string {
+	return f.ActorURI(username) + "/outbox"
+}
+
+func (f *Federation) FollowersURI(username string) string {
+	return f.ActorURI(username) + "/followers"
+}
+
+// ID of the Note of the local status
+func (f *Federation) StatusURI(username string, id object.StatusID) string {
+	return f.ActorURI(username) + "/statuses/" + strconv.FormatInt(id, 10)
+}
+
+// Person actor of the local account
+func (f *Federation) Actor(account *object.Account) *Actor {
+	actor := &Actor{
+		Context:           ContextURL,
+		ID:                f.ActorURI(account.Username),
+		Type:              "Person",
+		PreferredUsername: account.Username,
+		Name:              account.DisplayName,
+		Summary:           account.Note,
+		Inbox:             f.InboxURI(account.Username),
+		Outbox:            f.OutboxURI(account.Username),
+		Followers:         f.FollowersURI(account.Username),
+	}
+	if account.PublicKey != nil {
+		actor.PublicKey = &PublicKey{
+			ID:           f.KeyID(account.Username),
+			Owner:        actor.ID,
+			PublicKeyPem: *account.PublicKey,
+		}
+	}
+	return actor
+}
+
+// Note of the local status posted by account
+func (f *Federation) Note(account *object.Account, status *object.Status) *Note {
+	to, cc := f.addressing(account, status.Visibility)
+	html := status.Content
+	if html == "" {
+		html = content.Render(status)
+	}
+	published := status.CreateAt.Time
+	return &Note{
+		ID:           f.StatusURI(account.Username, status.ID),
+		Type:         "Note",
+		AttributedTo: f.ActorURI(account.Username),
+		Content:      html,
+		Published:    &published,
+		To:           to,
+		CC:           cc,
+	}
+}
+
+// Create activity of the local status
+func (f *Federation) CreateActivity(account *object.Account, status *object.Status) (*Activity, error) {
+	note := f.Note(account, status)
+	obj, err := json.Marshal(note)
+	if err != nil {
+		return nil, err
+	}
+	return &Activity{
+		Context:   ContextURL,
+		ID:        note.ID + "/activity",
+		Type:      TypeCreate,
+		Actor:     note.AttributedTo,
+		Object:    obj,
+		To:        note.To,
+		CC:        note.CC,
+		Published: note.Published,
+	}, nil
+}
+
+// Deliver the created status to the servers following account.
+// Direct statuses are not federated since mentions of remote accounts are not resolved.
+func (f *Federation) PublishCreate(ctx context.Context, account *object.Account, status *object.Status) error {
+	if status.Visibility == object.VisibilityDirect {
+		return nil
+	}
+	activity, err := f.CreateActivity(account, status)
+	if err != nil {
+		return err
+	}
+	return f.deliverToFollowers(ctx, account, activity)
+}
+
+// Deliver the deletion of the status to the servers following account
+func (f *Federation) PublishDelete(ctx context.Context, account *object.Account, status *object.Status) error {
+	if status.Visibility == object.VisibilityDirect {
+		return nil
+	}
+	to, cc := f.addressing(account, status.Visibility)
+	id := f.StatusURI(account.Username, status.ID)
+	obj, err := json.Marshal(&Note{ID: id, Type: "Tombstone"})
+	if err != nil {
+		return err
+	}
+	return f.deliverToFollowers(ctx, account, &Activity{
+		Context: ContextURL,
+		ID:      id + "#delete",
+		Type:    TypeDelete,
+		Actor:   f.ActorURI(account.Username),
+		Object:  obj,
+		To:      to,
+		CC:      cc,
+	})
+}
+
+// Ask the remote actor to let the local account follow it.
+// The follow is recorded once the remote server accepts it.
+func (f *Federation) Follow(ctx context.Context, follower *object.Account, actorURI string) (*object.Account, error) {
+	followee, err := f.resolveActor(ctx, actorURI)
+	if err != nil {
+		return nil, err
+	}
+	obj, err := json.Marshal(followee.URI)
+	if err != nil {
+		return nil, err
+	}
+	if err := f.deliver(ctx, follower, []string{*followee.InboxURL}, &Activity{
+		Context: ContextURL,
+		ID:      f.followID(follower, followee),
+		Type:    TypeFollow,
+		Actor:   f.ActorURI(follower.Username),
+		Object:  obj,
+	}); err != nil {
+		return nil, err
+	}
+	return followee, nil
+}
+
+// ID of the Follow activity of the local account following the remote one
+func (f *Federation) followID(follower, followee *object.Account) string {
+	return f.ActorURI(follower.Username) + "#follows/" + strconv.FormatInt(followee.ID, 10)
+}
+
+// Addressing of a status by its visibility
+func (f *Federation) addressing(account *object.Account, v object.Visibility) (to, cc Addresses) {
+	followers := f.FollowersURI(account.Username)
+	switch v {
+	case object.VisibilityPublic:
+		return Addresses{Public}, Addresses{followers}
+	case object.VisibilityUnlisted:
+		return Addresses{followers}, Addresses{Public}
+	default:
+		return Addresses{followers}, nil
+	}
+}
+
+func (f *Federation) deliverToFollowers(ctx context.Context, account *object.Account, activity *Activity) error {
+	inboxes, err := f.dao.Relationship().SelectFollowerInboxes(ctx, account.ID)
+	if err != nil {
+		return err
+	}
+	return f.deliver(ctx, account, inboxes, activity)
+}
+
+// Queue the activity for the inboxes, to be signed by account and delivered by DeliverDue
+func (f *Federation) deliver(ctx context.Context, account *object.Account, inboxes []string, activity *Activity) error {
+	if len(inboxes) == 0 {
+		return nil
+	}
+	b, err := json.Marshal(activity)
+	if err != nil {
+		return fmt.Errorf("marshal %s activity: %w", activity.Type, err)
+	}
+	return f.dao.ActivityDelivery().Insert(ctx, account.ID, b, inboxes)
+}
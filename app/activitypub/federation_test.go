@@ -0,0 +1,492 @@
+package activitypub_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+
+	"github.com/satorunooshie/Yatter/app/activitypub"
+	"github.com/satorunooshie/Yatter/app/app"
+	"github.com/satorunooshie/Yatter/app/dao"
+	"github.com/satorunooshie/Yatter/app/domain/object"
+	"github.com/satorunooshie/Yatter/app/domain/repository"
+	"github.com/satorunooshie/Yatter/app/handler/users"
+	"github.com/satorunooshie/Yatter/app/httpsig"
+)
+
+// In-memory storage of an instance, shared by the repositories
+type store struct {
+	mu         sync.Mutex
+	accounts   []*object.Account
+	statuses   []*object.Status
+	follows    map[[2]object.AccountID]bool
+	deliveries []*object.ActivityDelivery
+}
+
+type memoryDao struct {
+	dao.Dao
+	s *store
+}
+
+func (d *memoryDao) Account() repository.Account           { return &memoryAccount{s: d.s} }
+func (d *memoryDao) Status() repository.Status             { return &memoryStatus{s: d.s} }
+func (d *memoryDao) Relationship() repository.Relationship { return &memoryRelationship{s: d.s} }
+func (d *memoryDao) ActivityDelivery() repository.ActivityDelivery {
+	return &memoryActivityDelivery{s: d.s}
+}
+
+type memoryAccount struct {
+	repository.Account
+	s *store
+}
+
+func (r *memoryAccount) FindByID(_ context.Context, id object.AccountID) (*object.Account, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for _, v := range r.s.accounts {
+		if v.ID == id {
+			return v, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryAccount) FindByUsername(_ context.Context, username string) (*object.Account, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for _, v := range r.s.accounts {
+		if v.Username == username && v.IsLocal() {
+			return v, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryAccount) FindByURI(_ context.Context, uri string) (*object.Account, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for _, v := range r.s.accounts {
+		if v.URI != nil && *v.URI == uri {
+			return v, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryAccount) UpsertRemote(_ context.Context, a *object.Account) (object.AccountID, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	stored := *a
+	for i, v := range r.s.accounts {
+		if v.Username == a.Username && v.Domain == a.Domain {
+			stored.ID = v.ID
+			r.s.accounts[i] = &stored
+			return v.ID, nil
+		}
+	}
+	stored.ID = int64(len(r.s.accounts) + 1)
+	r.s.accounts = append(r.s.accounts, &stored)
+	return stored.ID, nil
+}
+
+type memoryStatus struct {
+	repository.Status
+	s *store
+}
+
+func (r *memoryStatus) FindByURI(_ context.Context, uri string) (*object.Status, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for _, v := range r.s.statuses {
+		if v.URI != nil && *v.URI == uri && v.DeleteAt == nil {
+			return v, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryStatus) InsertRemote(_ context.Context, accountID object.AccountID, uri, content string, visibility object.Visibility, createAt time.Time) (object.StatusID, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	id := int64(len(r.s.statuses) + 1)
+	r.s.statuses = append(r.s.statuses, &object.Status{
+		ID:         id,
+		AccountID:  accountID,
+		URI:        &uri,
+		Text:       content,
+		Visibility: visibility,
+		CreateAt:   object.DateTime{Time: createAt},
+	})
+	return id, nil
+}
+
+func (r *memoryStatus) Delete(_ context.Context, id object.StatusID, accountID object.AccountID) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for _, v := range r.s.statuses {
+		if v.ID == id && v.AccountID == accountID {
+			v.DeleteAt = &object.DateTime{Time: time.Now()}
+		}
+	}
+	return nil
+}
+
+type memoryRelationship struct {
+	repository.Relationship
+	s *store
+}
+
+func (r *memoryRelationship) IsFollowing(_ context.Context, followerID, followeeID object.AccountID) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.s.follows[[2]object.AccountID{followerID, followeeID}], nil
+}
+
+func (r *memoryRelationship) SelectFollowerInboxes(_ context.Context, followeeID object.AccountID) ([]string, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	var inboxes []string
+	for _, v := range r.s.accounts {
+		if r.s.follows[[2]object.AccountID{v.ID, followeeID}] && !v.IsLocal() {
+			inboxes = append(inboxes, *v.InboxURL)
+		}
+	}
+	return inboxes, nil
+}
+
+func (r *memoryRelationship) Follow(_ context.Context, followerID, followeeID object.AccountID) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	r.s.follows[[2]object.AccountID{followerID, followeeID}] = true
+	return nil
+}
+
+func (r *memoryRelationship) Unfollow(_ context.Context, followerID, followeeID object.AccountID) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	delete(r.s.follows, [2]object.AccountID{followerID, followeeID})
+	return nil
+}
+
+type memoryActivityDelivery struct {
+	repository.ActivityDelivery
+	s *store
+}
+
+func (r *memoryActivityDelivery) Insert(_ context.Context, accountID object.AccountID, activity []byte, inboxURLs []string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for _, v := range inboxURLs {
+		r.s.deliveries = append(r.s.deliveries, &object.ActivityDelivery{
+			ID:            int64(len(r.s.deliveries) + 1),
+			AccountID:     accountID,
+			InboxURL:      v,
+			Activity:      activity,
+			NextAttemptAt: object.DateTime{Time: time.Now()},
+		})
+	}
+	return nil
+}
+
+func (r *memoryActivityDelivery) SelectDue(_ context.Context, until time.Time, _ int64) ([]*object.ActivityDelivery, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	var due []*object.ActivityDelivery
+	for _, v := range r.s.deliveries {
+		if v.Status == object.WebhookDeliveryPending && !v.NextAttemptAt.After(until) {
+			copied := *v
+			due = append(due, &copied)
+		}
+	}
+	return due, nil
+}
+
+func (r *memoryActivityDelivery) Claim(_ context.Context, id object.ActivityDeliveryID, attempts int64, leaseUntil time.Time) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	v := r.s.deliveries[id-1]
+	if v.Status != object.WebhookDeliveryPending || v.Attempts != attempts {
+		return false, nil
+	}
+	v.Attempts++
+	v.NextAttemptAt = object.DateTime{Time: leaseUntil}
+	return true, nil
+}
+
+func (r *memoryActivityDelivery) Succeed(_ context.Context, id object.ActivityDeliveryID) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	r.s.deliveries[id-1].Status = object.WebhookDeliverySucceeded
+	return nil
+}
+
+func (r *memoryActivityDelivery) Fail(_ context.Context, id object.ActivityDeliveryID, reason string, _ *time.Time) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	v := r.s.deliveries[id-1]
+	v.Status = object.WebhookDeliveryFailed
+	v.Error = &reason
+	return nil
+}
+
+// Server running in process with one local account
+type instance struct {
+	store      *store
+	federation *activitypub.Federation
+	local      *object.Account
+}
+
+func newInstance(t *testing.T, username string) *instance {
+	t.Helper()
+
+	var handler http.Handler
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	local := &object.Account{ID: 1, Username: username}
+	if err := local.GenerateKeys(); err != nil {
+		t.Fatal(err)
+	}
+	s := &store{accounts: []*object.Account{local}, follows: make(map[[2]object.AccountID]bool)}
+	d := &memoryDao{s: s}
+	federation := activitypub.New(d, server.URL)
+	/* テストのサーバーはループバックで動く */
+	federation.AllowPrivateAddresses()
+
+	r := chi.NewRouter()
+	r.Mount("/users", users.NewRouter(&app.App{Dao: d, Federation: federation}))
+	handler = r
+
+	return &instance{store: s, federation: federation, local: local}
+}
+
+// Deliver the queued activities, failing the test if any of them is not accepted
+func (i *instance) deliver(t *testing.T) {
+	t.Helper()
+
+	if err := i.federation.DeliverDue(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range i.store.deliveries {
+		if v.Status != object.WebhookDeliverySucceeded {
+			t.Fatalf("delivery to %s was not accepted: %v", v.InboxURL, *v.Error)
+		}
+	}
+}
+
+func (i *instance) remoteAccount(t *testing.T, uri string) *object.Account {
+	t.Helper()
+
+	a, err := (&memoryAccount{s: i.store}).FindByURI(context.Background(), uri)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a == nil {
+		t.Fatalf("remote account %s is not stored", uri)
+	}
+	return a
+}
+
+func TestFederation(t *testing.T) {
+	ctx := context.Background()
+	alice := newInstance(t, "alice")
+	bob := newInstance(t, "bob")
+	aliceURI := alice.federation.ActorURI("alice")
+	bobURI := bob.federation.ActorURI("bob")
+
+	/* bob が alice をフォローし、alice のサーバーが承認する */
+	if _, err := bob.federation.Follow(ctx, bob.local, aliceURI); err != nil {
+		t.Fatal(err)
+	}
+	bob.deliver(t)
+	remoteBob := alice.remoteAccount(t, bobURI)
+	if remoteBob.Username != "bob" || *remoteBob.InboxURL != bob.federation.InboxURI("bob") {
+		t.Errorf("stored remote account = %+v", remoteBob)
+	}
+	if !alice.store.follows[[2]object.AccountID{remoteBob.ID, alice.local.ID}] {
+		t.Fatal("bob does not follow alice on alice's server")
+	}
+
+	remoteAlice := bob.remoteAccount(t, aliceURI)
+	if bob.store.follows[[2]object.AccountID{bob.local.ID, remoteAlice.ID}] {
+		t.Fatal("bob follows alice before the follow is accepted")
+	}
+	alice.deliver(t)
+	if !bob.store.follows[[2]object.AccountID{bob.local.ID, remoteAlice.ID}] {
+		t.Fatal("bob does not follow alice on bob's server")
+	}
+
+	/* alice の投稿が bob のサーバーに届く */
+	status := &object.Status{ID: 10, AccountID: alice.local.ID, Text: "hello\nfederation & friends", Visibility: object.VisibilityUnlisted, CreateAt: object.DateTime{Time: time.Now()}}
+	if err := alice.federation.PublishCreate(ctx, alice.local, status); err != nil {
+		t.Fatal(err)
+	}
+	alice.deliver(t)
+
+	uri := alice.federation.StatusURI("alice", status.ID)
+	received, err := (&memoryStatus{s: bob.store}).FindByURI(ctx, uri)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if received == nil {
+		t.Fatal("status is not delivered")
+	}
+	if received.AccountID != remoteAlice.ID || received.Text != status.Text || received.Visibility != object.VisibilityUnlisted {
+		t.Errorf("delivered status = %+v", received)
+	}
+
+	/* 直接のメッセージは配送しない */
+	direct := &object.Status{ID: 11, AccountID: alice.local.ID, Text: "secret", Visibility: object.VisibilityDirect}
+	if err := alice.federation.PublishCreate(ctx, alice.local, direct); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(alice.store.deliveries); got != 2 {
+		t.Errorf("direct status is queued: %d deliveries", got)
+	}
+
+	/* 削除も届く */
+	if err := alice.federation.PublishDelete(ctx, alice.local, status); err != nil {
+		t.Fatal(err)
+	}
+	alice.deliver(t)
+	if received.DeleteAt == nil {
+		t.Error("status is not deleted")
+	}
+}
+
+func TestFederation_VerifyRequest(t *testing.T) {
+	ctx := context.Background()
+	alice := newInstance(t, "alice")
+	bob := newInstance(t, "bob")
+	inbox := alice.federation.InboxURI("alice")
+	bobURI := bob.federation.ActorURI("bob")
+
+	/* alice のサーバーが bob を知っている状態にする */
+	if _, err := bob.federation.Follow(ctx, bob.local, alice.federation.ActorURI("alice")); err != nil {
+		t.Fatal(err)
+	}
+	bob.deliver(t)
+
+	bobKeyID := bob.federation.KeyID("bob")
+	bobKey, err := httpsig.ParsePrivateKey(*bob.local.PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	activity := func(actor string) []byte {
+		return []byte(`{"id":"` + actor + `#likes/1","type":"Like","actor":"` + actor + `","object":"` + inbox + `"}`)
+	}
+	post := func(t *testing.T, body []byte, header http.Header) int {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodPost, inbox, bytes.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header = header
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = resp.Body.Close()
+		return resp.StatusCode
+	}
+	sign := func(t *testing.T, body []byte, keyID string, key *rsa.PrivateKey, at time.Time) http.Header {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodPost, inbox, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := httpsig.Sign(req, body, keyID, key, at); err != nil {
+			t.Fatal(err)
+		}
+		return req.Header
+	}
+
+	signed := sign(t, activity(bobURI), bobKeyID, bobKey, time.Now())
+
+	tests := []struct {
+		name string
+		// Requests posted in order and the statuses of their responses
+		bodies  [][]byte
+		headers []http.Header
+		want    []int
+	}{
+		{
+			name:    "unsigned",
+			bodies:  [][]byte{activity(bobURI)},
+			headers: []http.Header{{}},
+			want:    []int{http.StatusUnauthorized},
+		},
+		{
+			name:    "signed and replayed",
+			bodies:  [][]byte{activity(bobURI), activity(bobURI)},
+			headers: []http.Header{signed, signed.Clone()},
+			want:    []int{http.StatusAccepted, http.StatusUnauthorized},
+		},
+		{
+			name:    "other key",
+			bodies:  [][]byte{activity(bobURI)},
+			headers: []http.Header{sign(t, activity(bobURI), bobKeyID, otherKey, time.Now())},
+			want:    []int{http.StatusUnauthorized},
+		},
+		{
+			name:    "key id not of the actor",
+			bodies:  [][]byte{activity(bobURI)},
+			headers: []http.Header{sign(t, activity(bobURI), bobURI+"#other-key", bobKey, time.Now())},
+			want:    []int{http.StatusUnauthorized},
+		},
+		{
+			name:    "stale",
+			bodies:  [][]byte{activity(bobURI)},
+			headers: []http.Header{sign(t, activity(bobURI), bobKeyID, bobKey, time.Now().Add(-2*time.Hour))},
+			want:    []int{http.StatusUnauthorized},
+		},
+		{
+			name:    "tampered",
+			bodies:  [][]byte{activity(bob.federation.ActorURI("someone"))},
+			headers: []http.Header{sign(t, activity(bobURI), bobKeyID, bobKey, time.Now())},
+			want:    []int{http.StatusUnauthorized},
+		},
+		{
+			name:    "activity of another actor",
+			bodies:  [][]byte{activity(bob.federation.ActorURI("someone"))},
+			headers: []http.Header{sign(t, activity(bob.federation.ActorURI("someone")), bobKeyID, bobKey, time.Now())},
+			want:    []int{http.StatusForbidden},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			for i := range tt.bodies {
+				if got := post(t, tt.bodies[i], tt.headers[i]); got != tt.want[i] {
+					t.Errorf("request %d responded %d, want %d", i, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestFederation_PrivateAddress(t *testing.T) {
+	alice := newInstance(t, "alice")
+	bob := newInstance(t, "bob")
+
+	/* 既定ではループバックのサーバーには接続しない */
+	federation := activitypub.New(&memoryDao{s: bob.store}, bob.federation.BaseURL())
+	if _, err := federation.Follow(context.Background(), bob.local, alice.federation.ActorURI("alice")); err == nil {
+		t.Error("Follow() reached the server on loopback")
+	}
+}
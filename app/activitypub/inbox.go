@@ -0,0 +1,204 @@
+package activitypub
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/satorunooshie/Yatter/app/domain/content"
+	"github.com/satorunooshie/Yatter/app/domain/object"
+)
+
+var (
+	// The activity is malformed
+	ErrInvalidActivity = errors.New("invalid activity")
+	// The actor is not allowed to do the activity
+	ErrForbidden = errors.New("forbidden activity")
+)
+
+// Process the activity posted to the inbox of the local account by the remote account which signed the request.
+//
+// Activities of unsupported types or about statuses the recipient does not follow are ignored.
+func (f *Federation) HandleInbox(ctx context.Context, recipient, signer *object.Account, activity *Activity) error {
+	if activity.ID == "" || activity.Actor == "" || len(activity.Object) == 0 {
+		return fmt.Errorf("%w: lacking id, actor or object", ErrInvalidActivity)
+	}
+	/* 転送された他人のアクティビティは受け付けない */
+	if signer.URI == nil || activity.Actor != *signer.URI {
+		return fmt.Errorf("%w: activity of %s signed by another actor", ErrForbidden, activity.Actor)
+	}
+
+	switch activity.Type {
+	case TypeFollow:
+		return f.handleFollow(ctx, recipient, signer, activity)
+	case TypeUndo:
+		return f.handleUndo(ctx, recipient, signer, activity)
+	case TypeAccept:
+		return f.handleAccept(ctx, recipient, signer, activity)
+	case TypeCreate:
+		return f.handleCreate(ctx, recipient, signer, activity)
+	case TypeDelete:
+		return f.handleDelete(ctx, signer, activity)
+	default:
+		return nil
+	}
+}
+
+// Let the remote actor follow the recipient, accepting it at once
+func (f *Federation) handleFollow(ctx context.Context, recipient, follower *object.Account, activity *Activity) error {
+	objectID, err := activity.ObjectID()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidActivity, err)
+	}
+	if objectID != f.ActorURI(recipient.Username) {
+		return fmt.Errorf("%w: following %s in the inbox of %s", ErrInvalidActivity, objectID, recipient.Username)
+	}
+
+	if err := f.dao.Relationship().Follow(ctx, follower.ID, recipient.ID); err != nil {
+		return err
+	}
+
+	follow := *activity
+	follow.Context = ""
+	obj, err := json.Marshal(&follow)
+	if err != nil {
+		return err
+	}
+	return f.deliver(ctx, recipient, []string{*follower.InboxURL}, &Activity{
+		Context: ContextURL,
+		ID:      f.ActorURI(recipient.Username) + "#accepts/follows/" + strconv.FormatInt(follower.ID, 10),
+		Type:    TypeAccept,
+		Actor:   f.ActorURI(recipient.Username),
+		Object:  obj,
+	})
+}
+
+// Undo a follow of the recipient by the remote actor
+func (f *Federation) handleUndo(ctx context.Context, recipient, follower *object.Account, activity *Activity) error {
+	var undone Activity
+	embedded, err := activity.DecodeObject(&undone)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidActivity, err)
+	}
+	/* フォロー以外の取り消しは扱わない */
+	if !embedded || undone.Type != TypeFollow {
+		return nil
+	}
+	if undone.Actor != activity.Actor {
+		return fmt.Errorf("%w: undoing a follow by %s", ErrForbidden, undone.Actor)
+	}
+	return f.dao.Relationship().Unfollow(ctx, follower.ID, recipient.ID)
+}
+
+// Record the follow of the remote actor by the recipient, which the actor accepted
+func (f *Federation) handleAccept(ctx context.Context, recipient, followee *object.Account, activity *Activity) error {
+	var follow Activity
+	embedded, err := activity.DecodeObject(&follow)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidActivity, err)
+	}
+	if !embedded {
+		follow.ID, err = activity.ObjectID()
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidActivity, err)
+		}
+		follow.Type = TypeFollow
+	}
+	/* 送った Follow への Accept だけを受け付ける */
+	if follow.Type != TypeFollow || follow.ID != f.followID(recipient, followee) {
+		return nil
+	}
+	return f.dao.Relationship().Follow(ctx, recipient.ID, followee.ID)
+}
+
+// Store the Note posted by a remote actor the recipient follows
+func (f *Federation) handleCreate(ctx context.Context, recipient, author *object.Account, activity *Activity) error {
+	var note Note
+	embedded, err := activity.DecodeObject(&note)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidActivity, err)
+	}
+	if !embedded || note.Type != "Note" {
+		return nil
+	}
+	if note.ID == "" {
+		return fmt.Errorf("%w: note has no id", ErrInvalidActivity)
+	}
+	if note.AttributedTo != activity.Actor {
+		return fmt.Errorf("%w: creating a note attributed to %s", ErrForbidden, note.AttributedTo)
+	}
+
+	following, err := f.dao.Relationship().IsFollowing(ctx, recipient.ID, author.ID)
+	if err != nil {
+		return err
+	}
+	if !following {
+		return nil
+	}
+
+	visibility, ok := f.visibilityOf(&note)
+	if !ok {
+		return nil
+	}
+
+	/* 同じサーバーのフォロワーごとに届くので一度だけ保存する */
+	existing, err := f.dao.Status().FindByURI(ctx, note.ID)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return nil
+	}
+
+	published := f.now()
+	if note.Published != nil {
+		published = *note.Published
+	}
+	_, err = f.dao.Status().InsertRemote(ctx, author.ID, note.ID, content.PlainText(note.Content), visibility, published)
+	return err
+}
+
+// Delete the status of the remote actor
+func (f *Federation) handleDelete(ctx context.Context, author *object.Account, activity *Activity) error {
+	objectID, err := activity.ObjectID()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidActivity, err)
+	}
+
+	status, err := f.dao.Status().FindByURI(ctx, objectID)
+	if err != nil {
+		return err
+	}
+	if status == nil {
+		return nil
+	}
+	if status.AccountID != author.ID {
+		return fmt.Errorf("%w: deleting a status of another account", ErrForbidden)
+	}
+	return f.dao.Status().Delete(ctx, status.ID, author.ID)
+}
+
+// Visibility of the remote Note by its addressing.
+// false for notes addressed only to local accounts, which are not stored
+// since their mentions are not resolved and nobody could see them.
+func (f *Federation) visibilityOf(note *Note) (object.Visibility, bool) {
+	switch {
+	case note.To.Contains(Public):
+		return object.VisibilityPublic, true
+	case note.CC.Contains(Public):
+		return object.VisibilityUnlisted, true
+	}
+	/* ローカルのアカウント以外（フォロワーのコレクション）宛てならフォロワー限定 */
+	local := f.baseURL + "/users/"
+	for _, addresses := range []Addresses{note.To, note.CC} {
+		for _, v := range addresses {
+			if !strings.HasPrefix(v, local) {
+				return object.VisibilityPrivate, true
+			}
+		}
+	}
+	return object.VisibilityDirect, false
+}
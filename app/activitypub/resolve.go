@@ -0,0 +1,92 @@
+package activitypub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+
+	"github.com/satorunooshie/Yatter/app/domain/object"
+)
+
+// Largest response accepted from remote servers
+const maxResponseSize = 1 << 20
+
+// Fetch the remote actor and store it as a remote account
+func (f *Federation) resolveActor(ctx context.Context, uri string) (*object.Account, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("actor must be an absolute http or https URL: %s", uri)
+	}
+
+	var actor Actor
+	if err := f.fetch(ctx, uri, &actor); err != nil {
+		return nil, err
+	}
+	if actor.ID != uri {
+		return nil, fmt.Errorf("actor %s has unexpected id: %s", uri, actor.ID)
+	}
+	if actor.PreferredUsername == "" || actor.Inbox == "" {
+		return nil, fmt.Errorf("actor %s lacks preferredUsername or inbox", uri)
+	}
+
+	account := &object.Account{
+		Username:    actor.PreferredUsername,
+		Domain:      u.Host,
+		URI:         &actor.ID,
+		InboxURL:    &actor.Inbox,
+		DisplayName: actor.Name,
+		Note:        actor.Summary,
+	}
+	if actor.Endpoints != nil && actor.Endpoints.SharedInbox != "" {
+		account.SharedInboxURL = &actor.Endpoints.SharedInbox
+	}
+	/* 他人の鍵を自分のものとして載せていても信用しない */
+	if actor.PublicKey != nil && actor.PublicKey.Owner == actor.ID {
+		account.PublicKey = &actor.PublicKey.PublicKeyPem
+		account.PublicKeyID = &actor.PublicKey.ID
+	}
+
+	id, err := f.dao.Account().UpsertRemote(ctx, account)
+	if err != nil {
+		return nil, err
+	}
+	account.ID = id
+	return account, nil
+}
+
+// GET the ActivityPub object into v
+func (f *Federation) fetch(ctx context.Context, uri string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", ContentType)
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_, _ = io.Copy(ioutil.Discard, io.LimitReader(resp.Body, maxResponseSize))
+		if err := resp.Body.Close(); err != nil {
+			log.Printf("[WARN] activitypub::fetch::resp.Body.Close(): %v", err)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("fetch %s: unexpected response: %s", uri, resp.Status)
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(v); err != nil {
+		return fmt.Errorf("fetch %s: %w", uri, err)
+	}
+	return nil
+}
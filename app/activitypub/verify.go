@@ -0,0 +1,111 @@
+package activitypub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/satorunooshie/Yatter/app/domain/object"
+	"github.com/satorunooshie/Yatter/app/httpsig"
+)
+
+// How far the Date of signed requests may be from now, which Mastodon also allows for clock skew
+const signatureMaxSkew = time.Hour
+
+// The request is not signed by a known actor, signed too long ago, or replayed
+var ErrUnauthorized = errors.New("unauthorized request")
+
+// Signatures already verified, kept until they get stale so that replays are rejected
+type replayCache struct {
+	mu        sync.Mutex
+	seen      map[string]time.Time
+	lastSweep time.Time
+}
+
+// Record the signature, false if it was already recorded
+func (c *replayCache) add(signature string, now time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.seen == nil {
+		c.seen = make(map[string]time.Time)
+	}
+	/* 古くなった署名は日時の確認で弾けるので忘れてよい */
+	if now.Sub(c.lastSweep) > signatureMaxSkew {
+		for k, expireAt := range c.seen {
+			if !expireAt.After(now) {
+				delete(c.seen, k)
+			}
+		}
+		c.lastSweep = now
+	}
+
+	if expireAt, ok := c.seen[signature]; ok && expireAt.After(now) {
+		return false
+	}
+	c.seen[signature] = now.Add(2 * signatureMaxSkew)
+	return true
+}
+
+// Verify the HTTP Signature of the request posted with body, returning the remote account which signed it.
+//
+// The key of an unknown actor is fetched and stored with the account, and fetched again
+// if the stored one does not verify the signature in case the actor has rotated it.
+func (f *Federation) VerifyRequest(ctx context.Context, req *http.Request, body []byte) (*object.Account, error) {
+	sig, err := httpsig.Parse(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnauthorized, err)
+	}
+	actorURI := sig.KeyID
+	if i := strings.IndexByte(actorURI, '#'); i >= 0 {
+		actorURI = actorURI[:i]
+	}
+
+	now := f.now()
+	signer, err := f.dao.Account().FindByURI(ctx, actorURI)
+	if err != nil {
+		return nil, err
+	}
+	fetched := false
+	if signer == nil || signer.PublicKey == nil {
+		if signer, err = f.resolveActor(ctx, actorURI); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrUnauthorized, err)
+		}
+		fetched = true
+	}
+
+	err = f.verify(req, body, sig, signer, now)
+	if err != nil && !fetched && errors.Is(err, httpsig.ErrInvalidSignature) {
+		if signer, err = f.resolveActor(ctx, actorURI); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrUnauthorized, err)
+		}
+		err = f.verify(req, body, sig, signer, now)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnauthorized, err)
+	}
+
+	if !f.replays.add(req.Header.Get("Signature"), now) {
+		return nil, fmt.Errorf("%w: replayed signature", ErrUnauthorized)
+	}
+	return signer, nil
+}
+
+func (f *Federation) verify(req *http.Request, body []byte, sig *httpsig.Signature, signer *object.Account, now time.Time) error {
+	if signer.PublicKey == nil {
+		return fmt.Errorf("%w: actor %s has no key", httpsig.ErrInvalidSignature, *signer.URI)
+	}
+	/* 鍵の ID が違えば、同じ actor のものでも別の鍵による署名とみなす */
+	if signer.PublicKeyID == nil || *signer.PublicKeyID != sig.KeyID {
+		return fmt.Errorf("%w: key %s is not the key of actor %s", httpsig.ErrInvalidSignature, sig.KeyID, *signer.URI)
+	}
+	key, err := httpsig.ParsePublicKey(*signer.PublicKey)
+	if err != nil {
+		return err
+	}
+	return sig.Verify(req, body, key, now, signatureMaxSkew)
+}
@@ -1,13 +1,25 @@
 package app
 
 import (
+	"github.com/satorunooshie/Yatter/app/activitypub"
 	"github.com/satorunooshie/Yatter/app/config"
 	"github.com/satorunooshie/Yatter/app/dao"
+	"github.com/satorunooshie/Yatter/app/publish"
+	"github.com/satorunooshie/Yatter/app/snowflake"
+	"github.com/satorunooshie/Yatter/app/stream"
+	"github.com/satorunooshie/Yatter/app/trend"
+	"github.com/satorunooshie/Yatter/app/webhook"
 )
 
 // Dependency manager for whole application
 type App struct {
-	Dao dao.Dao
+	Dao        dao.Dao
+	Trends     *trend.Trends
+	Publisher  *publish.Publisher
+	Scheduler  *publish.Scheduler
+	Bus        stream.Bus
+	Webhooks   *webhook.Deliverer
+	Federation *activitypub.Federation
 }
 
 // Create dependency manager
@@ -15,10 +27,30 @@ func NewApp() (*App, error) {
 	// panic if lacking something
 	daoCfg := config.MySQLConfig()
 
-	dao, err := dao.New(daoCfg)
+	ids, err := snowflake.New(config.Snowflake.Node())
 	if err != nil {
 		return nil, err
 	}
 
-	return &App{Dao: dao}, nil
+	dao, err := dao.New(daoCfg, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	trends := trend.New(dao.Tag(), config.Trends.Window(), config.Trends.HalfLife())
+
+	bus := stream.NewLocalBus(config.Streaming.Buffer())
+
+	webhooks := webhook.NewDeliverer(dao)
+
+	federation := activitypub.New(dao, config.Federation.BaseURL())
+	if config.Federation.AllowPrivateAddresses() {
+		federation.AllowPrivateAddresses()
+	}
+
+	publisher := publish.NewPublisher(dao, bus, federation)
+
+	scheduler := publish.NewScheduler(dao, publisher)
+
+	return &App{Dao: dao, Trends: trends, Publisher: publisher, Scheduler: scheduler, Bus: bus, Webhooks: webhooks, Federation: federation}, nil
 }
@@ -0,0 +1,24 @@
+package config
+
+import (
+	"strings"
+)
+
+// accessor namespace
+var Admin _admin
+
+type _admin struct{}
+
+// Check if the account is allowed to use the admin API, listed in comma-separated ADMIN_USERNAMES
+func (_admin) IsAdmin(username string) bool {
+	v, err := getString("ADMIN_USERNAMES")
+	if err != nil {
+		return false
+	}
+	for _, u := range strings.Split(v, ",") {
+		if strings.TrimSpace(u) == username {
+			return true
+		}
+	}
+	return false
+}
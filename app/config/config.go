@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"time"
 )
 
 const (
@@ -39,3 +40,15 @@ func getString(key string) (string, error) {
 	}
 	return v, nil
 }
+
+func getDuration(key string) (time.Duration, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return 0, fmt.Errorf("config:[%s] not found", key)
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, fmt.Errorf("config:[%s] should duration", key)
+	}
+	return d, nil
+}
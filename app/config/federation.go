@@ -0,0 +1,56 @@
+package config
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const defaultFederationInterval = 10 * time.Second
+
+// accessor namespace
+var Federation _federation
+
+type _federation struct{}
+
+// Read the URL the server is reachable at from other servers, which ActivityPub IDs start with
+func (_federation) BaseURL() string {
+	v, err := getString("SERVER_BASE_URL")
+	if err != nil {
+		return "http://localhost:" + strconv.Itoa(Port())
+	}
+	return strings.TrimSuffix(v, "/")
+}
+
+// Read the domain of the instance, which accounts are known as user@domain by, the host of BaseURL by default
+func (f _federation) Domain() string {
+	v, err := getString("SERVER_DOMAIN")
+	if err == nil {
+		return v
+	}
+	u, err := url.Parse(f.BaseURL())
+	if err != nil {
+		return "localhost"
+	}
+	return u.Host
+}
+
+// Read how often due activity deliveries are attempted
+func (_federation) Interval() time.Duration {
+	d, err := getDuration("FEDERATION_INTERVAL")
+	if err != nil || d <= 0 {
+		return defaultFederationInterval
+	}
+	return d
+}
+
+// Read if remote servers may be on loopback and private networks, which only development setups need
+func (_federation) AllowPrivateAddresses() bool {
+	v, err := getString("FEDERATION_ALLOW_PRIVATE_ADDRESSES")
+	if err != nil {
+		return false
+	}
+	allow, err := strconv.ParseBool(v)
+	return err == nil && allow
+}
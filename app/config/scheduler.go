@@ -0,0 +1,21 @@
+package config
+
+import (
+	"time"
+)
+
+const defaultSchedulerInterval = time.Minute
+
+// accessor namespace
+var Scheduler _scheduler
+
+type _scheduler struct{}
+
+// Read how often due scheduled statuses are published
+func (_scheduler) Interval() time.Duration {
+	d, err := getDuration("SCHEDULER_INTERVAL")
+	if err != nil || d <= 0 {
+		return defaultSchedulerInterval
+	}
+	return d
+}
@@ -0,0 +1,15 @@
+package config
+
+// accessor namespace
+var Snowflake _snowflake
+
+type _snowflake struct{}
+
+// Read the node number put in IDs, which must be unique among the servers sharing the database
+func (_snowflake) Node() int64 {
+	n, err := getInt("SNOWFLAKE_NODE")
+	if err != nil {
+		return 0
+	}
+	return int64(n)
+}
@@ -0,0 +1,33 @@
+package config
+
+import (
+	"time"
+)
+
+const (
+	defaultStreamingBuffer    = 64
+	defaultStreamingHeartbeat = 30 * time.Second
+)
+
+// accessor namespace
+var Streaming _streaming
+
+type _streaming struct{}
+
+// Read how many events are queued for a connection before it is dropped for falling behind
+func (_streaming) Buffer() int {
+	n, err := getInt("STREAMING_BUFFER")
+	if err != nil || n <= 0 {
+		return defaultStreamingBuffer
+	}
+	return n
+}
+
+// Read how often connections are pinged to keep them alive
+func (_streaming) Heartbeat() time.Duration {
+	d, err := getDuration("STREAMING_HEARTBEAT")
+	if err != nil || d <= 0 {
+		return defaultStreamingHeartbeat
+	}
+	return d
+}
@@ -0,0 +1,43 @@
+package config
+
+import (
+	"time"
+)
+
+const (
+	defaultTrendsWindow   = 24 * time.Hour
+	defaultTrendsHalfLife = 6 * time.Hour
+	defaultTrendsInterval = 5 * time.Minute
+)
+
+// accessor namespace
+var Trends _trends
+
+type _trends struct{}
+
+// Read how far back statuses are taken into account for trends
+func (_trends) Window() time.Duration {
+	d, err := getDuration("TRENDS_WINDOW")
+	if err != nil || d <= 0 {
+		return defaultTrendsWindow
+	}
+	return d
+}
+
+// Read the age at which a status weighs half as much in trends
+func (_trends) HalfLife() time.Duration {
+	d, err := getDuration("TRENDS_HALF_LIFE")
+	if err != nil || d <= 0 {
+		return defaultTrendsHalfLife
+	}
+	return d
+}
+
+// Read how often trends are refreshed
+func (_trends) Interval() time.Duration {
+	d, err := getDuration("TRENDS_INTERVAL")
+	if err != nil || d <= 0 {
+		return defaultTrendsInterval
+	}
+	return d
+}
@@ -0,0 +1,21 @@
+package config
+
+import (
+	"time"
+)
+
+const defaultWebhookInterval = 10 * time.Second
+
+// accessor namespace
+var Webhook _webhook
+
+type _webhook struct{}
+
+// Read how often due webhook deliveries are attempted
+func (_webhook) Interval() time.Duration {
+	d, err := getDuration("WEBHOOK_INTERVAL")
+	if err != nil || d <= 0 {
+		return defaultWebhookInterval
+	}
+	return d
+}
@@ -16,12 +16,13 @@ import (
 type (
 	// Implementation for repository.Account
 	account struct {
-		db *sqlx.DB
+		db  *sqlx.DB
+		ids IDGenerator
 	}
 )
 
-func NewAccount(db *sqlx.DB) repository.Account {
-	return &account{db: db}
+func NewAccount(db *sqlx.DB, ids IDGenerator) repository.Account {
+	return &account{db: db, ids: ids}
 }
 
 func (r *account) FindByID(ctx context.Context, id object.AccountID) (*object.Account, error) {
@@ -67,7 +68,7 @@ func (r *account) FindByIDs(ctx context.Context, ids []object.AccountID) ([]*obj
 
 func (r *account) FindByUsername(ctx context.Context, username string) (*object.Account, error) {
 	entity := &object.Account{}
-	if err := r.db.QueryRowxContext(ctx, "SELECT * FROM `account` WHERE `username` = ? AND `delete_at` IS NULL", username).StructScan(entity); err != nil {
+	if err := r.db.QueryRowxContext(ctx, "SELECT * FROM `account` WHERE `username` = ? AND `domain` = '' AND `delete_at` IS NULL", username).StructScan(entity); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
 		}
@@ -76,8 +77,19 @@ func (r *account) FindByUsername(ctx context.Context, username string) (*object.
 	return entity, nil
 }
 
-func (r *account) Insert(ctx context.Context, username, passwordHash string, createAt time.Time) error {
-	stmt, err := r.db.PreparexContext(ctx, "INSERT INTO `account` (`username`, `password_hash`, `create_at`) VALUES (?, ?, ?)")
+func (r *account) FindByURI(ctx context.Context, uri string) (*object.Account, error) {
+	entity := &object.Account{}
+	if err := r.db.QueryRowxContext(ctx, "SELECT * FROM `account` WHERE `uri` = ? AND `delete_at` IS NULL", uri).StructScan(entity); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return entity, nil
+}
+
+func (r *account) Insert(ctx context.Context, username, passwordHash, privateKey, publicKey string, createAt time.Time) error {
+	stmt, err := r.db.PreparexContext(ctx, "INSERT INTO `account` (`id`, `username`, `password_hash`, `private_key`, `public_key`, `create_at`) VALUES (?, ?, ?, ?, ?, ?)")
 	if err != nil {
 		return err
 	}
@@ -86,8 +98,39 @@ func (r *account) Insert(ctx context.Context, username, passwordHash string, cre
 			log.Printf("[WARN] dao::account::Insert::stmt.Close(): %v", err)
 		}
 	}()
-	if _, err := stmt.ExecContext(ctx, username, passwordHash, createAt); err != nil {
+	if _, err := stmt.ExecContext(ctx, r.ids.Next(), username, passwordHash, privateKey, publicKey, createAt); err != nil {
 		return err
 	}
 	return nil
 }
+
+func (r *account) Usage(ctx context.Context, activeMonthSince, activeHalfyearSince time.Time) (*object.InstanceUsage, error) {
+	entity := &object.InstanceUsage{}
+	if err := r.db.QueryRowxContext(ctx, "SELECT COUNT(*) AS `users`, COALESCE(SUM(`last_status_at` >= ?), 0) AS `active_month`, "+
+		"COALESCE(SUM(`last_status_at` >= ?), 0) AS `active_halfyear`, COALESCE(SUM(`statuses_count`), 0) AS `local_posts` "+
+		"FROM `account` WHERE `domain` = '' AND `delete_at` IS NULL", activeMonthSince, activeHalfyearSince).StructScan(entity); err != nil {
+		return nil, err
+	}
+	return entity, nil
+}
+
+func (r *account) UpsertRemote(ctx context.Context, a *object.Account) (object.AccountID, error) {
+	/* LAST_INSERT_ID(id) で更新時も既存の ID を返す */
+	id := r.ids.Next()
+	res, err := r.db.ExecContext(ctx, "INSERT INTO `account` (`id`, `username`, `domain`, `uri`, `inbox_url`, `shared_inbox_url`, `public_key`, `public_key_id`, `password_hash`, `display_name`, `note`) "+
+		"VALUES (?, ?, ?, ?, ?, ?, ?, ?, '', ?, ?) ON DUPLICATE KEY UPDATE `id` = LAST_INSERT_ID(`id`), `uri` = VALUES(`uri`), `inbox_url` = VALUES(`inbox_url`), "+
+		"`shared_inbox_url` = VALUES(`shared_inbox_url`), `public_key` = VALUES(`public_key`), `public_key_id` = VALUES(`public_key_id`), `display_name` = VALUES(`display_name`), `note` = VALUES(`note`)",
+		id, a.Username, a.Domain, a.URI, a.InboxURL, a.SharedInboxURL, a.PublicKey, a.PublicKeyID, a.DisplayName, a.Note)
+	if err != nil {
+		return 0, err
+	}
+	/* 挿入したときだけ影響を受けた行数が 1 になる */
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	if affected == 1 {
+		return id, nil
+	}
+	return res.LastInsertId()
+}
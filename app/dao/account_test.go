@@ -29,7 +29,8 @@ func Test_account_FindByID(t *testing.T) {
 	}()
 
 	r := &account{
-		db: db,
+		db:  db,
+		ids: &sequence{},
 	}
 
 	type args struct {
@@ -160,7 +161,8 @@ func Test_account_FindByIDs(t *testing.T) {
 	}()
 
 	r := &account{
-		db: db,
+		db:  db,
+		ids: &sequence{},
 	}
 
 	type args struct {
@@ -317,7 +319,8 @@ func Test_account_FindByUsername(t *testing.T) {
 	}()
 
 	r := &account{
-		db: db,
+		db:  db,
+		ids: &sequence{},
 	}
 
 	type args struct {
@@ -334,7 +337,7 @@ func Test_account_FindByUsername(t *testing.T) {
 		{
 			name: "ok",
 			query: func(s sqlxmock.Sqlmock) {
-				s.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `account` WHERE `username` = ? AND `delete_at` IS NULL")).
+				s.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `account` WHERE `username` = ? AND `domain` = '' AND `delete_at` IS NULL")).
 					WithArgs("名前").
 					WillReturnRows(
 						sqlxmock.NewRows(
@@ -373,7 +376,7 @@ func Test_account_FindByUsername(t *testing.T) {
 		{
 			name: "no rows",
 			query: func(s sqlxmock.Sqlmock) {
-				s.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `account` WHERE `username` = ? AND `delete_at` IS NULL")).
+				s.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `account` WHERE `username` = ? AND `domain` = '' AND `delete_at` IS NULL")).
 					WithArgs("名前").
 					WillReturnRows(
 						sqlxmock.NewRows(
@@ -401,7 +404,7 @@ func Test_account_FindByUsername(t *testing.T) {
 		{
 			name: "error",
 			query: func(s sqlxmock.Sqlmock) {
-				s.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `account` WHERE `username` = ? AND `delete_at` IS NULL")).
+				s.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `account` WHERE `username` = ? AND `domain` = '' AND `delete_at` IS NULL")).
 					WithArgs("名前").
 					WillReturnError(errors.New("error"))
 			},
@@ -444,13 +447,16 @@ func Test_account_Insert(t *testing.T) {
 	}()
 
 	r := &account{
-		db: db,
+		db:  db,
+		ids: &sequence{},
 	}
 
 	type args struct {
 		ctx          context.Context
 		username     string
 		passwordHash string
+		privateKey   string
+		publicKey    string
 		createAt     time.Time
 	}
 	tests := []struct {
@@ -462,15 +468,17 @@ func Test_account_Insert(t *testing.T) {
 		{
 			name: "ok",
 			query: func(s sqlxmock.Sqlmock) {
-				s.ExpectPrepare(regexp.QuoteMeta("INSERT INTO `account` (`username`, `password_hash`, `create_at`) VALUES (?, ?, ?)")).
+				s.ExpectPrepare(regexp.QuoteMeta("INSERT INTO `account` (`id`, `username`, `password_hash`, `private_key`, `public_key`, `create_at`) VALUES (?, ?, ?, ?, ?, ?)")).
 					ExpectExec().
-					WithArgs("名前", "hash", createAt).
+					WithArgs(sqlxmock.AnyArg(), "名前", "hash", "private", "public", createAt).
 					WillReturnResult(sqlxmock.NewResult(1, 1))
 			},
 			args: args{
 				ctx:          context.Background(),
 				username:     "名前",
 				passwordHash: "hash",
+				privateKey:   "private",
+				publicKey:    "public",
 				createAt:     createAt,
 			},
 			wantErr: false,
@@ -478,15 +486,17 @@ func Test_account_Insert(t *testing.T) {
 		{
 			name: "error",
 			query: func(s sqlxmock.Sqlmock) {
-				s.ExpectPrepare(regexp.QuoteMeta("INSERT INTO `account` (`username`, `password_hash`, `create_at`) VALUES (?, ?, ?)")).
+				s.ExpectPrepare(regexp.QuoteMeta("INSERT INTO `account` (`id`, `username`, `password_hash`, `private_key`, `public_key`, `create_at`) VALUES (?, ?, ?, ?, ?, ?)")).
 					ExpectExec().
-					WithArgs("名前", "hash", createAt).
+					WithArgs(sqlxmock.AnyArg(), "名前", "hash", "private", "public", createAt).
 					WillReturnError(errors.New("error"))
 			},
 			args: args{
 				ctx:          context.Background(),
 				username:     "名前",
 				passwordHash: "hash",
+				privateKey:   "private",
+				publicKey:    "public",
 				createAt:     createAt,
 			},
 			wantErr: true,
@@ -496,7 +506,7 @@ func Test_account_Insert(t *testing.T) {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
 			tt.query(mock)
-			if err := r.Insert(tt.args.ctx, tt.args.username, tt.args.passwordHash, tt.args.createAt); (err != nil) != tt.wantErr {
+			if err := r.Insert(tt.args.ctx, tt.args.username, tt.args.passwordHash, tt.args.privateKey, tt.args.publicKey, tt.args.createAt); (err != nil) != tt.wantErr {
 				t.Errorf("account.Insert() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
@@ -506,3 +516,85 @@ func Test_account_Insert(t *testing.T) {
 		})
 	}
 }
+
+func Test_account_Usage(t *testing.T) {
+	monthAgo, _ := time.Parse("2006-01-02", "2020-01-01")
+	halfYearAgo, _ := time.Parse("2006-01-02", "2019-07-01")
+	const query = "SELECT COUNT(*) AS `users`, COALESCE(SUM(`last_status_at` >= ?), 0) AS `active_month`, " +
+		"COALESCE(SUM(`last_status_at` >= ?), 0) AS `active_halfyear`, COALESCE(SUM(`statuses_count`), 0) AS `local_posts` " +
+		"FROM `account` WHERE `domain` = '' AND `delete_at` IS NULL"
+
+	db, mock, err := sqlxmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	r := &account{
+		db:  db,
+		ids: &sequence{},
+	}
+
+	type args struct {
+		ctx                 context.Context
+		activeMonthSince    time.Time
+		activeHalfyearSince time.Time
+	}
+	tests := []struct {
+		name    string
+		query   func(s sqlxmock.Sqlmock)
+		args    args
+		want    *object.InstanceUsage
+		wantErr bool
+	}{
+		{
+			name: "ok",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs(monthAgo, halfYearAgo).
+					WillReturnRows(sqlxmock.NewRows([]string{"users", "active_month", "active_halfyear", "local_posts"}).AddRow(10, 3, 5, 1024))
+			},
+			args: args{
+				ctx:                 context.Background(),
+				activeMonthSince:    monthAgo,
+				activeHalfyearSince: halfYearAgo,
+			},
+			want:    &object.InstanceUsage{Users: 10, ActiveMonth: 3, ActiveHalfyear: 5, LocalPosts: 1024},
+			wantErr: false,
+		},
+		{
+			name: "error",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs(monthAgo, halfYearAgo).
+					WillReturnError(errors.New("error"))
+			},
+			args: args{
+				ctx:                 context.Background(),
+				activeMonthSince:    monthAgo,
+				activeHalfyearSince: halfYearAgo,
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tt.query(mock)
+			got, err := r.Usage(tt.args.ctx, tt.args.activeMonthSince, tt.args.activeHalfyearSince)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("account.Usage() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Errorf("account.Usage() returned diff (want -> got):\n%s", diff)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
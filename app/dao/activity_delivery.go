@@ -0,0 +1,105 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/satorunooshie/Yatter/app/domain/object"
+	"github.com/satorunooshie/Yatter/app/domain/repository"
+)
+
+type (
+	// Implementation for repository.ActivityDelivery
+	activityDelivery struct {
+		db  *sqlx.DB
+		ids IDGenerator
+	}
+)
+
+func NewActivityDelivery(db *sqlx.DB, ids IDGenerator) repository.ActivityDelivery {
+	return &activityDelivery{db: db, ids: ids}
+}
+
+func (r *activityDelivery) Insert(ctx context.Context, accountID object.AccountID, activity []byte, inboxURLs []string) error {
+	if len(inboxURLs) == 0 {
+		return nil
+	}
+
+	placeholders := make([]string, 0, len(inboxURLs))
+	params := make([]interface{}, 0, len(inboxURLs)*4)
+	for _, inboxURL := range inboxURLs {
+		placeholders = append(placeholders, "(?, ?, ?, ?)")
+		params = append(params, r.ids.Next(), accountID, inboxURL, string(activity))
+	}
+
+	if _, err := r.db.ExecContext(ctx, "INSERT INTO `activity_delivery` (`id`, `account_id`, `inbox_url`, `activity`) VALUES "+strings.Join(placeholders, ", "), params...); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *activityDelivery) SelectDue(ctx context.Context, until time.Time, limit int64) ([]*object.ActivityDelivery, error) {
+	rows, err := r.db.QueryxContext(ctx, "SELECT * FROM `activity_delivery` WHERE `status` = ? AND `next_attempt_at` <= ? ORDER BY `next_attempt_at`, `id` LIMIT ?",
+		object.WebhookDeliveryPending, until, limit)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("[WARN] dao::activityDelivery::SelectDue::rows.Close(): %v", err)
+		}
+	}()
+
+	entities := make([]*object.ActivityDelivery, 0)
+	for rows.Next() {
+		entity := &object.ActivityDelivery{}
+		if err := rows.StructScan(&entity); err != nil {
+			return nil, err
+		}
+		entities = append(entities, entity)
+	}
+	return entities, nil
+}
+
+func (r *activityDelivery) Claim(ctx context.Context, id object.ActivityDeliveryID, attempts int64, leaseUntil time.Time) (bool, error) {
+	res, err := r.db.ExecContext(ctx, "UPDATE `activity_delivery` SET `attempts` = `attempts` + 1, `next_attempt_at` = ? WHERE `id` = ? AND `status` = ? AND `attempts` = ?",
+		leaseUntil, id, object.WebhookDeliveryPending, attempts)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+func (r *activityDelivery) Succeed(ctx context.Context, id object.ActivityDeliveryID) error {
+	if _, err := r.db.ExecContext(ctx, "UPDATE `activity_delivery` SET `status` = ?, `error` = NULL, `delivered_at` = NOW() WHERE `id` = ?",
+		object.WebhookDeliverySucceeded, id); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *activityDelivery) Fail(ctx context.Context, id object.ActivityDeliveryID, reason string, nextAttemptAt *time.Time) error {
+	status := object.WebhookDeliveryPending
+	if nextAttemptAt == nil {
+		status = object.WebhookDeliveryFailed
+	}
+	if _, err := r.db.ExecContext(ctx, "UPDATE `activity_delivery` SET `status` = ?, `next_attempt_at` = COALESCE(?, `next_attempt_at`), `error` = ? WHERE `id` = ?",
+		status, nextAttemptAt, reason, id); err != nil {
+		return err
+	}
+	return nil
+}
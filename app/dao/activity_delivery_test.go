@@ -0,0 +1,97 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	sqlxmock "github.com/zhashkevych/go-sqlxmock"
+
+	"github.com/satorunooshie/Yatter/app/domain/object"
+)
+
+func Test_activityDelivery_Insert(t *testing.T) {
+	db, mock, err := sqlxmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	r := &activityDelivery{
+		db:  db,
+		ids: &sequence{},
+	}
+
+	activity := []byte(`{"type":"Create"}`)
+
+	type args struct {
+		ctx       context.Context
+		accountID object.AccountID
+		activity  []byte
+		inboxURLs []string
+	}
+	tests := []struct {
+		name    string
+		query   func(s sqlxmock.Sqlmock)
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "inboxes",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectExec(regexp.QuoteMeta("INSERT INTO `activity_delivery` (`id`, `account_id`, `inbox_url`, `activity`) VALUES (?, ?, ?, ?), (?, ?, ?, ?)")).
+					WithArgs(1, 1, "https://a.example/inbox", string(activity), 2, 1, "https://b.example/inbox", string(activity)).
+					WillReturnResult(sqlxmock.NewResult(1, 2))
+			},
+			args: args{
+				ctx:       context.Background(),
+				accountID: 1,
+				activity:  activity,
+				inboxURLs: []string{"https://a.example/inbox", "https://b.example/inbox"},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "no inboxes",
+			query: func(s sqlxmock.Sqlmock) {},
+			args: args{
+				ctx:       context.Background(),
+				accountID: 1,
+				activity:  activity,
+				inboxURLs: nil,
+			},
+			wantErr: false,
+		},
+		{
+			name: "error",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectExec(regexp.QuoteMeta("INSERT INTO `activity_delivery` (`id`, `account_id`, `inbox_url`, `activity`) VALUES (?, ?, ?, ?)")).
+					WithArgs(3, 1, "https://a.example/inbox", string(activity)).
+					WillReturnError(errors.New("error"))
+			},
+			args: args{
+				ctx:       context.Background(),
+				accountID: 1,
+				activity:  activity,
+				inboxURLs: []string{"https://a.example/inbox"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tt.query(mock)
+			if err := r.Insert(tt.args.ctx, tt.args.accountID, tt.args.activity, tt.args.inboxURLs); (err != nil) != tt.wantErr {
+				t.Errorf("activityDelivery.Insert() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
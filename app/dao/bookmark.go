@@ -0,0 +1,80 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/satorunooshie/Yatter/app/domain/object"
+	"github.com/satorunooshie/Yatter/app/domain/repository"
+)
+
+type (
+	// Implementation for repository.Bookmark
+	bookmark struct {
+		db  *sqlx.DB
+		ids IDGenerator
+	}
+)
+
+func NewBookmark(db *sqlx.DB, ids IDGenerator) repository.Bookmark {
+	return &bookmark{db: db, ids: ids}
+}
+
+func (r *bookmark) Select(ctx context.Context, accountID object.AccountID, page object.Page) ([]*object.Bookmark, error) {
+	rows, err := r.db.QueryxContext(ctx, "SELECT `b`.* FROM `bookmark` AS `b` INNER JOIN `status` AS `s` ON `s`.`id` = `b`.`status_id` "+
+		"WHERE `b`.`account_id` = ? AND `b`.`id` > ? AND `b`.`id` < ? AND `s`.`delete_at` IS NULL ORDER BY `b`.`id` "+order(page)+" LIMIT ?", accountID, page.MinID, page.MaxID, page.Limit)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("[WARN] dao::bookmark::Select::rows.Close(): %v", err)
+		}
+	}()
+
+	entities := make([]*object.Bookmark, 0, page.Limit)
+	for rows.Next() {
+		entity := &object.Bookmark{}
+		if err := rows.StructScan(&entity); err != nil {
+			return nil, err
+		}
+		entities = append(entities, entity)
+	}
+	newestFirst(page, entities)
+	return entities, nil
+}
+
+func (r *bookmark) FindBookmarked(ctx context.Context, accountID object.AccountID, statusIDs []object.StatusID) ([]object.StatusID, error) {
+	query, params, err := sqlx.In("SELECT `status_id` FROM `bookmark` WHERE `account_id` = ? AND `status_id` IN (?)", accountID, statusIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]object.StatusID, 0)
+	if err := r.db.SelectContext(ctx, &ids, query, params...); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (r *bookmark) Insert(ctx context.Context, accountID object.AccountID, statusID object.StatusID) error {
+	if _, err := r.db.ExecContext(ctx, "INSERT IGNORE INTO `bookmark` (`id`, `account_id`, `status_id`) VALUES (?, ?, ?)", r.ids.Next(), accountID, statusID); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *bookmark) Delete(ctx context.Context, accountID object.AccountID, statusID object.StatusID) error {
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM `bookmark` WHERE `account_id` = ? AND `status_id` = ?", accountID, statusID); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,217 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	sqlxmock "github.com/zhashkevych/go-sqlxmock"
+
+	"github.com/satorunooshie/Yatter/app/domain/object"
+)
+
+func Test_bookmark_Select(t *testing.T) {
+	createAt, _ := time.Parse("2006-01-02", "2020-01-01")
+	const query = "SELECT `b`.* FROM `bookmark` AS `b` INNER JOIN `status` AS `s` ON `s`.`id` = `b`.`status_id` " +
+		"WHERE `b`.`account_id` = ? AND `b`.`id` > ? AND `b`.`id` < ? AND `s`.`delete_at` IS NULL ORDER BY `b`.`id` %s LIMIT ?"
+
+	db, mock, err := sqlxmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	r := &bookmark{
+		db:  db,
+		ids: &sequence{},
+	}
+
+	type args struct {
+		ctx       context.Context
+		accountID object.AccountID
+		page      object.Page
+	}
+	tests := []struct {
+		name    string
+		query   func(s sqlxmock.Sqlmock)
+		args    args
+		want    []*object.Bookmark
+		wantErr bool
+	}{
+		{
+			name: "ok",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(query, "DESC"))).
+					WithArgs(1, 0, math.MaxInt64, 2).
+					WillReturnRows(
+						sqlxmock.NewRows(
+							[]string{
+								"id",
+								"account_id",
+								"status_id",
+								"create_at",
+							},
+						).
+							AddRow(5, 1, 3, createAt).
+							AddRow(4, 1, 10, createAt),
+					)
+			},
+			args: args{
+				ctx:       context.Background(),
+				accountID: 1,
+				page:      object.Page{MinID: 0, MaxID: math.MaxInt64, Limit: 2},
+			},
+			want: []*object.Bookmark{
+				{ID: 5, AccountID: 1, StatusID: 3, CreateAt: object.DateTime{Time: createAt}},
+				{ID: 4, AccountID: 1, StatusID: 10, CreateAt: object.DateTime{Time: createAt}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "ascending",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(query, "ASC"))).
+					WithArgs(1, 3, math.MaxInt64, 2).
+					WillReturnRows(
+						sqlxmock.NewRows(
+							[]string{
+								"id",
+								"account_id",
+								"status_id",
+								"create_at",
+							},
+						).
+							AddRow(4, 1, 10, createAt).
+							AddRow(5, 1, 3, createAt),
+					)
+			},
+			args: args{
+				ctx:       context.Background(),
+				accountID: 1,
+				page:      object.Page{MinID: 3, MaxID: math.MaxInt64, Limit: 2, Ascending: true},
+			},
+			want: []*object.Bookmark{
+				{ID: 5, AccountID: 1, StatusID: 3, CreateAt: object.DateTime{Time: createAt}},
+				{ID: 4, AccountID: 1, StatusID: 10, CreateAt: object.DateTime{Time: createAt}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "error",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectQuery(regexp.QuoteMeta(fmt.Sprintf(query, "DESC"))).
+					WithArgs(1, 0, math.MaxInt64, 2).
+					WillReturnError(errors.New("error"))
+			},
+			args: args{
+				ctx:       context.Background(),
+				accountID: 1,
+				page:      object.Page{MinID: 0, MaxID: math.MaxInt64, Limit: 2},
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tt.query(mock)
+			got, err := r.Select(tt.args.ctx, tt.args.accountID, tt.args.page)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("bookmark.Select() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Errorf("bookmark.Select() returned diff (want -> got):\n%s", diff)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func Test_bookmark_FindBookmarked(t *testing.T) {
+	const query = "SELECT `status_id` FROM `bookmark` WHERE `account_id` = ? AND `status_id` IN (?, ?, ?)"
+
+	db, mock, err := sqlxmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	r := &bookmark{
+		db:  db,
+		ids: &sequence{},
+	}
+
+	type args struct {
+		ctx       context.Context
+		accountID object.AccountID
+		statusIDs []object.StatusID
+	}
+	tests := []struct {
+		name    string
+		query   func(s sqlxmock.Sqlmock)
+		args    args
+		want    []object.StatusID
+		wantErr bool
+	}{
+		{
+			name: "ok",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs(1, 1, 2, 3).
+					WillReturnRows(sqlxmock.NewRows([]string{"status_id"}).AddRow(1).AddRow(3))
+			},
+			args: args{
+				ctx:       context.Background(),
+				accountID: 1,
+				statusIDs: []object.StatusID{1, 2, 3},
+			},
+			want:    []object.StatusID{1, 3},
+			wantErr: false,
+		},
+		{
+			name: "error",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs(1, 1, 2, 3).
+					WillReturnError(errors.New("error"))
+			},
+			args: args{
+				ctx:       context.Background(),
+				accountID: 1,
+				statusIDs: []object.StatusID{1, 2, 3},
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tt.query(mock)
+			got, err := r.FindBookmarked(tt.args.ctx, tt.args.accountID, tt.args.statusIDs)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("bookmark.FindBookmarked() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Errorf("bookmark.FindBookmarked() returned diff (want -> got):\n%s", diff)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
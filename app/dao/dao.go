@@ -15,39 +15,110 @@ type (
 		Account() repository.Account
 		Status() repository.Status
 		MediaAttachment() repository.MediaAttachment
+		Relationship() repository.Relationship
+		Mention() repository.Mention
+		Tag() repository.Tag
+		StatusEdit() repository.StatusEdit
+		Poll() repository.Poll
+		ScheduledStatus() repository.ScheduledStatus
+		Bookmark() repository.Bookmark
+		PinnedStatus() repository.PinnedStatus
+		Notification() repository.Notification
+		Webhook() repository.Webhook
+		WebhookDelivery() repository.WebhookDelivery
+		ActivityDelivery() repository.ActivityDelivery
+		Search() repository.Search
 
 		// Clear all data in DB
 		InitAll() error
 	}
 
+	// Generator of the IDs of new rows, which are ordered by the time they are inserted at
+	IDGenerator interface {
+		Next() int64
+	}
+
 	// Implementation for DAO
 	dao struct {
-		db *sqlx.DB
+		db  *sqlx.DB
+		ids IDGenerator
 	}
 )
 
 // Create DAO
-func New(config DBConfig) (Dao, error) {
+func New(config DBConfig, ids IDGenerator) (Dao, error) {
 	db, err := initDb(config)
 	if err != nil {
 		return nil, err
 	}
 
-	return &dao{db: db}, nil
+	return &dao{db: db, ids: ids}, nil
 }
 
 func (d *dao) Account() repository.Account {
-	return NewAccount(d.db)
+	return NewAccount(d.db, d.ids)
 }
 
 func (d *dao) Status() repository.Status {
-	return NewStatus(d.db)
+	return NewStatus(d.db, d.ids)
 }
 
 func (d *dao) MediaAttachment() repository.MediaAttachment {
 	return NewMediaAttachment(d.db)
 }
 
+func (d *dao) Relationship() repository.Relationship {
+	return NewRelationship(d.db, d.ids)
+}
+
+func (d *dao) Mention() repository.Mention {
+	return NewMention(d.db, d.ids)
+}
+
+func (d *dao) Tag() repository.Tag {
+	return NewTag(d.db, d.ids)
+}
+
+func (d *dao) StatusEdit() repository.StatusEdit {
+	return NewStatusEdit(d.db)
+}
+
+func (d *dao) Poll() repository.Poll {
+	return NewPoll(d.db, d.ids)
+}
+
+func (d *dao) ScheduledStatus() repository.ScheduledStatus {
+	return NewScheduledStatus(d.db, d.ids)
+}
+
+func (d *dao) Bookmark() repository.Bookmark {
+	return NewBookmark(d.db, d.ids)
+}
+
+func (d *dao) PinnedStatus() repository.PinnedStatus {
+	return NewPinnedStatus(d.db, d.ids)
+}
+
+func (d *dao) Notification() repository.Notification {
+	return NewNotification(d.db, d.ids)
+}
+
+func (d *dao) Webhook() repository.Webhook {
+	return NewWebhook(d.db, d.ids)
+}
+
+func (d *dao) WebhookDelivery() repository.WebhookDelivery {
+	return NewWebhookDelivery(d.db, d.ids)
+}
+
+func (d *dao) ActivityDelivery() repository.ActivityDelivery {
+	return NewActivityDelivery(d.db, d.ids)
+}
+
+func (d *dao) Search() repository.Search {
+	return NewSearch(d.db)
+}
+
 func (d *dao) InitAll() error {
 	if err := d.exec("SET FOREIGN_KEY_CHECKS=0"); err != nil {
 		return fmt.Errorf("Can't disable FOREIGN_KEY_CHECKS: %w", err)
@@ -60,7 +131,7 @@ func (d *dao) InitAll() error {
 		}
 	}()
 
-	for _, table := range []string{"account", "status"} {
+	for _, table := range []string{"account", "status", "media_attachment", "mention", "tag", "status_tag", "status_edit", "poll", "poll_option", "poll_vote", "scheduled_status", "bookmark", "pinned_status", "notification", "webhook", "webhook_delivery", "activity_delivery"} {
 		if err := d.exec("TRUNCATE TABLE " + table); err != nil {
 			return fmt.Errorf("Can't truncate table "+table+": %w", err)
 		}
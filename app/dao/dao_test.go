@@ -0,0 +1,11 @@
+package dao
+
+// IDGenerator generating 1, 2, 3... in tests
+type sequence struct {
+	last int64
+}
+
+func (s *sequence) Next() int64 {
+	s.last++
+	return s.last
+}
@@ -23,6 +23,37 @@ func NewMediaAttachment(db *sqlx.DB) repository.MediaAttachment {
 	return &mediaAttachment{db: db}
 }
 
+func (r *mediaAttachment) FindByIDs(ctx context.Context, ids []object.MediaAttachmentID) ([]*object.MediaAttachment, error) {
+	query, params, err := sqlx.In("SELECT * FROM `media_attachment` WHERE `id` IN (?)", ids)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.db.QueryxContext(ctx, query, params...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("[WARN] dao::media_attachment::FindByIDs::rows.Close(): %v", err)
+		}
+	}()
+
+	entities := make([]*object.MediaAttachment, 0, len(ids))
+	for rows.Next() {
+		entity := &object.MediaAttachment{}
+		if err := rows.StructScan(&entity); err != nil {
+			return nil, err
+		}
+		entity.SetMediaType()
+		entities = append(entities, entity)
+	}
+	return entities, nil
+}
+
 func (r *mediaAttachment) FindByStatusIDs(ctx context.Context, statusIDs []object.StatusID) ([]*object.MediaAttachment, error) {
 	query, params, err := sqlx.In("SELECT * FROM `media_attachment` WHERE `status_id` IN (?) AND `delete_at` IS NULL", statusIDs)
 	if err != nil {
@@ -54,9 +85,14 @@ func (r *mediaAttachment) FindByStatusIDs(ctx context.Context, statusIDs []objec
 	return entities, nil
 }
 
-func (r *mediaAttachment) Select(ctx context.Context, minID, maxID, limit int64) ([]*object.MediaAttachment, error) {
-
-	rows, err := r.db.QueryxContext(ctx, "SELECT * FROM `media_attachment` WHERE `status_id` BETWEEN ? AND ? AND `delete_at` IS NULL ORDER BY `create_at` DESC LIMIT ?", minID, maxID, limit)
+func (r *mediaAttachment) Select(ctx context.Context, page object.Page) ([]*object.MediaAttachment, error) {
+	/* 添付ファイルがページをまたがないようにステータス単位で LIMIT する */
+	query := "SELECT `m`.* FROM `media_attachment` AS `m` INNER JOIN (" +
+		"SELECT DISTINCT `a`.`status_id` FROM `media_attachment` AS `a` INNER JOIN `status` AS `s` ON `s`.`id` = `a`.`status_id` " +
+		"WHERE `a`.`status_id` > ? AND `a`.`status_id` < ? AND `s`.`visibility` = ? AND `a`.`delete_at` IS NULL AND `s`.`delete_at` IS NULL " +
+		"ORDER BY `a`.`status_id` " + order(page) + " LIMIT ?" +
+		") AS `p` ON `p`.`status_id` = `m`.`status_id` WHERE `m`.`delete_at` IS NULL ORDER BY `m`.`status_id` DESC, `m`.`id`"
+	rows, err := r.db.QueryxContext(ctx, query, page.MinID, page.MaxID, object.VisibilityPublic, page.Limit)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
@@ -70,7 +106,7 @@ func (r *mediaAttachment) Select(ctx context.Context, minID, maxID, limit int64)
 		}
 	}()
 
-	entities := make([]*object.MediaAttachment, 0, limit)
+	entities := make([]*object.MediaAttachment, 0, page.Limit)
 	for rows.Next() {
 		entity := &object.MediaAttachment{}
 		if err := rows.StructScan(&entity); err != nil {
@@ -81,3 +117,24 @@ func (r *mediaAttachment) Select(ctx context.Context, minID, maxID, limit int64)
 	}
 	return entities, nil
 }
+
+func (r *mediaAttachment) Detach(ctx context.Context, statusID object.StatusID, keepIDs []object.MediaAttachmentID) error {
+	return detachMedia(ctx, r.db, statusID, keepIDs)
+}
+
+// Detach media attachments with db or in a transaction
+func detachMedia(ctx context.Context, ext sqlx.ExecerContext, statusID object.StatusID, keepIDs []object.MediaAttachmentID) error {
+	query, params := "UPDATE `media_attachment` SET `delete_at` = NOW() WHERE `status_id` = ? AND `delete_at` IS NULL", []interface{}{statusID}
+	if len(keepIDs) != 0 {
+		var err error
+		query, params, err = sqlx.In(query+" AND `id` NOT IN (?)", statusID, keepIDs)
+		if err != nil {
+			return err
+		}
+	}
+
+	if _, err := ext.ExecContext(ctx, query, params...); err != nil {
+		return err
+	}
+	return nil
+}
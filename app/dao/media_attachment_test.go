@@ -166,6 +166,11 @@ func Test_mediaAttachment_FindByStatusIDs(t *testing.T) {
 func Test_mediaAttachment_Select(t *testing.T) {
 	mediaType := "image"
 	createAt, _ := time.Parse("2012-01-02", "2020-01-01")
+	const query = "SELECT `m`.* FROM `media_attachment` AS `m` INNER JOIN (" +
+		"SELECT DISTINCT `a`.`status_id` FROM `media_attachment` AS `a` INNER JOIN `status` AS `s` ON `s`.`id` = `a`.`status_id` " +
+		"WHERE `a`.`status_id` > ? AND `a`.`status_id` < ? AND `s`.`visibility` = ? AND `a`.`delete_at` IS NULL AND `s`.`delete_at` IS NULL " +
+		"ORDER BY `a`.`status_id` DESC LIMIT ?" +
+		") AS `p` ON `p`.`status_id` = `m`.`status_id` WHERE `m`.`delete_at` IS NULL ORDER BY `m`.`status_id` DESC, `m`.`id`"
 
 	db, mock, err := sqlxmock.Newx()
 	if err != nil {
@@ -180,10 +185,8 @@ func Test_mediaAttachment_Select(t *testing.T) {
 	}
 
 	type args struct {
-		ctx   context.Context
-		minID object.StatusID
-		maxID object.StatusID
-		limit int64
+		ctx  context.Context
+		page object.Page
 	}
 	tests := []struct {
 		name    string
@@ -195,8 +198,8 @@ func Test_mediaAttachment_Select(t *testing.T) {
 		{
 			name: "ok",
 			query: func(s sqlxmock.Sqlmock) {
-				s.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `media_attachment` WHERE `status_id` BETWEEN ? AND ? AND `delete_at` IS NULL ORDER BY `create_at` DESC LIMIT ?")).
-					WithArgs(1, 100, 2).
+				s.ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs(1, 100, object.VisibilityPublic, 2).
 					WillReturnRows(
 						sqlxmock.NewRows(
 							[]string{
@@ -209,34 +212,32 @@ func Test_mediaAttachment_Select(t *testing.T) {
 								"delete_at",
 							},
 						).
-							AddRow(1, 1, object.TypeImage, "http://example.com/1", "description1", createAt, nil).
-							AddRow(2, 2, object.TypeImage, "http://example.com/2", "description2", createAt, nil),
+							AddRow(2, 2, object.TypeImage, "http://example.com/2", "description2", createAt, nil).
+							AddRow(1, 1, object.TypeImage, "http://example.com/1", "description1", createAt, nil),
 					)
 			},
 			args: args{
-				ctx:   context.Background(),
-				minID: 1,
-				maxID: 100,
-				limit: 2,
+				ctx:  context.Background(),
+				page: object.Page{MinID: 1, MaxID: 100, Limit: 2},
 			},
 			want: []*object.MediaAttachment{
 				{
-					ID:          1,
-					StatusID:    1,
+					ID:          2,
+					StatusID:    2,
 					Type:        object.TypeImage,
-					URL:         "http://example.com/1",
-					Description: "description1",
+					URL:         "http://example.com/2",
+					Description: "description2",
 					CreateAt:    object.DateTime{Time: createAt},
 					DeleteAt:    nil,
 
 					MediaType: &mediaType,
 				},
 				{
-					ID:          2,
-					StatusID:    2,
+					ID:          1,
+					StatusID:    1,
 					Type:        object.TypeImage,
-					URL:         "http://example.com/2",
-					Description: "description2",
+					URL:         "http://example.com/1",
+					Description: "description1",
 					CreateAt:    object.DateTime{Time: createAt},
 					DeleteAt:    nil,
 
@@ -247,8 +248,8 @@ func Test_mediaAttachment_Select(t *testing.T) {
 		{
 			name: "no rows",
 			query: func(s sqlxmock.Sqlmock) {
-				s.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `media_attachment` WHERE `status_id` BETWEEN ? AND ? AND `delete_at` IS NULL ORDER BY `create_at` DESC LIMIT ?")).
-					WithArgs(1, 100, 2).
+				s.ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs(1, 100, object.VisibilityPublic, 2).
 					WillReturnRows(
 						sqlxmock.NewRows(
 							[]string{
@@ -264,10 +265,8 @@ func Test_mediaAttachment_Select(t *testing.T) {
 					)
 			},
 			args: args{
-				ctx:   context.Background(),
-				minID: 1,
-				maxID: 100,
-				limit: 2,
+				ctx:  context.Background(),
+				page: object.Page{MinID: 1, MaxID: 100, Limit: 2},
 			},
 			want:    []*object.MediaAttachment{},
 			wantErr: false,
@@ -275,15 +274,13 @@ func Test_mediaAttachment_Select(t *testing.T) {
 		{
 			name: "error",
 			query: func(s sqlxmock.Sqlmock) {
-				s.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `media_attachment` WHERE `status_id` BETWEEN ? AND ? AND `delete_at` IS NULL ORDER BY `create_at` DESC LIMIT ?")).
-					WithArgs(1, 100, 2).
+				s.ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs(1, 100, object.VisibilityPublic, 2).
 					WillReturnError(errors.New("error"))
 			},
 			args: args{
-				ctx:   context.Background(),
-				minID: 1,
-				maxID: 100,
-				limit: 2,
+				ctx:  context.Background(),
+				page: object.Page{MinID: 1, MaxID: 100, Limit: 2},
 			},
 			want:    nil,
 			wantErr: true,
@@ -293,7 +290,7 @@ func Test_mediaAttachment_Select(t *testing.T) {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
 			tt.query(mock)
-			got, err := r.Select(tt.args.ctx, tt.args.minID, tt.args.maxID, tt.args.limit)
+			got, err := r.Select(tt.args.ctx, tt.args.page)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("mediaAttachment.Select() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -307,3 +304,187 @@ func Test_mediaAttachment_Select(t *testing.T) {
 		})
 	}
 }
+
+func Test_mediaAttachment_Detach(t *testing.T) {
+	db, mock, err := sqlxmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	r := &mediaAttachment{
+		db: db,
+	}
+
+	type args struct {
+		ctx      context.Context
+		statusID object.StatusID
+		keepIDs  []object.MediaAttachmentID
+	}
+	tests := []struct {
+		name    string
+		query   func(s sqlxmock.Sqlmock)
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "keep some",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectExec(regexp.QuoteMeta("UPDATE `media_attachment` SET `delete_at` = NOW() WHERE `status_id` = ? AND `delete_at` IS NULL AND `id` NOT IN (?, ?)")).
+					WithArgs(1, 2, 3).
+					WillReturnResult(sqlxmock.NewResult(0, 1))
+			},
+			args: args{
+				ctx:      context.Background(),
+				statusID: 1,
+				keepIDs:  []object.MediaAttachmentID{2, 3},
+			},
+			wantErr: false,
+		},
+		{
+			name: "keep none",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectExec(regexp.QuoteMeta("UPDATE `media_attachment` SET `delete_at` = NOW() WHERE `status_id` = ? AND `delete_at` IS NULL")).
+					WithArgs(1).
+					WillReturnResult(sqlxmock.NewResult(0, 3))
+			},
+			args: args{
+				ctx:      context.Background(),
+				statusID: 1,
+				keepIDs:  []object.MediaAttachmentID{},
+			},
+			wantErr: false,
+		},
+		{
+			name: "error",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectExec(regexp.QuoteMeta("UPDATE `media_attachment` SET `delete_at` = NOW() WHERE `status_id` = ? AND `delete_at` IS NULL")).
+					WithArgs(1).
+					WillReturnError(errors.New("error"))
+			},
+			args: args{
+				ctx:      context.Background(),
+				statusID: 1,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tt.query(mock)
+			if err := r.Detach(tt.args.ctx, tt.args.statusID, tt.args.keepIDs); (err != nil) != tt.wantErr {
+				t.Errorf("mediaAttachment.Detach() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func Test_mediaAttachment_FindByIDs(t *testing.T) {
+	createAt, _ := time.Parse("2006-01-02", "2020-01-01")
+	deleteAt := object.DateTime{Time: createAt}
+	mediaType := "image"
+
+	db, mock, err := sqlxmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	r := &mediaAttachment{
+		db: db,
+	}
+
+	type args struct {
+		ctx context.Context
+		ids []object.MediaAttachmentID
+	}
+	tests := []struct {
+		name    string
+		query   func(s sqlxmock.Sqlmock)
+		args    args
+		want    []*object.MediaAttachment
+		wantErr bool
+	}{
+		{
+			name: "including deleted",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `media_attachment` WHERE `id` IN (?, ?)")).
+					WithArgs(1, 2).
+					WillReturnRows(
+						sqlxmock.NewRows([]string{"id", "status_id", "type", "url", "description", "create_at", "delete_at"}).
+							AddRow(1, 1, object.TypeImage, "http://example.com/1", "description1", createAt, nil).
+							AddRow(2, 1, object.TypeImage, "http://example.com/2", "description2", createAt, createAt),
+					)
+			},
+			args: args{
+				ctx: context.Background(),
+				ids: []object.MediaAttachmentID{1, 2},
+			},
+			want: []*object.MediaAttachment{
+				{
+					ID:          1,
+					StatusID:    1,
+					Type:        object.TypeImage,
+					URL:         "http://example.com/1",
+					Description: "description1",
+					CreateAt:    object.DateTime{Time: createAt},
+					DeleteAt:    nil,
+
+					MediaType: &mediaType,
+				},
+				{
+					ID:          2,
+					StatusID:    1,
+					Type:        object.TypeImage,
+					URL:         "http://example.com/2",
+					Description: "description2",
+					CreateAt:    object.DateTime{Time: createAt},
+					DeleteAt:    &deleteAt,
+
+					MediaType: &mediaType,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "error",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `media_attachment` WHERE `id` IN (?, ?)")).
+					WithArgs(1, 2).
+					WillReturnError(errors.New("error"))
+			},
+			args: args{
+				ctx: context.Background(),
+				ids: []object.MediaAttachmentID{1, 2},
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tt.query(mock)
+			got, err := r.FindByIDs(tt.args.ctx, tt.args.ids)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("mediaAttachment.FindByIDs() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Errorf("mediaAttachment.FindByIDs() returned diff (want -> got):\n%s", diff)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
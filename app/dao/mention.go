@@ -0,0 +1,88 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/satorunooshie/Yatter/app/domain/object"
+	"github.com/satorunooshie/Yatter/app/domain/repository"
+)
+
+type (
+	// Implementation for repository.Mention
+	mention struct {
+		db  *sqlx.DB
+		ids IDGenerator
+	}
+)
+
+func NewMention(db *sqlx.DB, ids IDGenerator) repository.Mention {
+	return &mention{db: db, ids: ids}
+}
+
+func (r *mention) FindByStatusIDs(ctx context.Context, statusIDs []object.StatusID) ([]*object.Mention, error) {
+	query, params, err := sqlx.In("SELECT `m`.`id`, `m`.`status_id`, `m`.`account_id`, `a`.`username`, `m`.`create_at` FROM `mention` AS `m` "+
+		"INNER JOIN `account` AS `a` ON `a`.`id` = `m`.`account_id` WHERE `m`.`status_id` IN (?) AND `a`.`delete_at` IS NULL ORDER BY `m`.`id`", statusIDs)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.db.QueryxContext(ctx, query, params...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("[WARN] dao::mention::FindByStatusIDs::rows.Close(): %v", err)
+		}
+	}()
+
+	entities := make([]*object.Mention, 0, len(statusIDs))
+	for rows.Next() {
+		entity := &object.Mention{}
+		if err := rows.StructScan(&entity); err != nil {
+			return nil, err
+		}
+		entity.SetURL()
+		entities = append(entities, entity)
+	}
+	return entities, nil
+}
+
+func (r *mention) Insert(ctx context.Context, statusID object.StatusID, accountIDs []object.AccountID) error {
+	return insertMentions(ctx, r.db, r.ids, statusID, accountIDs)
+}
+
+func (r *mention) Delete(ctx context.Context, statusID object.StatusID) error {
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM `mention` WHERE `status_id` = ?", statusID); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Insert mentions with db or in a transaction
+func insertMentions(ctx context.Context, ext sqlx.ExecerContext, ids IDGenerator, statusID object.StatusID, accountIDs []object.AccountID) error {
+	if len(accountIDs) == 0 {
+		return nil
+	}
+
+	placeholders := make([]string, 0, len(accountIDs))
+	params := make([]interface{}, 0, len(accountIDs)*3)
+	for _, accountID := range accountIDs {
+		placeholders = append(placeholders, "(?, ?, ?)")
+		params = append(params, ids.Next(), statusID, accountID)
+	}
+
+	if _, err := ext.ExecContext(ctx, "INSERT INTO `mention` (`id`, `status_id`, `account_id`) VALUES "+strings.Join(placeholders, ", "), params...); err != nil {
+		return err
+	}
+	return nil
+}
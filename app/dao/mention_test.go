@@ -0,0 +1,198 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	sqlxmock "github.com/zhashkevych/go-sqlxmock"
+
+	"github.com/satorunooshie/Yatter/app/domain/object"
+)
+
+func Test_mention_FindByStatusIDs(t *testing.T) {
+	createAt, _ := time.Parse("2006-01-02", "2020-01-01")
+	const query = "SELECT `m`.`id`, `m`.`status_id`, `m`.`account_id`, `a`.`username`, `m`.`create_at` FROM `mention` AS `m` " +
+		"INNER JOIN `account` AS `a` ON `a`.`id` = `m`.`account_id` WHERE `m`.`status_id` IN (?, ?) AND `a`.`delete_at` IS NULL ORDER BY `m`.`id`"
+
+	db, mock, err := sqlxmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	r := &mention{
+		db: db,
+	}
+
+	type args struct {
+		ctx       context.Context
+		statusIDs []object.StatusID
+	}
+	tests := []struct {
+		name    string
+		query   func(s sqlxmock.Sqlmock)
+		args    args
+		want    []*object.Mention
+		wantErr bool
+	}{
+		{
+			name: "ok",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs(1, 2).
+					WillReturnRows(
+						sqlxmock.NewRows(
+							[]string{
+								"id",
+								"status_id",
+								"account_id",
+								"username",
+								"create_at",
+							},
+						).
+							AddRow(1, 1, 10, "john", createAt).
+							AddRow(2, 2, 11, "名前", createAt),
+					)
+			},
+			args: args{
+				ctx:       context.Background(),
+				statusIDs: []object.StatusID{1, 2},
+			},
+			want: []*object.Mention{
+				{
+					ID:        1,
+					StatusID:  1,
+					AccountID: 10,
+					Username:  "john",
+					CreateAt:  object.DateTime{Time: createAt},
+					URL:       "/v1/accounts/john",
+				},
+				{
+					ID:        2,
+					StatusID:  2,
+					AccountID: 11,
+					Username:  "名前",
+					CreateAt:  object.DateTime{Time: createAt},
+					URL:       "/v1/accounts/名前",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "error",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs(1, 2).
+					WillReturnError(errors.New("error"))
+			},
+			args: args{
+				ctx:       context.Background(),
+				statusIDs: []object.StatusID{1, 2},
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tt.query(mock)
+			got, err := r.FindByStatusIDs(tt.args.ctx, tt.args.statusIDs)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("mention.FindByStatusIDs() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Errorf("mention.FindByStatusIDs() returned diff (want -> got):\n%s", diff)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func Test_mention_Insert(t *testing.T) {
+	db, mock, err := sqlxmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	r := &mention{
+		db:  db,
+		ids: &sequence{},
+	}
+
+	type args struct {
+		ctx        context.Context
+		statusID   object.StatusID
+		accountIDs []object.AccountID
+	}
+	tests := []struct {
+		name    string
+		query   func(s sqlxmock.Sqlmock)
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "ok",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectExec(regexp.QuoteMeta("INSERT INTO `mention` (`id`, `status_id`, `account_id`) VALUES (?, ?, ?), (?, ?, ?)")).
+					WithArgs(1, 1, 10, 2, 1, 11).
+					WillReturnResult(sqlxmock.NewResult(1, 2))
+			},
+			args: args{
+				ctx:        context.Background(),
+				statusID:   1,
+				accountIDs: []object.AccountID{10, 11},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "empty",
+			query: func(s sqlxmock.Sqlmock) {},
+			args: args{
+				ctx:        context.Background(),
+				statusID:   1,
+				accountIDs: nil,
+			},
+			wantErr: false,
+		},
+		{
+			name: "error",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectExec(regexp.QuoteMeta("INSERT INTO `mention` (`id`, `status_id`, `account_id`) VALUES (?, ?, ?)")).
+					WithArgs(3, 1, 10).
+					WillReturnError(errors.New("error"))
+			},
+			args: args{
+				ctx:        context.Background(),
+				statusID:   1,
+				accountIDs: []object.AccountID{10},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tt.query(mock)
+			if err := r.Insert(tt.args.ctx, tt.args.statusID, tt.args.accountIDs); (err != nil) != tt.wantErr {
+				t.Errorf("mention.Insert() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
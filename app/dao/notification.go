@@ -0,0 +1,111 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/satorunooshie/Yatter/app/domain/object"
+	"github.com/satorunooshie/Yatter/app/domain/repository"
+)
+
+type (
+	// Implementation for repository.Notification
+	notification struct {
+		db  *sqlx.DB
+		ids IDGenerator
+	}
+)
+
+func NewNotification(db *sqlx.DB, ids IDGenerator) repository.Notification {
+	return &notification{db: db, ids: ids}
+}
+
+func (r *notification) Select(ctx context.Context, accountID object.AccountID, types []object.NotificationType, page object.Page) ([]*object.Notification, error) {
+	if len(types) == 0 {
+		return nil, nil
+	}
+
+	query, params, err := sqlx.In("SELECT `n`.* FROM `notification` AS `n` "+
+		"INNER JOIN `account` AS `a` ON `a`.`id` = `n`.`from_account_id` LEFT JOIN `status` AS `s` ON `s`.`id` = `n`.`status_id` "+
+		"WHERE `n`.`account_id` = ? AND `n`.`type` IN (?) AND `n`.`id` > ? AND `n`.`id` < ? AND `a`.`delete_at` IS NULL AND `s`.`delete_at` IS NULL "+
+		"ORDER BY `n`.`id` "+order(page)+" LIMIT ?", accountID, types, page.MinID, page.MaxID, page.Limit)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.db.QueryxContext(ctx, query, params...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("[WARN] dao::notification::Select::rows.Close(): %v", err)
+		}
+	}()
+
+	entities := make([]*object.Notification, 0, page.Limit)
+	for rows.Next() {
+		entity := &object.Notification{}
+		if err := rows.StructScan(&entity); err != nil {
+			return nil, err
+		}
+		entities = append(entities, entity)
+	}
+	newestFirst(page, entities)
+	return entities, nil
+}
+
+func (r *notification) FindByStatusID(ctx context.Context, statusID object.StatusID) ([]*object.Notification, error) {
+	entities := make([]*object.Notification, 0)
+	if err := r.db.SelectContext(ctx, &entities, "SELECT * FROM `notification` WHERE `status_id` = ? ORDER BY `id`", statusID); err != nil {
+		return nil, err
+	}
+	return entities, nil
+}
+
+func (r *notification) Insert(ctx context.Context, typ object.NotificationType, fromAccountID object.AccountID, statusID object.StatusID, accountIDs []object.AccountID) error {
+	return insertNotifications(ctx, r.db, r.ids, typ, fromAccountID, statusID, accountIDs)
+}
+
+func (r *notification) Delete(ctx context.Context, id object.NotificationID, accountID object.AccountID) error {
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM `notification` WHERE `id` = ? AND `account_id` = ?", id, accountID); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *notification) DeleteAll(ctx context.Context, accountID object.AccountID) error {
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM `notification` WHERE `account_id` = ?", accountID); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Insert notifications with db or in a transaction
+func insertNotifications(ctx context.Context, ext sqlx.ExecerContext, ids IDGenerator, typ object.NotificationType, fromAccountID object.AccountID, statusID object.StatusID, accountIDs []object.AccountID) error {
+	if len(accountIDs) == 0 {
+		return nil
+	}
+
+	placeholders := make([]string, 0, len(accountIDs))
+	params := make([]interface{}, 0, len(accountIDs)*5)
+	for _, accountID := range accountIDs {
+		placeholders = append(placeholders, "(?, ?, ?, ?, ?)")
+		params = append(params, ids.Next(), accountID, typ, fromAccountID, statusID)
+	}
+
+	/* 同じアカウントによる同じ操作はユニークキーで弾く */
+	if _, err := ext.ExecContext(ctx, "INSERT IGNORE INTO `notification` (`id`, `account_id`, `type`, `from_account_id`, `status_id`) VALUES "+
+		strings.Join(placeholders, ", "), params...); err != nil {
+		return err
+	}
+	return nil
+}
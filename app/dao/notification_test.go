@@ -0,0 +1,218 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"math"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	sqlxmock "github.com/zhashkevych/go-sqlxmock"
+
+	"github.com/satorunooshie/Yatter/app/domain/object"
+)
+
+func Test_notification_Select(t *testing.T) {
+	createAt, _ := time.Parse("2006-01-02", "2020-01-01")
+	const query = "SELECT `n`.* FROM `notification` AS `n` " +
+		"INNER JOIN `account` AS `a` ON `a`.`id` = `n`.`from_account_id` LEFT JOIN `status` AS `s` ON `s`.`id` = `n`.`status_id` " +
+		"WHERE `n`.`account_id` = ? AND `n`.`type` IN (?, ?) AND `n`.`id` > ? AND `n`.`id` < ? AND `a`.`delete_at` IS NULL AND `s`.`delete_at` IS NULL " +
+		"ORDER BY `n`.`id` DESC LIMIT ?"
+
+	db, mock, err := sqlxmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	r := &notification{
+		db:  db,
+		ids: &sequence{},
+	}
+
+	type args struct {
+		ctx       context.Context
+		accountID object.AccountID
+		types     []object.NotificationType
+		page      object.Page
+	}
+	tests := []struct {
+		name    string
+		query   func(s sqlxmock.Sqlmock)
+		args    args
+		want    []*object.Notification
+		wantErr bool
+	}{
+		{
+			name: "ok",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs(1, object.NotificationMention, object.NotificationFollow, 0, math.MaxInt64, 2).
+					WillReturnRows(
+						sqlxmock.NewRows(
+							[]string{
+								"id",
+								"account_id",
+								"type",
+								"from_account_id",
+								"status_id",
+								"create_at",
+							},
+						).
+							AddRow(5, 1, object.NotificationFollow, 2, 0, createAt).
+							AddRow(4, 1, object.NotificationMention, 3, 10, createAt),
+					)
+			},
+			args: args{
+				ctx:       context.Background(),
+				accountID: 1,
+				types:     []object.NotificationType{object.NotificationMention, object.NotificationFollow},
+				page:      object.Page{MinID: 0, MaxID: math.MaxInt64, Limit: 2},
+			},
+			want: []*object.Notification{
+				{ID: 5, AccountID: 1, Type: object.NotificationFollow, FromAccountID: 2, CreateAt: object.DateTime{Time: createAt}},
+				{ID: 4, AccountID: 1, Type: object.NotificationMention, FromAccountID: 3, StatusID: 10, CreateAt: object.DateTime{Time: createAt}},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "no types",
+			query: func(s sqlxmock.Sqlmock) {},
+			args: args{
+				ctx:       context.Background(),
+				accountID: 1,
+				types:     nil,
+				page:      object.Page{MinID: 0, MaxID: math.MaxInt64, Limit: 2},
+			},
+			want:    nil,
+			wantErr: false,
+		},
+		{
+			name: "error",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs(1, object.NotificationMention, object.NotificationFollow, 0, math.MaxInt64, 2).
+					WillReturnError(errors.New("error"))
+			},
+			args: args{
+				ctx:       context.Background(),
+				accountID: 1,
+				types:     []object.NotificationType{object.NotificationMention, object.NotificationFollow},
+				page:      object.Page{MinID: 0, MaxID: math.MaxInt64, Limit: 2},
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tt.query(mock)
+			got, err := r.Select(tt.args.ctx, tt.args.accountID, tt.args.types, tt.args.page)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("notification.Select() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Errorf("notification.Select() returned diff (want -> got):\n%s", diff)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func Test_notification_Insert(t *testing.T) {
+	const query = "INSERT IGNORE INTO `notification` (`id`, `account_id`, `type`, `from_account_id`, `status_id`) VALUES (?, ?, ?, ?, ?), (?, ?, ?, ?, ?)"
+
+	db, mock, err := sqlxmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	r := &notification{
+		db:  db,
+		ids: &sequence{},
+	}
+
+	type args struct {
+		ctx           context.Context
+		typ           object.NotificationType
+		fromAccountID object.AccountID
+		statusID      object.StatusID
+		accountIDs    []object.AccountID
+	}
+	tests := []struct {
+		name    string
+		query   func(s sqlxmock.Sqlmock)
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "ok",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectExec(regexp.QuoteMeta(query)).
+					WithArgs(sqlxmock.AnyArg(), 2, object.NotificationMention, 1, 10, sqlxmock.AnyArg(), 3, object.NotificationMention, 1, 10).
+					WillReturnResult(sqlxmock.NewResult(0, 2))
+			},
+			args: args{
+				ctx:           context.Background(),
+				typ:           object.NotificationMention,
+				fromAccountID: 1,
+				statusID:      10,
+				accountIDs:    []object.AccountID{2, 3},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "no accounts",
+			query: func(s sqlxmock.Sqlmock) {},
+			args: args{
+				ctx:           context.Background(),
+				typ:           object.NotificationMention,
+				fromAccountID: 1,
+				statusID:      10,
+				accountIDs:    nil,
+			},
+			wantErr: false,
+		},
+		{
+			name: "error",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectExec(regexp.QuoteMeta(query)).
+					WithArgs(sqlxmock.AnyArg(), 2, object.NotificationMention, 1, 10, sqlxmock.AnyArg(), 3, object.NotificationMention, 1, 10).
+					WillReturnError(errors.New("error"))
+			},
+			args: args{
+				ctx:           context.Background(),
+				typ:           object.NotificationMention,
+				fromAccountID: 1,
+				statusID:      10,
+				accountIDs:    []object.AccountID{2, 3},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tt.query(mock)
+			err := r.Insert(tt.args.ctx, tt.args.typ, tt.args.fromAccountID, tt.args.statusID, tt.args.accountIDs)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("notification.Insert() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package dao
+
+import (
+	"reflect"
+
+	"github.com/satorunooshie/Yatter/app/domain/object"
+)
+
+// Direction of ORDER BY to take the items of the page
+func order(page object.Page) string {
+	if page.Ascending {
+		return "ASC"
+	}
+	return "DESC"
+}
+
+// Reverse the entities taken in ascending order so that pages are always newest first
+func newestFirst(page object.Page, entities interface{}) {
+	if !page.Ascending {
+		return
+	}
+	swap := reflect.Swapper(entities)
+	for i, j := 0, reflect.ValueOf(entities).Len()-1; i < j; i, j = i+1, j-1 {
+		swap(i, j)
+	}
+}
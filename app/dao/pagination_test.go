@@ -0,0 +1,244 @@
+package dao
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"sync"
+	"testing"
+	"testing/quick"
+	"time"
+
+	"github.com/satorunooshie/Yatter/app/config"
+	"github.com/satorunooshie/Yatter/app/domain/object"
+	"github.com/satorunooshie/Yatter/app/snowflake"
+)
+
+/*
+ * 実際の MySQL に対するページングの性質テスト
+ * MYSQL_HOST が設定されていなければスキップする
+ */
+
+func openTestDB(t *testing.T) *dao {
+	t.Helper()
+	if os.Getenv("MYSQL_HOST") == "" {
+		t.Skip("MYSQL_HOST is not set")
+	}
+
+	ids, err := snowflake.New(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := New(config.MySQLConfig(), ids)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.InitAll(); err != nil {
+		t.Fatal(err)
+	}
+	return d.(*dao)
+}
+
+// Insert n statuses with random visibilities and creation times unrelated to their IDs,
+// attaching up to 3 media to each and deleting some of them
+func insertTestStatuses(ctx context.Context, d *dao, rnd *rand.Rand, accountID object.AccountID, n int) error {
+	r := d.Status()
+	for i := 0; i < n; i++ {
+		visibility := object.VisibilityPublic
+		if rnd.Intn(4) == 0 {
+			visibility = object.VisibilityUnlisted
+		}
+		createAt := time.Date(2022, 4, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(rnd.Intn(1000)) * time.Minute)
+		id, err := r.InsertRemote(ctx, accountID, fmt.Sprintf("https://remote.example/statuses/%d", rnd.Int63()), "content", visibility, createAt)
+		if err != nil {
+			return err
+		}
+		for j := rnd.Intn(4); j > 0; j-- {
+			if _, err := d.db.ExecContext(ctx, "INSERT INTO `media_attachment` (`id`, `status_id`, `url`, `create_at`) VALUES (?, ?, ?, ?)", d.ids.Next(), id, "/media/a.png", createAt); err != nil {
+				return err
+			}
+		}
+		if rnd.Intn(8) == 0 {
+			if _, err := d.db.ExecContext(ctx, "UPDATE `status` SET `delete_at` = NOW() WHERE `id` = ?", id); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func insertTestAccount(t *testing.T, d *dao) object.AccountID {
+	t.Helper()
+	ctx := context.Background()
+
+	r := d.Account()
+	if err := r.Insert(ctx, "john", "", "", "", time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	account, err := r.FindByUsername(ctx, "john")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return account.ID
+}
+
+// Walk the pages while inserting statuses concurrently, returning the IDs of the items in the walked order
+func walkPages(ctx context.Context, d *dao, rnd *rand.Rand, accountID object.AccountID, inserts int, page object.Page, next func(ctx context.Context, page object.Page) (object.Page, []int64, error)) ([]int64, error) {
+	var wg sync.WaitGroup
+	insertErr := make(chan error, 1)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		insertErr <- insertTestStatuses(ctx, d, rand.New(rand.NewSource(rnd.Int63())), accountID, inserts)
+	}()
+
+	walked := make([]int64, 0)
+	for {
+		var (
+			ids []int64
+			err error
+		)
+		page, ids, err = next(ctx, page)
+		if err != nil {
+			return nil, err
+		}
+		if len(ids) == 0 {
+			break
+		}
+		walked = append(walked, ids...)
+	}
+
+	wg.Wait()
+	if err := <-insertErr; err != nil {
+		return nil, err
+	}
+	return walked, nil
+}
+
+// Check the walked IDs are strictly ordered, which means no duplicates
+func checkOrdered(t *testing.T, walked []int64, ascending bool) bool {
+	t.Helper()
+	for i := 1; i < len(walked); i++ {
+		if ascending && walked[i-1] >= walked[i] || !ascending && walked[i-1] <= walked[i] {
+			t.Logf("walked out of order or duplicated at %d: %v", i, walked)
+			return false
+		}
+	}
+	return true
+}
+
+// Check none of the expected IDs are skipped
+func checkContains(t *testing.T, walked, expected []int64) bool {
+	t.Helper()
+	found := make(map[int64]bool, len(walked))
+	for _, id := range walked {
+		found[id] = true
+	}
+	for _, id := range expected {
+		if !found[id] {
+			t.Logf("skipped %d: walked %v, expected %v", id, walked, expected)
+			return false
+		}
+	}
+	return true
+}
+
+func TestStatusSelectPagination(t *testing.T) {
+	d := openTestDB(t)
+	accountID := insertTestAccount(t, d)
+	r := d.Status()
+	ctx := context.Background()
+
+	f := func(seed int64, n, inserts, limit uint8, ascending bool) bool {
+		rnd := rand.New(rand.NewSource(seed))
+		if err := insertTestStatuses(ctx, d, rnd, accountID, int(n%50)); err != nil {
+			t.Fatal(err)
+		}
+		expected := make([]int64, 0)
+		if err := d.db.SelectContext(ctx, &expected, "SELECT `id` FROM `status` WHERE `visibility` = ? AND `delete_at` IS NULL", object.VisibilityPublic); err != nil {
+			t.Fatal(err)
+		}
+
+		page := object.Page{MinID: 0, MaxID: math.MaxInt64, Limit: int64(limit%10) + 1, Ascending: ascending}
+		walked, err := walkPages(ctx, d, rnd, accountID, int(inserts%20), page, func(ctx context.Context, page object.Page) (object.Page, []int64, error) {
+			statuses, err := r.Select(ctx, page)
+			if err != nil || len(statuses) == 0 {
+				return page, nil, err
+			}
+			ids := make([]int64, 0, len(statuses))
+			for _, v := range statuses {
+				ids = append(ids, v.ID)
+			}
+			/* ページ内は常に新しい順なので昇順に辿るときは逆順に並べる */
+			if page.Ascending {
+				for i, j := 0, len(ids)-1; i < j; i, j = i+1, j-1 {
+					ids[i], ids[j] = ids[j], ids[i]
+				}
+				page.MinID = ids[len(ids)-1]
+			} else {
+				page.MaxID = ids[len(ids)-1]
+			}
+			return page, ids, nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return checkOrdered(t, walked, ascending) && checkContains(t, walked, expected)
+	}
+	if err := quick.Check(f, &quick.Config{MaxCount: 30}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMediaAttachmentSelectPagination(t *testing.T) {
+	d := openTestDB(t)
+	accountID := insertTestAccount(t, d)
+	r := d.MediaAttachment()
+	ctx := context.Background()
+
+	f := func(seed int64, n, inserts, limit uint8) bool {
+		rnd := rand.New(rand.NewSource(seed))
+		if err := insertTestStatuses(ctx, d, rnd, accountID, int(n%50)); err != nil {
+			t.Fatal(err)
+		}
+		expected := make([]int64, 0)
+		if err := d.db.SelectContext(ctx, &expected, "SELECT `m`.`id` FROM `media_attachment` AS `m` INNER JOIN `status` AS `s` ON `s`.`id` = `m`.`status_id` "+
+			"WHERE `s`.`visibility` = ? AND `m`.`delete_at` IS NULL AND `s`.`delete_at` IS NULL", object.VisibilityPublic); err != nil {
+			t.Fatal(err)
+		}
+
+		statusIDs := make(map[int64]int64)
+		page := object.Page{MinID: 0, MaxID: math.MaxInt64, Limit: int64(limit%10) + 1}
+		walked, err := walkPages(ctx, d, rnd, accountID, int(inserts%20), page, func(ctx context.Context, page object.Page) (object.Page, []int64, error) {
+			media, err := r.Select(ctx, page)
+			if err != nil || len(media) == 0 {
+				return page, nil, err
+			}
+			ids := make([]int64, 0, len(media))
+			for _, v := range media {
+				statusIDs[v.ID] = v.StatusID
+				ids = append(ids, v.ID)
+			}
+			page.MaxID = media[len(media)-1].StatusID
+			return page, ids, nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		/* ステータスの新しい順、同じステータスの中では添付ファイルの古い順に並ぶ */
+		for i := 1; i < len(walked); i++ {
+			prev, cur := statusIDs[walked[i-1]], statusIDs[walked[i]]
+			if prev < cur || prev == cur && walked[i-1] >= walked[i] {
+				t.Logf("walked out of order or duplicated at %d: %v", i, walked)
+				return false
+			}
+		}
+		return checkContains(t, walked, expected)
+	}
+	if err := quick.Check(f, &quick.Config{MaxCount: 30}); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,87 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/satorunooshie/Yatter/app/domain/object"
+	"github.com/satorunooshie/Yatter/app/domain/repository"
+)
+
+type (
+	// Implementation for repository.PinnedStatus
+	pinnedStatus struct {
+		db  *sqlx.DB
+		ids IDGenerator
+	}
+)
+
+func NewPinnedStatus(db *sqlx.DB, ids IDGenerator) repository.PinnedStatus {
+	return &pinnedStatus{db: db, ids: ids}
+}
+
+func (r *pinnedStatus) FindByAccountID(ctx context.Context, accountID object.AccountID) ([]object.StatusID, error) {
+	ids := make([]object.StatusID, 0)
+	if err := r.db.SelectContext(ctx, &ids, "SELECT `status_id` FROM `pinned_status` WHERE `account_id` = ? ORDER BY `position` DESC", accountID); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (r *pinnedStatus) FindPinned(ctx context.Context, statusIDs []object.StatusID) ([]object.StatusID, error) {
+	query, params, err := sqlx.In("SELECT `status_id` FROM `pinned_status` WHERE `status_id` IN (?)", statusIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]object.StatusID, 0)
+	if err := r.db.SelectContext(ctx, &ids, query, params...); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (r *pinnedStatus) Insert(ctx context.Context, accountID object.AccountID, statusID object.StatusID, max int) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			log.Printf("[WARN] dao::pinnedStatus::Insert::tx.Rollback(): %v", err)
+		}
+	}()
+
+	/* 同時に上限を超えて固定されないようにアカウントの固定をロックする */
+	pinned := make([]object.StatusID, 0)
+	if err := tx.SelectContext(ctx, &pinned, "SELECT `status_id` FROM `pinned_status` WHERE `account_id` = ? FOR UPDATE", accountID); err != nil {
+		return err
+	}
+	for _, id := range pinned {
+		if id == statusID {
+			return nil
+		}
+	}
+	if len(pinned) >= max {
+		return repository.ErrPinLimit
+	}
+
+	if _, err := tx.ExecContext(ctx, "INSERT INTO `pinned_status` (`id`, `account_id`, `status_id`, `position`) "+
+		"SELECT ?, ?, ?, COALESCE(MAX(`position`), 0) + 1 FROM `pinned_status` WHERE `account_id` = ?", r.ids.Next(), accountID, statusID, accountID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *pinnedStatus) Delete(ctx context.Context, accountID object.AccountID, statusID object.StatusID) error {
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM `pinned_status` WHERE `account_id` = ? AND `status_id` = ?", accountID, statusID); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	sqlxmock "github.com/zhashkevych/go-sqlxmock"
+
+	"github.com/satorunooshie/Yatter/app/domain/object"
+	"github.com/satorunooshie/Yatter/app/domain/repository"
+)
+
+func Test_pinnedStatus_Insert(t *testing.T) {
+	const (
+		lockQuery   = "SELECT `status_id` FROM `pinned_status` WHERE `account_id` = ? FOR UPDATE"
+		insertQuery = "INSERT INTO `pinned_status` (`id`, `account_id`, `status_id`, `position`) " +
+			"SELECT ?, ?, ?, COALESCE(MAX(`position`), 0) + 1 FROM `pinned_status` WHERE `account_id` = ?"
+	)
+
+	db, mock, err := sqlxmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	r := &pinnedStatus{
+		db:  db,
+		ids: &sequence{},
+	}
+
+	type args struct {
+		ctx       context.Context
+		accountID object.AccountID
+		statusID  object.StatusID
+		max       int
+	}
+	tests := []struct {
+		name    string
+		query   func(s sqlxmock.Sqlmock)
+		args    args
+		wantErr error
+	}{
+		{
+			name: "ok",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectBegin()
+				s.ExpectQuery(regexp.QuoteMeta(lockQuery)).
+					WithArgs(1).
+					WillReturnRows(sqlxmock.NewRows([]string{"status_id"}).AddRow(2))
+				s.ExpectExec(regexp.QuoteMeta(insertQuery)).
+					WithArgs(1, 1, 3, 1).
+					WillReturnResult(sqlxmock.NewResult(2, 1))
+				s.ExpectCommit()
+			},
+			args: args{
+				ctx:       context.Background(),
+				accountID: 1,
+				statusID:  3,
+				max:       2,
+			},
+			wantErr: nil,
+		},
+		{
+			name: "already pinned",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectBegin()
+				s.ExpectQuery(regexp.QuoteMeta(lockQuery)).
+					WithArgs(1).
+					WillReturnRows(sqlxmock.NewRows([]string{"status_id"}).AddRow(2).AddRow(3))
+				s.ExpectRollback()
+			},
+			args: args{
+				ctx:       context.Background(),
+				accountID: 1,
+				statusID:  3,
+				max:       2,
+			},
+			wantErr: nil,
+		},
+		{
+			name: "limit",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectBegin()
+				s.ExpectQuery(regexp.QuoteMeta(lockQuery)).
+					WithArgs(1).
+					WillReturnRows(sqlxmock.NewRows([]string{"status_id"}).AddRow(2).AddRow(4))
+				s.ExpectRollback()
+			},
+			args: args{
+				ctx:       context.Background(),
+				accountID: 1,
+				statusID:  3,
+				max:       2,
+			},
+			wantErr: repository.ErrPinLimit,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tt.query(mock)
+			if err := r.Insert(tt.args.ctx, tt.args.accountID, tt.args.statusID, tt.args.max); !errors.Is(err, tt.wantErr) {
+				t.Errorf("pinnedStatus.Insert() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,229 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/satorunooshie/Yatter/app/domain/object"
+	"github.com/satorunooshie/Yatter/app/domain/repository"
+)
+
+type (
+	// Implementation for repository.Poll
+	poll struct {
+		db  *sqlx.DB
+		ids IDGenerator
+	}
+)
+
+const pollColumns = "`p`.*, `s`.`account_id`, " +
+	"(SELECT COUNT(*) FROM `poll_vote` AS `v` WHERE `v`.`poll_id` = `p`.`id`) AS `votes_count`, " +
+	"(SELECT COUNT(DISTINCT `v`.`account_id`) FROM `poll_vote` AS `v` WHERE `v`.`poll_id` = `p`.`id`) AS `voters_count`"
+
+func NewPoll(db *sqlx.DB, ids IDGenerator) repository.Poll {
+	return &poll{db: db, ids: ids}
+}
+
+func (r *poll) FindByID(ctx context.Context, id object.PollID) (*object.Poll, error) {
+	entity := &object.Poll{}
+	if err := r.db.QueryRowxContext(ctx, "SELECT "+pollColumns+" FROM `poll` AS `p` INNER JOIN `status` AS `s` ON `s`.`id` = `p`.`status_id` WHERE `p`.`id` = ? AND `s`.`delete_at` IS NULL", id).StructScan(entity); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if err := r.fillOptions(ctx, []*object.Poll{entity}); err != nil {
+		return nil, err
+	}
+	return entity, nil
+}
+
+func (r *poll) FindByStatusIDs(ctx context.Context, statusIDs []object.StatusID) ([]*object.Poll, error) {
+	query, params, err := sqlx.In("SELECT "+pollColumns+" FROM `poll` AS `p` INNER JOIN `status` AS `s` ON `s`.`id` = `p`.`status_id` WHERE `p`.`status_id` IN (?)", statusIDs)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.db.QueryxContext(ctx, query, params...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("[WARN] dao::poll::FindByStatusIDs::rows.Close(): %v", err)
+		}
+	}()
+
+	entities := make([]*object.Poll, 0)
+	for rows.Next() {
+		entity := &object.Poll{}
+		if err := rows.StructScan(&entity); err != nil {
+			return nil, err
+		}
+		entities = append(entities, entity)
+	}
+	if err := r.fillOptions(ctx, entities); err != nil {
+		return nil, err
+	}
+	return entities, nil
+}
+
+func (r *poll) FindVotes(ctx context.Context, pollIDs []object.PollID, accountID object.AccountID) ([]*object.PollVote, error) {
+	query, params, err := sqlx.In("SELECT `poll_id`, `account_id`, `choice` FROM `poll_vote` WHERE `poll_id` IN (?) AND `account_id` = ? ORDER BY `choice`", pollIDs, accountID)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.db.QueryxContext(ctx, query, params...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("[WARN] dao::poll::FindVotes::rows.Close(): %v", err)
+		}
+	}()
+
+	entities := make([]*object.PollVote, 0)
+	for rows.Next() {
+		entity := &object.PollVote{}
+		if err := rows.StructScan(&entity); err != nil {
+			return nil, err
+		}
+		entities = append(entities, entity)
+	}
+	return entities, nil
+}
+
+func (r *poll) Insert(ctx context.Context, statusID object.StatusID, options []string, expireAt time.Time, multiple, hideTotals bool) (object.PollID, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			log.Printf("[WARN] dao::poll::Insert::tx.Rollback(): %v", err)
+		}
+	}()
+
+	id := r.ids.Next()
+	if err := insertPoll(ctx, tx, r.ids, id, statusID, options, expireAt, multiple, hideTotals); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r *poll) Vote(ctx context.Context, id object.PollID, accountID object.AccountID, choices []int) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			log.Printf("[WARN] dao::poll::Vote::tx.Rollback(): %v", err)
+		}
+	}()
+
+	/* 同じアカウントからの同時投票を防ぐために投票をロックする */
+	if _, err := tx.ExecContext(ctx, "SELECT `id` FROM `poll` WHERE `id` = ? FOR UPDATE", id); err != nil {
+		return err
+	}
+
+	var voted bool
+	if err := tx.QueryRowxContext(ctx, "SELECT EXISTS(SELECT 1 FROM `poll_vote` WHERE `poll_id` = ? AND `account_id` = ?)", id, accountID).Scan(&voted); err != nil {
+		return err
+	}
+	if voted {
+		return repository.ErrAlreadyVoted
+	}
+
+	placeholders := make([]string, 0, len(choices))
+	params := make([]interface{}, 0, len(choices)*4)
+	for _, choice := range choices {
+		placeholders = append(placeholders, "(?, ?, ?, ?)")
+		params = append(params, r.ids.Next(), id, accountID, choice)
+	}
+	if _, err := tx.ExecContext(ctx, "INSERT INTO `poll_vote` (`id`, `poll_id`, `account_id`, `choice`) VALUES "+strings.Join(placeholders, ", "), params...); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Fill polls with their options and the number of votes for each option
+func (r *poll) fillOptions(ctx context.Context, polls []*object.Poll) error {
+	if len(polls) == 0 {
+		return nil
+	}
+
+	pollIDs := make([]object.PollID, 0, len(polls))
+	pollMap := make(map[object.PollID]*object.Poll, len(polls))
+	for _, p := range polls {
+		pollIDs = append(pollIDs, p.ID)
+		pollMap[p.ID] = p
+	}
+
+	query, params, err := sqlx.In("SELECT `o`.*, COUNT(`v`.`poll_id`) AS `votes_count` FROM `poll_option` AS `o` "+
+		"LEFT JOIN `poll_vote` AS `v` ON `v`.`poll_id` = `o`.`poll_id` AND `v`.`choice` = `o`.`position` "+
+		"WHERE `o`.`poll_id` IN (?) GROUP BY `o`.`id` ORDER BY `o`.`poll_id`, `o`.`position`", pollIDs)
+	if err != nil {
+		return err
+	}
+	rows, err := r.db.QueryxContext(ctx, query, params...)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("[WARN] dao::poll::fillOptions::rows.Close(): %v", err)
+		}
+	}()
+
+	for rows.Next() {
+		entity := &object.PollOption{}
+		if err := rows.StructScan(&entity); err != nil {
+			return err
+		}
+		if p, ok := pollMap[entity.PollID]; ok {
+			p.Options = append(p.Options, entity)
+		}
+	}
+	return nil
+}
+
+// Insert a poll with its options in the transaction
+func insertPoll(ctx context.Context, tx sqlx.ExecerContext, ids IDGenerator, id object.PollID, statusID object.StatusID, options []string, expireAt time.Time, multiple, hideTotals bool) error {
+	if _, err := tx.ExecContext(ctx, "INSERT INTO `poll` (`id`, `status_id`, `expire_at`, `multiple`, `hide_totals`) VALUES (?, ?, ?, ?, ?)", id, statusID, expireAt, multiple, hideTotals); err != nil {
+		return err
+	}
+
+	placeholders := make([]string, 0, len(options))
+	params := make([]interface{}, 0, len(options)*4)
+	for i, title := range options {
+		placeholders = append(placeholders, "(?, ?, ?, ?)")
+		params = append(params, ids.Next(), id, i, title)
+	}
+	if _, err := tx.ExecContext(ctx, "INSERT INTO `poll_option` (`id`, `poll_id`, `position`, `title`) VALUES "+strings.Join(placeholders, ", "), params...); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,248 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	sqlxmock "github.com/zhashkevych/go-sqlxmock"
+
+	"github.com/satorunooshie/Yatter/app/domain/object"
+	"github.com/satorunooshie/Yatter/app/domain/repository"
+)
+
+func Test_poll_FindByID(t *testing.T) {
+	createAt, _ := time.Parse("2006-01-02", "2020-01-01")
+	expireAt := createAt.Add(24 * time.Hour)
+	const (
+		pollQuery   = "SELECT " + pollColumns + " FROM `poll` AS `p` INNER JOIN `status` AS `s` ON `s`.`id` = `p`.`status_id` WHERE `p`.`id` = ? AND `s`.`delete_at` IS NULL"
+		optionQuery = "SELECT `o`.*, COUNT(`v`.`poll_id`) AS `votes_count` FROM `poll_option` AS `o` " +
+			"LEFT JOIN `poll_vote` AS `v` ON `v`.`poll_id` = `o`.`poll_id` AND `v`.`choice` = `o`.`position` " +
+			"WHERE `o`.`poll_id` IN (?) GROUP BY `o`.`id` ORDER BY `o`.`poll_id`, `o`.`position`"
+	)
+	count := func(n int64) *int64 { return &n }
+
+	db, mock, err := sqlxmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	r := &poll{
+		db:  db,
+		ids: &sequence{},
+	}
+
+	type args struct {
+		ctx context.Context
+		id  object.PollID
+	}
+	tests := []struct {
+		name    string
+		query   func(s sqlxmock.Sqlmock)
+		args    args
+		want    *object.Poll
+		wantErr bool
+	}{
+		{
+			name: "ok",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectQuery(regexp.QuoteMeta(pollQuery)).
+					WithArgs(1).
+					WillReturnRows(
+						sqlxmock.NewRows(
+							[]string{
+								"id",
+								"status_id",
+								"expire_at",
+								"multiple",
+								"hide_totals",
+								"create_at",
+								"account_id",
+								"votes_count",
+								"voters_count",
+							},
+						).
+							AddRow(1, 2, expireAt, true, false, createAt, 3, 3, 2),
+					)
+				s.ExpectQuery(regexp.QuoteMeta(optionQuery)).
+					WithArgs(1).
+					WillReturnRows(
+						sqlxmock.NewRows(
+							[]string{
+								"id",
+								"poll_id",
+								"position",
+								"title",
+								"votes_count",
+							},
+						).
+							AddRow(1, 1, 0, "ramen", 2).
+							AddRow(2, 1, 1, "udon", 1),
+					)
+			},
+			args: args{
+				ctx: context.Background(),
+				id:  1,
+			},
+			want: &object.Poll{
+				ID:          1,
+				StatusID:    2,
+				ExpireAt:    object.DateTime{Time: expireAt},
+				Multiple:    true,
+				HideTotals:  false,
+				CreateAt:    object.DateTime{Time: createAt},
+				AccountID:   3,
+				VotesCount:  count(3),
+				VotersCount: count(2),
+				Options: []*object.PollOption{
+					{ID: 1, PollID: 1, Position: 0, Title: "ramen", VotesCount: count(2)},
+					{ID: 2, PollID: 1, Position: 1, Title: "udon", VotesCount: count(1)},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "not found",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectQuery(regexp.QuoteMeta(pollQuery)).
+					WithArgs(1).
+					WillReturnError(sql.ErrNoRows)
+			},
+			args: args{
+				ctx: context.Background(),
+				id:  1,
+			},
+			want:    nil,
+			wantErr: false,
+		},
+		{
+			name: "error",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectQuery(regexp.QuoteMeta(pollQuery)).
+					WithArgs(1).
+					WillReturnError(errors.New("error"))
+			},
+			args: args{
+				ctx: context.Background(),
+				id:  1,
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tt.query(mock)
+			got, err := r.FindByID(tt.args.ctx, tt.args.id)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("poll.FindByID() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Errorf("poll.FindByID() returned diff (want -> got):\n%s", diff)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func Test_poll_Vote(t *testing.T) {
+	const (
+		lockQuery   = "SELECT `id` FROM `poll` WHERE `id` = ? FOR UPDATE"
+		votedQuery  = "SELECT EXISTS(SELECT 1 FROM `poll_vote` WHERE `poll_id` = ? AND `account_id` = ?)"
+		insertQuery = "INSERT INTO `poll_vote` (`id`, `poll_id`, `account_id`, `choice`) VALUES (?, ?, ?, ?), (?, ?, ?, ?)"
+	)
+
+	db, mock, err := sqlxmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	r := &poll{
+		db:  db,
+		ids: &sequence{},
+	}
+
+	type args struct {
+		ctx       context.Context
+		id        object.PollID
+		accountID object.AccountID
+		choices   []int
+	}
+	tests := []struct {
+		name    string
+		query   func(s sqlxmock.Sqlmock)
+		args    args
+		wantErr error
+	}{
+		{
+			name: "ok",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectBegin()
+				s.ExpectExec(regexp.QuoteMeta(lockQuery)).
+					WithArgs(1).
+					WillReturnResult(sqlxmock.NewResult(0, 0))
+				s.ExpectQuery(regexp.QuoteMeta(votedQuery)).
+					WithArgs(1, 2).
+					WillReturnRows(sqlxmock.NewRows([]string{"exists"}).AddRow(false))
+				s.ExpectExec(regexp.QuoteMeta(insertQuery)).
+					WithArgs(1, 1, 2, 0, 2, 1, 2, 3).
+					WillReturnResult(sqlxmock.NewResult(1, 2))
+				s.ExpectCommit()
+			},
+			args: args{
+				ctx:       context.Background(),
+				id:        1,
+				accountID: 2,
+				choices:   []int{0, 3},
+			},
+			wantErr: nil,
+		},
+		{
+			name: "already voted",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectBegin()
+				s.ExpectExec(regexp.QuoteMeta(lockQuery)).
+					WithArgs(1).
+					WillReturnResult(sqlxmock.NewResult(0, 0))
+				s.ExpectQuery(regexp.QuoteMeta(votedQuery)).
+					WithArgs(1, 2).
+					WillReturnRows(sqlxmock.NewRows([]string{"exists"}).AddRow(true))
+				s.ExpectRollback()
+			},
+			args: args{
+				ctx:       context.Background(),
+				id:        1,
+				accountID: 2,
+				choices:   []int{0, 3},
+			},
+			wantErr: repository.ErrAlreadyVoted,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tt.query(mock)
+			if err := r.Vote(tt.args.ctx, tt.args.id, tt.args.accountID, tt.args.choices); !errors.Is(err, tt.wantErr) {
+				t.Errorf("poll.Vote() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/satorunooshie/Yatter/app/domain/object"
+	"github.com/satorunooshie/Yatter/app/domain/repository"
+)
+
+type (
+	// Implementation for repository.Relationship
+	relationship struct {
+		db  *sqlx.DB
+		ids IDGenerator
+	}
+)
+
+func NewRelationship(db *sqlx.DB, ids IDGenerator) repository.Relationship {
+	return &relationship{db: db, ids: ids}
+}
+
+func (r *relationship) IsFollowing(ctx context.Context, followerID, followeeID object.AccountID) (bool, error) {
+	var exists bool
+	if err := r.db.QueryRowxContext(ctx, "SELECT EXISTS(SELECT 1 FROM `follow` WHERE `follower_id` = ? AND `followee_id` = ? AND `delete_at` IS NULL)", followerID, followeeID).Scan(&exists); err != nil {
+		return false, err
+	}
+	return exists, nil
+}
+
+func (r *relationship) SelectFollowerInboxes(ctx context.Context, followeeID object.AccountID) ([]string, error) {
+	inboxes := make([]string, 0)
+	if err := r.db.SelectContext(ctx, &inboxes, "SELECT DISTINCT COALESCE(`a`.`shared_inbox_url`, `a`.`inbox_url`) FROM `follow` AS `f` "+
+		"INNER JOIN `account` AS `a` ON `a`.`id` = `f`.`follower_id` "+
+		"WHERE `f`.`followee_id` = ? AND `f`.`delete_at` IS NULL AND `a`.`domain` <> '' AND `a`.`inbox_url` IS NOT NULL AND `a`.`delete_at` IS NULL", followeeID); err != nil {
+		return nil, err
+	}
+	return inboxes, nil
+}
+
+func (r *relationship) Follow(ctx context.Context, followerID, followeeID object.AccountID) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			log.Printf("[WARN] dao::relationship::Follow::tx.Rollback(): %v", err)
+		}
+	}()
+
+	/* 解除された行は復活させる。挿入で 1、復活で 2、フォロー中なら 0 行が影響を受ける */
+	res, err := tx.ExecContext(ctx, "INSERT INTO `follow` (`id`, `follower_id`, `followee_id`) VALUES (?, ?, ?) "+
+		"ON DUPLICATE KEY UPDATE `create_at` = IF(`delete_at` IS NULL, `create_at`, NOW()), `delete_at` = NULL", r.ids.Next(), followerID, followeeID)
+	if err != nil {
+		return err
+	}
+	followed, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if followed == 0 {
+		return nil
+	}
+
+	if _, err := tx.ExecContext(ctx, "UPDATE `account` SET `following_count` = `following_count` + 1 WHERE `id` = ?", followerID); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "UPDATE `account` SET `followers_count` = `followers_count` + 1 WHERE `id` = ?", followeeID); err != nil {
+		return err
+	}
+
+	/* ローカルのアカウントだけに通知し、フォローし直しても通知は一度だけにする */
+	if _, err := tx.ExecContext(ctx, "INSERT IGNORE INTO `notification` (`id`, `account_id`, `type`, `from_account_id`, `status_id`) "+
+		"SELECT ?, `id`, ?, ?, 0 FROM `account` WHERE `id` = ? AND `domain` = ''", r.ids.Next(), object.NotificationFollow, followerID, followeeID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *relationship) Unfollow(ctx context.Context, followerID, followeeID object.AccountID) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			log.Printf("[WARN] dao::relationship::Unfollow::tx.Rollback(): %v", err)
+		}
+	}()
+
+	res, err := tx.ExecContext(ctx, "UPDATE `follow` SET `delete_at` = NOW() WHERE `follower_id` = ? AND `followee_id` = ? AND `delete_at` IS NULL", followerID, followeeID)
+	if err != nil {
+		return err
+	}
+	unfollowed, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if unfollowed == 0 {
+		return nil
+	}
+
+	if _, err := tx.ExecContext(ctx, "UPDATE `account` SET `following_count` = `following_count` - 1 WHERE `id` = ? AND `following_count` > 0", followerID); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "UPDATE `account` SET `followers_count` = `followers_count` - 1 WHERE `id` = ? AND `followers_count` > 0", followeeID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,303 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	sqlxmock "github.com/zhashkevych/go-sqlxmock"
+
+	"github.com/satorunooshie/Yatter/app/domain/object"
+)
+
+func Test_relationship_IsFollowing(t *testing.T) {
+	db, mock, err := sqlxmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	r := &relationship{
+		db: db,
+	}
+
+	type args struct {
+		ctx        context.Context
+		followerID object.AccountID
+		followeeID object.AccountID
+	}
+	tests := []struct {
+		name    string
+		query   func(s sqlxmock.Sqlmock)
+		args    args
+		want    bool
+		wantErr bool
+	}{
+		{
+			name: "following",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectQuery(regexp.QuoteMeta("SELECT EXISTS(SELECT 1 FROM `follow` WHERE `follower_id` = ? AND `followee_id` = ? AND `delete_at` IS NULL)")).
+					WithArgs(1, 2).
+					WillReturnRows(sqlxmock.NewRows([]string{"exists"}).AddRow(true))
+			},
+			args: args{
+				ctx:        context.Background(),
+				followerID: 1,
+				followeeID: 2,
+			},
+			want:    true,
+			wantErr: false,
+		},
+		{
+			name: "not following",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectQuery(regexp.QuoteMeta("SELECT EXISTS(SELECT 1 FROM `follow` WHERE `follower_id` = ? AND `followee_id` = ? AND `delete_at` IS NULL)")).
+					WithArgs(1, 2).
+					WillReturnRows(sqlxmock.NewRows([]string{"exists"}).AddRow(false))
+			},
+			args: args{
+				ctx:        context.Background(),
+				followerID: 1,
+				followeeID: 2,
+			},
+			want:    false,
+			wantErr: false,
+		},
+		{
+			name: "error",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectQuery(regexp.QuoteMeta("SELECT EXISTS(SELECT 1 FROM `follow` WHERE `follower_id` = ? AND `followee_id` = ? AND `delete_at` IS NULL)")).
+					WithArgs(1, 2).
+					WillReturnError(errors.New("error"))
+			},
+			args: args{
+				ctx:        context.Background(),
+				followerID: 1,
+				followeeID: 2,
+			},
+			want:    false,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tt.query(mock)
+			got, err := r.IsFollowing(tt.args.ctx, tt.args.followerID, tt.args.followeeID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("relationship.IsFollowing() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Errorf("relationship.IsFollowing() returned diff (want -> got):\n%s", diff)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func Test_relationship_Follow(t *testing.T) {
+	const (
+		followQuery = "INSERT INTO `follow` (`id`, `follower_id`, `followee_id`) VALUES (?, ?, ?) " +
+			"ON DUPLICATE KEY UPDATE `create_at` = IF(`delete_at` IS NULL, `create_at`, NOW()), `delete_at` = NULL"
+		followingQuery = "UPDATE `account` SET `following_count` = `following_count` + 1 WHERE `id` = ?"
+		followersQuery = "UPDATE `account` SET `followers_count` = `followers_count` + 1 WHERE `id` = ?"
+		notifyQuery    = "INSERT IGNORE INTO `notification` (`id`, `account_id`, `type`, `from_account_id`, `status_id`) " +
+			"SELECT ?, `id`, ?, ?, 0 FROM `account` WHERE `id` = ? AND `domain` = ''"
+	)
+
+	db, mock, err := sqlxmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	r := &relationship{
+		db:  db,
+		ids: &sequence{},
+	}
+
+	type args struct {
+		ctx        context.Context
+		followerID object.AccountID
+		followeeID object.AccountID
+	}
+	tests := []struct {
+		name    string
+		query   func(s sqlxmock.Sqlmock)
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "follow",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectBegin()
+				s.ExpectExec(regexp.QuoteMeta(followQuery)).
+					WithArgs(1, 1, 2).
+					WillReturnResult(sqlxmock.NewResult(1, 1))
+				s.ExpectExec(regexp.QuoteMeta(followingQuery)).
+					WithArgs(1).
+					WillReturnResult(sqlxmock.NewResult(0, 1))
+				s.ExpectExec(regexp.QuoteMeta(followersQuery)).
+					WithArgs(2).
+					WillReturnResult(sqlxmock.NewResult(0, 1))
+				s.ExpectExec(regexp.QuoteMeta(notifyQuery)).
+					WithArgs(2, object.NotificationFollow, 1, 2).
+					WillReturnResult(sqlxmock.NewResult(0, 1))
+				s.ExpectCommit()
+			},
+			args: args{
+				ctx:        context.Background(),
+				followerID: 1,
+				followeeID: 2,
+			},
+			wantErr: false,
+		},
+		{
+			name: "follow again after unfollowing",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectBegin()
+				s.ExpectExec(regexp.QuoteMeta(followQuery)).
+					WithArgs(3, 1, 2).
+					WillReturnResult(sqlxmock.NewResult(0, 2))
+				s.ExpectExec(regexp.QuoteMeta(followingQuery)).
+					WithArgs(1).
+					WillReturnResult(sqlxmock.NewResult(0, 1))
+				s.ExpectExec(regexp.QuoteMeta(followersQuery)).
+					WithArgs(2).
+					WillReturnResult(sqlxmock.NewResult(0, 1))
+				s.ExpectExec(regexp.QuoteMeta(notifyQuery)).
+					WithArgs(4, object.NotificationFollow, 1, 2).
+					WillReturnResult(sqlxmock.NewResult(0, 0))
+				s.ExpectCommit()
+			},
+			args: args{
+				ctx:        context.Background(),
+				followerID: 1,
+				followeeID: 2,
+			},
+			wantErr: false,
+		},
+		{
+			name: "already following",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectBegin()
+				s.ExpectExec(regexp.QuoteMeta(followQuery)).
+					WithArgs(5, 1, 2).
+					WillReturnResult(sqlxmock.NewResult(0, 0))
+				s.ExpectRollback()
+			},
+			args: args{
+				ctx:        context.Background(),
+				followerID: 1,
+				followeeID: 2,
+			},
+			wantErr: false,
+		},
+		{
+			name: "error",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectBegin()
+				s.ExpectExec(regexp.QuoteMeta(followQuery)).
+					WithArgs(6, 1, 2).
+					WillReturnError(errors.New("error"))
+				s.ExpectRollback()
+			},
+			args: args{
+				ctx:        context.Background(),
+				followerID: 1,
+				followeeID: 2,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tt.query(mock)
+			if err := r.Follow(tt.args.ctx, tt.args.followerID, tt.args.followeeID); (err != nil) != tt.wantErr {
+				t.Errorf("relationship.Follow() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func Test_relationship_Unfollow(t *testing.T) {
+	const (
+		unfollowQuery  = "UPDATE `follow` SET `delete_at` = NOW() WHERE `follower_id` = ? AND `followee_id` = ? AND `delete_at` IS NULL"
+		followingQuery = "UPDATE `account` SET `following_count` = `following_count` - 1 WHERE `id` = ? AND `following_count` > 0"
+		followersQuery = "UPDATE `account` SET `followers_count` = `followers_count` - 1 WHERE `id` = ? AND `followers_count` > 0"
+	)
+
+	db, mock, err := sqlxmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	r := &relationship{
+		db: db,
+	}
+
+	tests := []struct {
+		name    string
+		query   func(s sqlxmock.Sqlmock)
+		wantErr bool
+	}{
+		{
+			name: "unfollow",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectBegin()
+				s.ExpectExec(regexp.QuoteMeta(unfollowQuery)).
+					WithArgs(1, 2).
+					WillReturnResult(sqlxmock.NewResult(0, 1))
+				s.ExpectExec(regexp.QuoteMeta(followingQuery)).
+					WithArgs(1).
+					WillReturnResult(sqlxmock.NewResult(0, 1))
+				s.ExpectExec(regexp.QuoteMeta(followersQuery)).
+					WithArgs(2).
+					WillReturnResult(sqlxmock.NewResult(0, 1))
+				s.ExpectCommit()
+			},
+			wantErr: false,
+		},
+		{
+			name: "not following",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectBegin()
+				s.ExpectExec(regexp.QuoteMeta(unfollowQuery)).
+					WithArgs(1, 2).
+					WillReturnResult(sqlxmock.NewResult(0, 0))
+				s.ExpectRollback()
+			},
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tt.query(mock)
+			if err := r.Unfollow(context.Background(), 1, 2); (err != nil) != tt.wantErr {
+				t.Errorf("relationship.Unfollow() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/satorunooshie/Yatter/app/domain/object"
+	"github.com/satorunooshie/Yatter/app/domain/repository"
+)
+
+type (
+	// Implementation for repository.ScheduledStatus
+	scheduledStatus struct {
+		db  *sqlx.DB
+		ids IDGenerator
+	}
+)
+
+func NewScheduledStatus(db *sqlx.DB, ids IDGenerator) repository.ScheduledStatus {
+	return &scheduledStatus{db: db, ids: ids}
+}
+
+func (r *scheduledStatus) FindByID(ctx context.Context, id object.ScheduledStatusID) (*object.ScheduledStatus, error) {
+	entity := &object.ScheduledStatus{}
+	if err := r.db.QueryRowxContext(ctx, "SELECT * FROM `scheduled_status` WHERE `id` = ?", id).StructScan(entity); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return entity, nil
+}
+
+func (r *scheduledStatus) SelectByAccountID(ctx context.Context, accountID object.AccountID, limit int64) ([]*object.ScheduledStatus, error) {
+	return r.selectx(ctx, "SELECT * FROM `scheduled_status` WHERE `account_id` = ? ORDER BY `scheduled_at`, `id` LIMIT ?", accountID, limit)
+}
+
+func (r *scheduledStatus) SelectDue(ctx context.Context, until time.Time, limit int64) ([]*object.ScheduledStatus, error) {
+	return r.selectx(ctx, "SELECT * FROM `scheduled_status` WHERE `scheduled_at` <= ? ORDER BY `scheduled_at`, `id` LIMIT ?", until, limit)
+}
+
+func (r *scheduledStatus) Insert(ctx context.Context, accountID object.AccountID, scheduledAt time.Time, params object.StatusParams) (object.ScheduledStatusID, error) {
+	id := r.ids.Next()
+	if _, err := r.db.ExecContext(ctx, "INSERT INTO `scheduled_status` (`id`, `account_id`, `scheduled_at`, `params`) VALUES (?, ?, ?, ?)", id, accountID, scheduledAt, params); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r *scheduledStatus) Update(ctx context.Context, id object.ScheduledStatusID, accountID object.AccountID, scheduledAt time.Time) error {
+	res, err := r.db.ExecContext(ctx, "UPDATE `scheduled_status` SET `scheduled_at` = ? WHERE `id` = ? AND `account_id` = ?", scheduledAt, id, accountID)
+	if err != nil {
+		return err
+	}
+	updated, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if updated != 0 {
+		return nil
+	}
+
+	/* 日時が変わらない行も影響を受けた行数に含まれないので存在を確かめる */
+	var exists bool
+	if err := r.db.QueryRowxContext(ctx, "SELECT EXISTS(SELECT 1 FROM `scheduled_status` WHERE `id` = ? AND `account_id` = ?)", id, accountID).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return repository.ErrScheduledStatusNotFound
+	}
+	return nil
+}
+
+func (r *scheduledStatus) Delete(ctx context.Context, id object.ScheduledStatusID, accountID object.AccountID) error {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM `scheduled_status` WHERE `id` = ? AND `account_id` = ?", id, accountID)
+	if err != nil {
+		return err
+	}
+	deleted, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return repository.ErrScheduledStatusNotFound
+	}
+	return nil
+}
+
+func (r *scheduledStatus) Publish(ctx context.Context, id object.ScheduledStatusID, until time.Time, extras object.StatusExtras) (object.StatusID, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			log.Printf("[WARN] dao::scheduledStatus::Publish::tx.Rollback(): %v", err)
+		}
+	}()
+
+	/* 他のインスタンスが同時に公開しないように行をロックする */
+	entity := &object.ScheduledStatus{}
+	if err := tx.QueryRowxContext(ctx, "SELECT * FROM `scheduled_status` WHERE `id` = ? AND `scheduled_at` <= ? FOR UPDATE", id, until).StructScan(entity); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	statusID := r.ids.Next()
+	if _, err := tx.ExecContext(ctx, "INSERT INTO `status` (`id`, `account_id`, `content`, `visibility`) VALUES (?, ?, ?, ?)", statusID, entity.AccountID, entity.Params.Text, entity.Params.Visibility); err != nil {
+		return 0, err
+	}
+
+	if _, err := tx.ExecContext(ctx, incrementStatusesCountQuery, entity.AccountID); err != nil {
+		return 0, err
+	}
+
+	if err := insertExtras(ctx, tx, r.ids, entity.AccountID, statusID, extras); err != nil {
+		return 0, err
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM `scheduled_status` WHERE `id` = ?", id); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return statusID, nil
+}
+
+func (r *scheduledStatus) selectx(ctx context.Context, query string, args ...interface{}) ([]*object.ScheduledStatus, error) {
+	rows, err := r.db.QueryxContext(ctx, query, args...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("[WARN] dao::scheduledStatus::selectx::rows.Close(): %v", err)
+		}
+	}()
+
+	entities := make([]*object.ScheduledStatus, 0)
+	for rows.Next() {
+		entity := &object.ScheduledStatus{}
+		if err := rows.StructScan(&entity); err != nil {
+			return nil, err
+		}
+		entities = append(entities, entity)
+	}
+	return entities, nil
+}
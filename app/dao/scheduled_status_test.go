@@ -0,0 +1,383 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	sqlxmock "github.com/zhashkevych/go-sqlxmock"
+
+	"github.com/satorunooshie/Yatter/app/domain/object"
+	"github.com/satorunooshie/Yatter/app/domain/repository"
+)
+
+func Test_scheduledStatus_SelectDue(t *testing.T) {
+	createAt, _ := time.Parse("2006-01-02", "2020-01-01")
+	scheduledAt := createAt.Add(time.Hour)
+	const query = "SELECT * FROM `scheduled_status` WHERE `scheduled_at` <= ? ORDER BY `scheduled_at`, `id` LIMIT ?"
+
+	db, mock, err := sqlxmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	r := &scheduledStatus{
+		db:  db,
+		ids: &sequence{},
+	}
+
+	type args struct {
+		ctx   context.Context
+		until time.Time
+		limit int64
+	}
+	tests := []struct {
+		name    string
+		query   func(s sqlxmock.Sqlmock)
+		args    args
+		want    []*object.ScheduledStatus
+		wantErr bool
+	}{
+		{
+			name: "ok",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs(scheduledAt, 10).
+					WillReturnRows(
+						sqlxmock.NewRows(
+							[]string{
+								"id",
+								"account_id",
+								"scheduled_at",
+								"params",
+								"create_at",
+							},
+						).
+							AddRow(1, 1, scheduledAt, `{"text":"おはよう","visibility":"public"}`, createAt).
+							AddRow(2, 2, scheduledAt, `{"text":"どっち？","visibility":"private","poll":{"options":["a","b"],"expires_in":300,"multiple":false,"hide_totals":true}}`, createAt),
+					)
+			},
+			args: args{
+				ctx:   context.Background(),
+				until: scheduledAt,
+				limit: 10,
+			},
+			want: []*object.ScheduledStatus{
+				{
+					ID:          1,
+					AccountID:   1,
+					ScheduledAt: object.DateTime{Time: scheduledAt},
+					Params:      object.StatusParams{Text: "おはよう", Visibility: object.VisibilityPublic},
+					CreateAt:    object.DateTime{Time: createAt},
+				},
+				{
+					ID:          2,
+					AccountID:   2,
+					ScheduledAt: object.DateTime{Time: scheduledAt},
+					Params: object.StatusParams{
+						Text:       "どっち？",
+						Visibility: object.VisibilityPrivate,
+						Poll:       &object.PollParams{Options: []string{"a", "b"}, ExpiresIn: 300, HideTotals: true},
+					},
+					CreateAt: object.DateTime{Time: createAt},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "error",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs(scheduledAt, 10).
+					WillReturnError(errors.New("error"))
+			},
+			args: args{
+				ctx:   context.Background(),
+				until: scheduledAt,
+				limit: 10,
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tt.query(mock)
+			got, err := r.SelectDue(tt.args.ctx, tt.args.until, tt.args.limit)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("scheduledStatus.SelectDue() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Errorf("scheduledStatus.SelectDue() returned diff (want -> got):\n%s", diff)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func Test_scheduledStatus_Publish(t *testing.T) {
+	createAt, _ := time.Parse("2006-01-02", "2020-01-01")
+	now := createAt.Add(time.Hour)
+	const (
+		lockQuery   = "SELECT * FROM `scheduled_status` WHERE `id` = ? AND `scheduled_at` <= ? FOR UPDATE"
+		insertQuery = "INSERT INTO `status` (`id`, `account_id`, `content`, `visibility`) VALUES (?, ?, ?, ?)"
+		countQuery  = "UPDATE `account` SET `statuses_count` = `statuses_count` + 1, `last_status_at` = NOW() WHERE `id` = ?"
+		deleteQuery = "DELETE FROM `scheduled_status` WHERE `id` = ?"
+	)
+	columns := []string{"id", "account_id", "scheduled_at", "params", "create_at"}
+
+	db, mock, err := sqlxmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	r := &scheduledStatus{
+		db:  db,
+		ids: &sequence{},
+	}
+
+	type args struct {
+		ctx    context.Context
+		id     object.ScheduledStatusID
+		until  time.Time
+		extras object.StatusExtras
+	}
+	tests := []struct {
+		name    string
+		query   func(s sqlxmock.Sqlmock)
+		args    args
+		want    object.StatusID
+		wantErr bool
+	}{
+		{
+			name: "ok",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectBegin()
+				s.ExpectQuery(regexp.QuoteMeta(lockQuery)).
+					WithArgs(1, now).
+					WillReturnRows(sqlxmock.NewRows(columns).AddRow(1, 2, now, `{"text":"おはよう","visibility":"unlisted"}`, createAt))
+				s.ExpectExec(regexp.QuoteMeta(insertQuery)).
+					WithArgs(1, 2, "おはよう", object.VisibilityUnlisted).
+					WillReturnResult(sqlxmock.NewResult(0, 1))
+				s.ExpectExec(regexp.QuoteMeta(countQuery)).
+					WithArgs(2).
+					WillReturnResult(sqlxmock.NewResult(0, 1))
+				s.ExpectExec(regexp.QuoteMeta("INSERT INTO `mention` (`id`, `status_id`, `account_id`) VALUES (?, ?, ?)")).
+					WithArgs(2, 1, 3).
+					WillReturnResult(sqlxmock.NewResult(0, 1))
+				s.ExpectExec(regexp.QuoteMeta("INSERT IGNORE INTO `notification` (`id`, `account_id`, `type`, `from_account_id`, `status_id`) VALUES (?, ?, ?, ?, ?)")).
+					WithArgs(3, 3, object.NotificationMention, 2, 1).
+					WillReturnResult(sqlxmock.NewResult(0, 1))
+				s.ExpectExec(regexp.QuoteMeta(deleteQuery)).
+					WithArgs(1).
+					WillReturnResult(sqlxmock.NewResult(0, 1))
+				s.ExpectCommit()
+			},
+			args: args{
+				ctx:   context.Background(),
+				id:    1,
+				until: now,
+				extras: object.StatusExtras{
+					MentionedAccountIDs: []object.AccountID{3},
+					NotifiedAccountIDs:  []object.AccountID{3},
+				},
+			},
+			want:    1,
+			wantErr: false,
+		},
+		{
+			name: "already published",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectBegin()
+				s.ExpectQuery(regexp.QuoteMeta(lockQuery)).
+					WithArgs(1, now).
+					WillReturnRows(sqlxmock.NewRows(columns))
+				s.ExpectRollback()
+			},
+			args: args{
+				ctx:   context.Background(),
+				id:    1,
+				until: now,
+			},
+			want:    0,
+			wantErr: false,
+		},
+		{
+			name: "error",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectBegin()
+				s.ExpectQuery(regexp.QuoteMeta(lockQuery)).
+					WithArgs(1, now).
+					WillReturnRows(sqlxmock.NewRows(columns).AddRow(1, 2, now, `{"text":"おはよう","visibility":"unlisted"}`, createAt))
+				s.ExpectExec(regexp.QuoteMeta(insertQuery)).
+					WithArgs(sqlxmock.AnyArg(), 2, "おはよう", object.VisibilityUnlisted).
+					WillReturnError(errors.New("error"))
+				s.ExpectRollback()
+			},
+			args: args{
+				ctx:   context.Background(),
+				id:    1,
+				until: now,
+			},
+			want:    0,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tt.query(mock)
+			got, err := r.Publish(tt.args.ctx, tt.args.id, tt.args.until, tt.args.extras)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("scheduledStatus.Publish() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("scheduledStatus.Publish() = %v, want %v", got, tt.want)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func Test_scheduledStatus_Update(t *testing.T) {
+	scheduledAt, _ := time.Parse("2006-01-02", "2020-01-01")
+	const (
+		updateQuery = "UPDATE `scheduled_status` SET `scheduled_at` = ? WHERE `id` = ? AND `account_id` = ?"
+		existsQuery = "SELECT EXISTS(SELECT 1 FROM `scheduled_status` WHERE `id` = ? AND `account_id` = ?)"
+	)
+
+	db, mock, err := sqlxmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	r := &scheduledStatus{
+		db: db,
+	}
+
+	tests := []struct {
+		name    string
+		query   func(s sqlxmock.Sqlmock)
+		wantErr error
+	}{
+		{
+			name: "ok",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectExec(regexp.QuoteMeta(updateQuery)).
+					WithArgs(scheduledAt, 1, 2).
+					WillReturnResult(sqlxmock.NewResult(0, 1))
+			},
+			wantErr: nil,
+		},
+		{
+			name: "unchanged",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectExec(regexp.QuoteMeta(updateQuery)).
+					WithArgs(scheduledAt, 1, 2).
+					WillReturnResult(sqlxmock.NewResult(0, 0))
+				s.ExpectQuery(regexp.QuoteMeta(existsQuery)).
+					WithArgs(1, 2).
+					WillReturnRows(sqlxmock.NewRows([]string{"exists"}).AddRow(true))
+			},
+			wantErr: nil,
+		},
+		{
+			name: "not found",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectExec(regexp.QuoteMeta(updateQuery)).
+					WithArgs(scheduledAt, 1, 2).
+					WillReturnResult(sqlxmock.NewResult(0, 0))
+				s.ExpectQuery(regexp.QuoteMeta(existsQuery)).
+					WithArgs(1, 2).
+					WillReturnRows(sqlxmock.NewRows([]string{"exists"}).AddRow(false))
+			},
+			wantErr: repository.ErrScheduledStatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tt.query(mock)
+			if err := r.Update(context.Background(), 1, 2, scheduledAt); !errors.Is(err, tt.wantErr) {
+				t.Errorf("scheduledStatus.Update() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func Test_scheduledStatus_Delete(t *testing.T) {
+	const deleteQuery = "DELETE FROM `scheduled_status` WHERE `id` = ? AND `account_id` = ?"
+
+	db, mock, err := sqlxmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	r := &scheduledStatus{
+		db: db,
+	}
+
+	tests := []struct {
+		name    string
+		query   func(s sqlxmock.Sqlmock)
+		wantErr error
+	}{
+		{
+			name: "ok",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectExec(regexp.QuoteMeta(deleteQuery)).
+					WithArgs(1, 2).
+					WillReturnResult(sqlxmock.NewResult(0, 1))
+			},
+			wantErr: nil,
+		},
+		{
+			name: "not found",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectExec(regexp.QuoteMeta(deleteQuery)).
+					WithArgs(1, 2).
+					WillReturnResult(sqlxmock.NewResult(0, 0))
+			},
+			wantErr: repository.ErrScheduledStatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tt.query(mock)
+			if err := r.Delete(context.Background(), 1, 2); !errors.Is(err, tt.wantErr) {
+				t.Errorf("scheduledStatus.Delete() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/satorunooshie/Yatter/app/domain/content"
+	"github.com/satorunooshie/Yatter/app/domain/object"
+	"github.com/satorunooshie/Yatter/app/domain/repository"
+)
+
+/*
+ * 閲覧者が読めるステータスに絞る条件
+ * 公開・未収載、自分の投稿、フォローしている相手のフォロワー限定、メンションされた投稿
+ */
+const visibleStatusCondition = "(`s`.`visibility` IN (?, ?) OR `s`.`account_id` = ? " +
+	"OR `s`.`visibility` = ? AND EXISTS(SELECT 1 FROM `follow` AS `f` WHERE `f`.`follower_id` = ? AND `f`.`followee_id` = `s`.`account_id` AND `f`.`delete_at` IS NULL) " +
+	"OR `s`.`visibility` IN (?, ?) AND EXISTS(SELECT 1 FROM `mention` AS `n` WHERE `n`.`status_id` = `s`.`id` AND `n`.`account_id` = ?))"
+
+type (
+	// Implementation for repository.Search with FULLTEXT indexes using the ngram parser
+	search struct {
+		db *sqlx.DB
+	}
+)
+
+func NewSearch(db *sqlx.DB) repository.Search {
+	return &search{db: db}
+}
+
+func (r *search) Statuses(ctx context.Context, query object.SearchQuery, viewerID object.AccountID, page object.Page) ([]*object.Status, error) {
+	q := "SELECT `s`.* FROM `status` AS `s` WHERE `s`.`id` > ? AND `s`.`id` < ? AND `s`.`delete_at` IS NULL AND " + visibleStatusCondition
+	params := []interface{}{
+		page.MinID, page.MaxID,
+		object.VisibilityPublic, object.VisibilityUnlisted, viewerID,
+		object.VisibilityPrivate, viewerID,
+		object.VisibilityPrivate, object.VisibilityDirect, viewerID,
+	}
+	if len(query.Terms) != 0 {
+		q += " AND MATCH(`s`.`content`) AGAINST (? IN BOOLEAN MODE)"
+		params = append(params, booleanQuery(query.Terms))
+	}
+	if query.FromUsername != "" {
+		q += " AND `s`.`account_id` = (SELECT `a`.`id` FROM `account` AS `a` WHERE `a`.`username` = ? AND `a`.`domain` = ?)"
+		params = append(params, query.FromUsername, query.FromDomain)
+	}
+	if query.HasMedia {
+		q += " AND EXISTS(SELECT 1 FROM `media_attachment` AS `m` WHERE `m`.`status_id` = `s`.`id` AND `m`.`delete_at` IS NULL)"
+	}
+	q += " ORDER BY `s`.`id` " + order(page) + " LIMIT ?"
+	params = append(params, page.Limit)
+
+	rows, err := r.db.QueryxContext(ctx, q, params...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("[WARN] dao::search::Statuses::rows.Close(): %v", err)
+		}
+	}()
+
+	entities := make([]*object.Status, 0, page.Limit)
+	for rows.Next() {
+		entity := &object.Status{}
+		if err := rows.StructScan(&entity); err != nil {
+			return nil, err
+		}
+		entities = append(entities, entity)
+	}
+	newestFirst(page, entities)
+	return entities, nil
+}
+
+func (r *search) Accounts(ctx context.Context, query object.SearchQuery, page object.Page) ([]*object.Account, error) {
+	if len(query.Terms) == 0 {
+		return nil, nil
+	}
+
+	rows, err := r.db.QueryxContext(ctx, "SELECT * FROM `account` WHERE MATCH(`username`, `display_name`) AGAINST (? IN BOOLEAN MODE) "+
+		"AND `id` > ? AND `id` < ? AND `delete_at` IS NULL AND `suspend_at` IS NULL ORDER BY `id` "+order(page)+" LIMIT ?",
+		booleanQuery(query.Terms), page.MinID, page.MaxID, page.Limit)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("[WARN] dao::search::Accounts::rows.Close(): %v", err)
+		}
+	}()
+
+	entities := make([]*object.Account, 0, page.Limit)
+	for rows.Next() {
+		entity := &object.Account{}
+		if err := rows.StructScan(&entity); err != nil {
+			return nil, err
+		}
+		entities = append(entities, entity)
+	}
+	newestFirst(page, entities)
+	return entities, nil
+}
+
+func (r *search) Hashtags(ctx context.Context, query object.SearchQuery, page object.Page) ([]*object.Tag, error) {
+	if len(query.Terms) == 0 {
+		return nil, nil
+	}
+
+	/* タグ名は短いので全文検索ではなく UNIQUE インデックスで前方一致を取る */
+	prefix := likeEscaper.Replace(content.NormalizeTag(query.Terms[0]))
+	rows, err := r.db.QueryxContext(ctx, "SELECT * FROM `tag` WHERE `name` LIKE ? AND `id` > ? AND `id` < ? ORDER BY `id` "+order(page)+" LIMIT ?",
+		prefix+"%", page.MinID, page.MaxID, page.Limit)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("[WARN] dao::search::Hashtags::rows.Close(): %v", err)
+		}
+	}()
+
+	entities := make([]*object.Tag, 0, page.Limit)
+	for rows.Next() {
+		entity := &object.Tag{}
+		if err := rows.StructScan(&entity); err != nil {
+			return nil, err
+		}
+		entity.SetURL()
+		entities = append(entities, entity)
+	}
+	newestFirst(page, entities)
+	return entities, nil
+}
+
+func (r *search) AccountsByPrefix(ctx context.Context, prefix string, viewerID object.AccountID, limit int64) ([]*object.Account, error) {
+	/*
+	 * username は uq_username_domain, display_name は idx_display_name の範囲スキャンで候補を limit 件ずつ取り,
+	 * フォローしている相手は idx_follower_id_followee_id から別に取ってフォロー数が多くても先頭に来るようにする
+	 */
+	like := likeEscaper.Replace(prefix) + "%"
+	rows, err := r.db.QueryxContext(ctx, "SELECT `a`.* FROM `account` AS `a` INNER JOIN ("+
+		"(SELECT `x`.`id` FROM `follow` AS `f` INNER JOIN `account` AS `x` ON `x`.`id` = `f`.`followee_id` "+
+		"WHERE `f`.`follower_id` = ? AND `f`.`delete_at` IS NULL AND (`x`.`username` LIKE ? OR `x`.`display_name` LIKE ?) "+
+		"AND `x`.`delete_at` IS NULL AND `x`.`suspend_at` IS NULL ORDER BY `x`.`username` LIMIT ?) UNION "+
+		"(SELECT `id` FROM `account` WHERE `username` LIKE ? AND `delete_at` IS NULL AND `suspend_at` IS NULL ORDER BY `username` LIMIT ?) UNION "+
+		"(SELECT `id` FROM `account` WHERE `display_name` LIKE ? AND `delete_at` IS NULL AND `suspend_at` IS NULL ORDER BY `display_name` LIMIT ?)"+
+		") AS `c` ON `c`.`id` = `a`.`id` "+
+		"ORDER BY EXISTS(SELECT 1 FROM `follow` AS `f` WHERE `f`.`follower_id` = ? AND `f`.`followee_id` = `a`.`id` AND `f`.`delete_at` IS NULL) DESC, `a`.`username`, `a`.`id` LIMIT ?",
+		viewerID, like, like, limit, like, limit, like, limit, viewerID, limit)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("[WARN] dao::search::AccountsByPrefix::rows.Close(): %v", err)
+		}
+	}()
+
+	entities := make([]*object.Account, 0, limit)
+	for rows.Next() {
+		entity := &object.Account{}
+		if err := rows.StructScan(&entity); err != nil {
+			return nil, err
+		}
+		entities = append(entities, entity)
+	}
+	return entities, nil
+}
+
+// Escape the wildcards of LIKE
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// Build the query of MATCH() AGAINST (... IN BOOLEAN MODE) requiring all of the terms.
+//
+// Each term is a phrase, which the ngram parser matches as consecutive ngrams like a substring,
+// so that operators in the terms are not interpreted.
+func booleanQuery(terms []string) string {
+	phrases := make([]string, 0, len(terms))
+	for _, term := range terms {
+		phrases = append(phrases, `+"`+strings.ReplaceAll(term, `"`, " ")+`"`)
+	}
+	return strings.Join(phrases, " ")
+}
@@ -0,0 +1,395 @@
+package dao
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	sqlxmock "github.com/zhashkevych/go-sqlxmock"
+
+	"github.com/satorunooshie/Yatter/app/domain/object"
+)
+
+func Test_search_Statuses(t *testing.T) {
+	createAt, _ := time.Parse("2006-01-02", "2020-01-01")
+	const (
+		query      = "SELECT `s`.* FROM `status` AS `s` WHERE `s`.`id` > ? AND `s`.`id` < ? AND `s`.`delete_at` IS NULL AND " + visibleStatusCondition
+		matchQuery = " AND MATCH(`s`.`content`) AGAINST (? IN BOOLEAN MODE)"
+		fromQuery  = " AND `s`.`account_id` = (SELECT `a`.`id` FROM `account` AS `a` WHERE `a`.`username` = ? AND `a`.`domain` = ?)"
+		mediaQuery = " AND EXISTS(SELECT 1 FROM `media_attachment` AS `m` WHERE `m`.`status_id` = `s`.`id` AND `m`.`delete_at` IS NULL)"
+		orderQuery = " ORDER BY `s`.`id` DESC LIMIT ?"
+	)
+	visibilityArgs := []driver.Value{
+		object.VisibilityPublic, object.VisibilityUnlisted, 3,
+		object.VisibilityPrivate, 3,
+		object.VisibilityPrivate, object.VisibilityDirect, 3,
+	}
+	columns := []string{"id", "account_id", "content", "visibility", "create_at", "delete_at"}
+
+	db, mock, err := sqlxmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	r := &search{db: db}
+
+	type args struct {
+		ctx      context.Context
+		query    object.SearchQuery
+		viewerID object.AccountID
+		page     object.Page
+	}
+	tests := []struct {
+		name    string
+		query   func(s sqlxmock.Sqlmock)
+		args    args
+		want    []*object.Status
+		wantErr bool
+	}{
+		{
+			name: "terms",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectQuery(regexp.QuoteMeta(query + matchQuery + orderQuery)).
+					WithArgs(append(append([]driver.Value{0, 100}, visibilityArgs...), `+"寿司" +"a b"`, 2)...).
+					WillReturnRows(sqlxmock.NewRows(columns).AddRow(10, 1, "寿司 a\"b", object.VisibilityPublic, createAt, nil))
+			},
+			args: args{
+				ctx:      context.Background(),
+				query:    object.ParseSearchQuery(`寿司 a"b`),
+				viewerID: 3,
+				page:     object.Page{MinID: 0, MaxID: 100, Limit: 2},
+			},
+			want: []*object.Status{
+				{
+					ID:         10,
+					AccountID:  1,
+					Text:       "寿司 a\"b",
+					Visibility: object.VisibilityPublic,
+					CreateAt:   object.DateTime{Time: createAt},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "operators only",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectQuery(regexp.QuoteMeta(query + fromQuery + mediaQuery + orderQuery)).
+					WithArgs(append(append([]driver.Value{0, 100}, visibilityArgs...), "john", "remote.example", 2)...).
+					WillReturnRows(sqlxmock.NewRows(columns))
+			},
+			args: args{
+				ctx:      context.Background(),
+				query:    object.ParseSearchQuery("from:@john@remote.example has:media"),
+				viewerID: 3,
+				page:     object.Page{MinID: 0, MaxID: 100, Limit: 2},
+			},
+			want:    []*object.Status{},
+			wantErr: false,
+		},
+		{
+			name: "error",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectQuery(regexp.QuoteMeta(query + matchQuery + orderQuery)).
+					WithArgs(append(append([]driver.Value{0, 100}, visibilityArgs...), `+"寿司"`, 2)...).
+					WillReturnError(errors.New("error"))
+			},
+			args: args{
+				ctx:      context.Background(),
+				query:    object.ParseSearchQuery("寿司"),
+				viewerID: 3,
+				page:     object.Page{MinID: 0, MaxID: 100, Limit: 2},
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tt.query(mock)
+			got, err := r.Statuses(tt.args.ctx, tt.args.query, tt.args.viewerID, tt.args.page)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("search.Statuses() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Errorf("Statuses() returned diff (want -> got):\n%s", diff)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func Test_search_Accounts(t *testing.T) {
+	createAt, _ := time.Parse("2006-01-02", "2020-01-01")
+	const query = "SELECT * FROM `account` WHERE MATCH(`username`, `display_name`) AGAINST (? IN BOOLEAN MODE) " +
+		"AND `id` > ? AND `id` < ? AND `delete_at` IS NULL AND `suspend_at` IS NULL ORDER BY `id` DESC LIMIT ?"
+
+	db, mock, err := sqlxmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	r := &search{db: db}
+
+	type args struct {
+		ctx   context.Context
+		query object.SearchQuery
+		page  object.Page
+	}
+	tests := []struct {
+		name    string
+		query   func(s sqlxmock.Sqlmock)
+		args    args
+		want    []*object.Account
+		wantErr bool
+	}{
+		{
+			name: "ok",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs(`+"jo"`, 0, 100, 2).
+					WillReturnRows(sqlxmock.NewRows([]string{"id", "username", "create_at"}).AddRow(1, "john", createAt))
+			},
+			args: args{
+				ctx:   context.Background(),
+				query: object.ParseSearchQuery("jo has:media"),
+				page:  object.Page{MinID: 0, MaxID: 100, Limit: 2},
+			},
+			want: []*object.Account{
+				{
+					ID:       1,
+					Username: "john",
+					CreateAt: object.DateTime{Time: createAt},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "no terms",
+			query: func(s sqlxmock.Sqlmock) {},
+			args: args{
+				ctx:   context.Background(),
+				query: object.ParseSearchQuery("from:john"),
+				page:  object.Page{MinID: 0, MaxID: 100, Limit: 2},
+			},
+			want:    nil,
+			wantErr: false,
+		},
+		{
+			name: "error",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs(`+"jo"`, 0, 100, 2).
+					WillReturnError(errors.New("error"))
+			},
+			args: args{
+				ctx:   context.Background(),
+				query: object.ParseSearchQuery("jo"),
+				page:  object.Page{MinID: 0, MaxID: 100, Limit: 2},
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tt.query(mock)
+			got, err := r.Accounts(tt.args.ctx, tt.args.query, tt.args.page)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("search.Accounts() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Errorf("Accounts() returned diff (want -> got):\n%s", diff)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func Test_search_Hashtags(t *testing.T) {
+	createAt, _ := time.Parse("2006-01-02", "2020-01-01")
+	const query = "SELECT * FROM `tag` WHERE `name` LIKE ? AND `id` > ? AND `id` < ? ORDER BY `id` ASC LIMIT ?"
+
+	db, mock, err := sqlxmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	r := &search{db: db}
+
+	type args struct {
+		ctx   context.Context
+		query object.SearchQuery
+		page  object.Page
+	}
+	tests := []struct {
+		name    string
+		query   func(s sqlxmock.Sqlmock)
+		args    args
+		want    []*object.Tag
+		wantErr bool
+	}{
+		{
+			name: "ok",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs(`go\_%`, 1, 100, 2).
+					WillReturnRows(sqlxmock.NewRows([]string{"id", "name", "create_at"}).
+						AddRow(2, "go_lang", createAt).
+						AddRow(3, "go_fmt", createAt))
+			},
+			args: args{
+				ctx:   context.Background(),
+				query: object.ParseSearchQuery("#Go_"),
+				page:  object.Page{MinID: 1, MaxID: 100, Limit: 2, Ascending: true},
+			},
+			want: []*object.Tag{
+				{ID: 3, Name: "go_fmt", CreateAt: object.DateTime{Time: createAt}, URL: "/v1/timelines/tag/go_fmt"},
+				{ID: 2, Name: "go_lang", CreateAt: object.DateTime{Time: createAt}, URL: "/v1/timelines/tag/go_lang"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "error",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs(`go%`, 1, 100, 2).
+					WillReturnError(errors.New("error"))
+			},
+			args: args{
+				ctx:   context.Background(),
+				query: object.ParseSearchQuery("go"),
+				page:  object.Page{MinID: 1, MaxID: 100, Limit: 2, Ascending: true},
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tt.query(mock)
+			got, err := r.Hashtags(tt.args.ctx, tt.args.query, tt.args.page)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("search.Hashtags() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Errorf("Hashtags() returned diff (want -> got):\n%s", diff)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func Test_search_AccountsByPrefix(t *testing.T) {
+	createAt, _ := time.Parse("2006-01-02", "2020-01-01")
+	const query = "SELECT `a`.* FROM `account` AS `a` INNER JOIN (" +
+		"(SELECT `x`.`id` FROM `follow` AS `f` INNER JOIN `account` AS `x` ON `x`.`id` = `f`.`followee_id` " +
+		"WHERE `f`.`follower_id` = ? AND `f`.`delete_at` IS NULL AND (`x`.`username` LIKE ? OR `x`.`display_name` LIKE ?) " +
+		"AND `x`.`delete_at` IS NULL AND `x`.`suspend_at` IS NULL ORDER BY `x`.`username` LIMIT ?) UNION " +
+		"(SELECT `id` FROM `account` WHERE `username` LIKE ? AND `delete_at` IS NULL AND `suspend_at` IS NULL ORDER BY `username` LIMIT ?) UNION " +
+		"(SELECT `id` FROM `account` WHERE `display_name` LIKE ? AND `delete_at` IS NULL AND `suspend_at` IS NULL ORDER BY `display_name` LIMIT ?)" +
+		") AS `c` ON `c`.`id` = `a`.`id` " +
+		"ORDER BY EXISTS(SELECT 1 FROM `follow` AS `f` WHERE `f`.`follower_id` = ? AND `f`.`followee_id` = `a`.`id` AND `f`.`delete_at` IS NULL) DESC, `a`.`username`, `a`.`id` LIMIT ?"
+
+	db, mock, err := sqlxmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	r := &search{db: db}
+
+	type args struct {
+		ctx      context.Context
+		prefix   string
+		viewerID object.AccountID
+		limit    int64
+	}
+	tests := []struct {
+		name    string
+		query   func(s sqlxmock.Sqlmock)
+		args    args
+		want    []*object.Account
+		wantErr bool
+	}{
+		{
+			name: "ok",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs(3, `j\_%`, `j\_%`, 2, `j\_%`, 2, `j\_%`, 2, 3, 2).
+					WillReturnRows(sqlxmock.NewRows([]string{"id", "username", "create_at"}).
+						AddRow(2, "j_ane", createAt).
+						AddRow(1, "j_ohn", createAt))
+			},
+			args: args{
+				ctx:      context.Background(),
+				prefix:   "j_",
+				viewerID: 3,
+				limit:    2,
+			},
+			want: []*object.Account{
+				{ID: 2, Username: "j_ane", CreateAt: object.DateTime{Time: createAt}},
+				{ID: 1, Username: "j_ohn", CreateAt: object.DateTime{Time: createAt}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "error",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs(0, `j%`, `j%`, 2, `j%`, 2, `j%`, 2, 0, 2).
+					WillReturnError(errors.New("error"))
+			},
+			args: args{
+				ctx:    context.Background(),
+				prefix: "j",
+				limit:  2,
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tt.query(mock)
+			got, err := r.AccountsByPrefix(tt.args.ctx, tt.args.prefix, tt.args.viewerID, tt.args.limit)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("search.AccountsByPrefix() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Errorf("AccountsByPrefix() returned diff (want -> got):\n%s", diff)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
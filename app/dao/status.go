@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"log"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 
@@ -12,15 +13,19 @@ import (
 	"github.com/satorunooshie/Yatter/app/domain/repository"
 )
 
+// Count a new status of the account, which must run in the same transaction as the insert
+const incrementStatusesCountQuery = "UPDATE `account` SET `statuses_count` = `statuses_count` + 1, `last_status_at` = NOW() WHERE `id` = ?"
+
 type (
 	// Implementation for repository.Status
 	status struct {
-		db *sqlx.DB
+		db  *sqlx.DB
+		ids IDGenerator
 	}
 )
 
-func NewStatus(db *sqlx.DB) repository.Status {
-	return &status{db: db}
+func NewStatus(db *sqlx.DB, ids IDGenerator) repository.Status {
+	return &status{db: db, ids: ids}
 }
 
 func (r *status) FindByID(ctx context.Context, id object.StatusID) (*object.Status, error) {
@@ -64,8 +69,19 @@ func (r *status) FindByIDs(ctx context.Context, ids []object.StatusID) ([]*objec
 	return entities, nil
 }
 
-func (r *status) Select(ctx context.Context, minID, maxID, limit int64) ([]*object.Status, error) {
-	rows, err := r.db.QueryxContext(ctx, "SELECT * FROM `status` WHERE `id` BETWEEN ? AND ? AND `delete_at` IS NULL ORDER BY `create_at` DESC LIMIT ?", minID, maxID, limit)
+func (r *status) FindByURI(ctx context.Context, uri string) (*object.Status, error) {
+	entity := &object.Status{}
+	if err := r.db.QueryRowxContext(ctx, "SELECT * FROM `status` WHERE `uri` = ? AND `delete_at` IS NULL", uri).StructScan(entity); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return entity, nil
+}
+
+func (r *status) Select(ctx context.Context, page object.Page) ([]*object.Status, error) {
+	rows, err := r.db.QueryxContext(ctx, "SELECT * FROM `status` WHERE `visibility` = ? AND `id` > ? AND `id` < ? AND `delete_at` IS NULL ORDER BY `id` "+order(page)+" LIMIT ?", object.VisibilityPublic, page.MinID, page.MaxID, page.Limit)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
@@ -79,7 +95,7 @@ func (r *status) Select(ctx context.Context, minID, maxID, limit int64) ([]*obje
 		}
 	}()
 
-	entities := make([]*object.Status, 0, limit)
+	entities := make([]*object.Status, 0, page.Limit)
 	for rows.Next() {
 		entity := &object.Status{}
 		if err := rows.StructScan(&entity); err != nil {
@@ -87,48 +103,247 @@ func (r *status) Select(ctx context.Context, minID, maxID, limit int64) ([]*obje
 		}
 		entities = append(entities, entity)
 	}
+	newestFirst(page, entities)
 	return entities, nil
 }
 
-func (r *status) Insert(ctx context.Context, accountID object.AccountID, content string) (object.StatusID, error) {
-	stmt, err := r.db.PreparexContext(ctx, "INSERT INTO `status` (`account_id`, `content`) VALUES (?, ?)")
+func (r *status) SelectByTag(ctx context.Context, tag string, onlyMedia bool, page object.Page) ([]*object.Status, error) {
+	query := "SELECT `s`.* FROM `status` AS `s` INNER JOIN `status_tag` AS `st` ON `st`.`status_id` = `s`.`id` INNER JOIN `tag` AS `t` ON `t`.`id` = `st`.`tag_id` " +
+		"WHERE `t`.`name` = ? AND `st`.`status_id` > ? AND `st`.`status_id` < ? AND `s`.`visibility` = ? AND `s`.`delete_at` IS NULL"
+	if onlyMedia {
+		query += " AND EXISTS(SELECT 1 FROM `media_attachment` AS `m` WHERE `m`.`status_id` = `s`.`id` AND `m`.`delete_at` IS NULL)"
+	}
+	query += " ORDER BY `st`.`status_id` " + order(page) + " LIMIT ?"
+
+	rows, err := r.db.QueryxContext(ctx, query, tag, page.MinID, page.MaxID, object.VisibilityPublic, page.Limit)
 	if err != nil {
-		return 0, err
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("[WARN] dao::status::SelectByTag::rows.Close(): %v", err)
+		}
+	}()
+
+	entities := make([]*object.Status, 0, page.Limit)
+	for rows.Next() {
+		entity := &object.Status{}
+		if err := rows.StructScan(&entity); err != nil {
+			return nil, err
+		}
+		entities = append(entities, entity)
+	}
+	newestFirst(page, entities)
+	return entities, nil
+}
+
+func (r *status) SelectByAccountID(ctx context.Context, accountID object.AccountID, visibilities []object.Visibility, onlyMedia, excludePinned bool, page object.Page) ([]*object.Status, error) {
+	/* ORDER BY `id` で PRIMARY を選ばれないように idx_account_id を使わせる */
+	query := "SELECT * FROM `status` FORCE INDEX (`idx_account_id`) WHERE `account_id` = ? AND `id` > ? AND `id` < ? AND `visibility` IN (?) AND `delete_at` IS NULL"
+	if onlyMedia {
+		query += " AND EXISTS(SELECT 1 FROM `media_attachment` AS `m` WHERE `m`.`status_id` = `status`.`id` AND `m`.`delete_at` IS NULL)"
+	}
+	if excludePinned {
+		query += " AND NOT EXISTS(SELECT 1 FROM `pinned_status` AS `p` WHERE `p`.`status_id` = `status`.`id`)"
+	}
+	query += " ORDER BY `id` " + order(page) + " LIMIT ?"
+
+	query, params, err := sqlx.In(query, accountID, page.MinID, page.MaxID, visibilities, page.Limit)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.db.QueryxContext(ctx, query, params...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
 	}
 
 	defer func() {
-		if err := stmt.Close(); err != nil {
-			log.Printf("[WARN] dao::status::Insert::stmt.Close(): %v", err)
+		if err := rows.Close(); err != nil {
+			log.Printf("[WARN] dao::status::SelectByAccountID::rows.Close(): %v", err)
 		}
 	}()
 
-	res, err := stmt.ExecContext(ctx, accountID, content)
+	entities := make([]*object.Status, 0, page.Limit)
+	for rows.Next() {
+		entity := &object.Status{}
+		if err := rows.StructScan(&entity); err != nil {
+			return nil, err
+		}
+		entities = append(entities, entity)
+	}
+	newestFirst(page, entities)
+	return entities, nil
+}
+
+func (r *status) Insert(ctx context.Context, accountID object.AccountID, content string, visibility object.Visibility, extras object.StatusExtras) (object.StatusID, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return 0, err
 	}
 
-	id, err := res.LastInsertId()
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			log.Printf("[WARN] dao::status::Insert::tx.Rollback(): %v", err)
+		}
+	}()
+
+	id := r.ids.Next()
+	if _, err := tx.ExecContext(ctx, "INSERT INTO `status` (`id`, `account_id`, `content`, `visibility`) VALUES (?, ?, ?, ?)", id, accountID, content, visibility); err != nil {
+		return 0, err
+	}
+
+	if _, err := tx.ExecContext(ctx, incrementStatusesCountQuery, accountID); err != nil {
+		return 0, err
+	}
+
+	if err := insertExtras(ctx, tx, r.ids, accountID, id, extras); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r *status) InsertRemote(ctx context.Context, accountID object.AccountID, uri, content string, visibility object.Visibility, createAt time.Time) (object.StatusID, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return 0, err
 	}
 
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			log.Printf("[WARN] dao::status::InsertRemote::tx.Rollback(): %v", err)
+		}
+	}()
+
+	/* 受け取った順に並べるため、ID は作成日時ではなく受け取った時刻から作る */
+	id := r.ids.Next()
+	if _, err := tx.ExecContext(ctx, "INSERT INTO `status` (`id`, `account_id`, `uri`, `content`, `visibility`, `create_at`) VALUES (?, ?, ?, ?, ?, ?)", id, accountID, uri, content, visibility, createAt); err != nil {
+		return 0, err
+	}
+
+	if _, err := tx.ExecContext(ctx, incrementStatusesCountQuery, accountID); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
 	return id, nil
 }
 
+func (r *status) Update(ctx context.Context, id object.StatusID, accountID object.AccountID, content string, prevMediaAttachmentIDs, keepMediaAttachmentIDs []object.MediaAttachmentID, extras object.StatusExtras) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			log.Printf("[WARN] dao::status::Update::tx.Rollback(): %v", err)
+		}
+	}()
+
+	/* UPDATE は内容が変わらないと 0 行になるので、版を残せたかでステータスがあるか確かめる */
+	res, err := tx.ExecContext(ctx, "INSERT INTO `status_edit` (`id`, `status_id`, `content`, `media_attachment_ids`, `create_at`) "+
+		"SELECT ?, `id`, `content`, ?, COALESCE(`edit_at`, `create_at`) FROM `status` WHERE `id` = ? AND `account_id` = ? AND `delete_at` IS NULL",
+		r.ids.Next(), object.MediaAttachmentIDs(prevMediaAttachmentIDs), id, accountID)
+	if err != nil {
+		return err
+	}
+	found, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if found == 0 {
+		return repository.ErrStatusNotFound
+	}
+
+	if _, err := tx.ExecContext(ctx, "UPDATE `status` SET `content` = ?, `edit_at` = NOW() WHERE `id` = ? AND `account_id` = ? AND `delete_at` IS NULL", content, id, accountID); err != nil {
+		return err
+	}
+
+	if keepMediaAttachmentIDs != nil {
+		if err := detachMedia(ctx, tx, id, keepMediaAttachmentIDs); err != nil {
+			return err
+		}
+	}
+
+	/* 本文から抽出し直したメンションとタグに置き換える */
+	if _, err := tx.ExecContext(ctx, "DELETE FROM `mention` WHERE `status_id` = ?", id); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM `status_tag` WHERE `status_id` = ?", id); err != nil {
+		return err
+	}
+	if err := insertExtras(ctx, tx, r.ids, accountID, id, extras); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
 func (r *status) Delete(ctx context.Context, id object.StatusID, accountID object.AccountID) error {
-	stmt, err := r.db.PrepareContext(ctx, "UPDATE `status` SET `delete_at` = NOW() WHERE `id` = ? AND `account_id` = ?")
+	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return err
 	}
 
 	defer func() {
-		if err := stmt.Close(); err != nil {
-			log.Printf("[WARN] dao::status::Delete::stmt.Close(): %v", err)
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			log.Printf("[WARN] dao::status::Delete::tx.Rollback(): %v", err)
 		}
 	}()
 
-	if _, err := stmt.ExecContext(ctx, id, accountID); err != nil {
+	res, err := tx.ExecContext(ctx, "UPDATE `status` SET `delete_at` = NOW() WHERE `id` = ? AND `account_id` = ? AND `delete_at` IS NULL", id, accountID)
+	if err != nil {
 		return err
 	}
-	return nil
+	deleted, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return nil
+	}
+
+	if _, err := tx.ExecContext(ctx, "UPDATE `account` SET `statuses_count` = `statuses_count` - 1 WHERE `id` = ? AND `statuses_count` > 0", accountID); err != nil {
+		return err
+	}
+	/* 最新のステータスが消えたときのために残りから求め直す */
+	if _, err := tx.ExecContext(ctx, "UPDATE `account` SET `last_status_at` = (SELECT MAX(`create_at`) FROM `status` WHERE `account_id` = ? AND `delete_at` IS NULL) WHERE `id` = ?", accountID, accountID); err != nil {
+		return err
+	}
+
+	/* 削除されたステータスの固定を外す */
+	if _, err := tx.ExecContext(ctx, "DELETE FROM `pinned_status` WHERE `status_id` = ? AND `account_id` = ?", id, accountID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Store mentions, tags and the poll of a new status in the transaction, notifying the mentioned accounts
+func insertExtras(ctx context.Context, tx *sqlx.Tx, ids IDGenerator, accountID object.AccountID, statusID object.StatusID, extras object.StatusExtras) error {
+	if err := insertMentions(ctx, tx, ids, statusID, extras.MentionedAccountIDs); err != nil {
+		return err
+	}
+	if _, err := attachTags(ctx, tx, ids, statusID, extras.Tags); err != nil {
+		return err
+	}
+	if p := extras.Poll; p != nil {
+		if err := insertPoll(ctx, tx, ids, ids.Next(), statusID, p.Options, extras.PollExpireAt, p.Multiple, p.HideTotals); err != nil {
+			return err
+		}
+	}
+	return insertNotifications(ctx, tx, ids, object.NotificationMention, accountID, statusID, extras.NotifiedAccountIDs)
 }
@@ -0,0 +1,50 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/satorunooshie/Yatter/app/domain/object"
+	"github.com/satorunooshie/Yatter/app/domain/repository"
+)
+
+type (
+	// Implementation for repository.StatusEdit
+	statusEdit struct {
+		db *sqlx.DB
+	}
+)
+
+func NewStatusEdit(db *sqlx.DB) repository.StatusEdit {
+	return &statusEdit{db: db}
+}
+
+func (r *statusEdit) FindByStatusID(ctx context.Context, statusID object.StatusID) ([]*object.StatusEdit, error) {
+	rows, err := r.db.QueryxContext(ctx, "SELECT * FROM `status_edit` WHERE `status_id` = ? ORDER BY `id`", statusID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("[WARN] dao::status_edit::FindByStatusID::rows.Close(): %v", err)
+		}
+	}()
+
+	entities := make([]*object.StatusEdit, 0)
+	for rows.Next() {
+		entity := &object.StatusEdit{}
+		if err := rows.StructScan(&entity); err != nil {
+			return nil, err
+		}
+		entities = append(entities, entity)
+	}
+	return entities, nil
+}
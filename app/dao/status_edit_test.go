@@ -0,0 +1,115 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	sqlxmock "github.com/zhashkevych/go-sqlxmock"
+
+	"github.com/satorunooshie/Yatter/app/domain/object"
+)
+
+func Test_statusEdit_FindByStatusID(t *testing.T) {
+	createAt, _ := time.Parse("2006-01-02", "2020-01-01")
+
+	db, mock, err := sqlxmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	r := &statusEdit{
+		db: db,
+	}
+
+	type args struct {
+		ctx      context.Context
+		statusID object.StatusID
+	}
+	tests := []struct {
+		name    string
+		query   func(s sqlxmock.Sqlmock)
+		args    args
+		want    []*object.StatusEdit
+		wantErr bool
+	}{
+		{
+			name: "ok",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `status_edit` WHERE `status_id` = ? ORDER BY `id`")).
+					WithArgs(1).
+					WillReturnRows(
+						sqlxmock.NewRows(
+							[]string{
+								"id",
+								"status_id",
+								"content",
+								"media_attachment_ids",
+								"create_at",
+							},
+						).
+							AddRow(1, 1, "first", "[1,2]", createAt).
+							AddRow(2, 1, "second", "[]", createAt),
+					)
+			},
+			args: args{
+				ctx:      context.Background(),
+				statusID: 1,
+			},
+			want: []*object.StatusEdit{
+				{
+					ID:                 1,
+					StatusID:           1,
+					Text:               "first",
+					MediaAttachmentIDs: object.MediaAttachmentIDs{1, 2},
+					CreateAt:           object.DateTime{Time: createAt},
+				},
+				{
+					ID:                 2,
+					StatusID:           1,
+					Text:               "second",
+					MediaAttachmentIDs: object.MediaAttachmentIDs{},
+					CreateAt:           object.DateTime{Time: createAt},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "error",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `status_edit` WHERE `status_id` = ? ORDER BY `id`")).
+					WithArgs(1).
+					WillReturnError(errors.New("error"))
+			},
+			args: args{
+				ctx:      context.Background(),
+				statusID: 1,
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tt.query(mock)
+			got, err := r.FindByStatusID(tt.args.ctx, tt.args.statusID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("statusEdit.FindByStatusID() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Errorf("statusEdit.FindByStatusID() returned diff (want -> got):\n%s", diff)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
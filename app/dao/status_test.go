@@ -25,7 +25,8 @@ func Test_status_FindByID(t *testing.T) {
 	}()
 
 	r := &status{
-		db: db,
+		db:  db,
+		ids: &sequence{},
 	}
 
 	type args struct {
@@ -64,7 +65,7 @@ func Test_status_FindByID(t *testing.T) {
 			want: &object.Status{
 				ID:        1,
 				AccountID: 1,
-				Content:   "content",
+				Text:      "content",
 				CreateAt:  object.DateTime{Time: createAt},
 				DeleteAt:  nil,
 			},
@@ -139,7 +140,8 @@ func Test_status_FindByIDs(t *testing.T) {
 	}()
 
 	r := &status{
-		db: db,
+		db:  db,
+		ids: &sequence{},
 	}
 
 	type args struct {
@@ -181,21 +183,21 @@ func Test_status_FindByIDs(t *testing.T) {
 				{
 					ID:        1,
 					AccountID: 1,
-					Content:   "content1",
+					Text:      "content1",
 					CreateAt:  object.DateTime{Time: createAt},
 					DeleteAt:  nil,
 				},
 				{
 					ID:        2,
 					AccountID: 2,
-					Content:   "content2",
+					Text:      "content2",
 					CreateAt:  object.DateTime{Time: createAt},
 					DeleteAt:  nil,
 				},
 				{
 					ID:        3,
 					AccountID: 3,
-					Content:   "content3",
+					Text:      "content3",
 					CreateAt:  object.DateTime{Time: createAt},
 					DeleteAt:  nil,
 				},
@@ -272,14 +274,13 @@ func Test_status_Select(t *testing.T) {
 	}()
 
 	r := &status{
-		db: db,
+		db:  db,
+		ids: &sequence{},
 	}
 
 	type args struct {
-		ctx   context.Context
-		minID object.StatusID
-		maxID object.StatusID
-		limit int64
+		ctx  context.Context
+		page object.Page
 	}
 	tests := []struct {
 		name    string
@@ -291,8 +292,8 @@ func Test_status_Select(t *testing.T) {
 		{
 			name: "ok",
 			query: func(s sqlxmock.Sqlmock) {
-				s.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `status` WHERE `id` BETWEEN ? AND ? AND `delete_at` IS NULL ORDER BY `create_at` DESC LIMIT ?")).
-					WithArgs(1, 100, 2).
+				s.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `status` WHERE `visibility` = ? AND `id` > ? AND `id` < ? AND `delete_at` IS NULL ORDER BY `id` DESC LIMIT ?")).
+					WithArgs(object.VisibilityPublic, 1, 100, 2).
 					WillReturnRows(
 						sqlxmock.NewRows(
 							[]string{
@@ -308,23 +309,21 @@ func Test_status_Select(t *testing.T) {
 					)
 			},
 			args: args{
-				ctx:   context.Background(),
-				minID: 1,
-				maxID: 100,
-				limit: 2,
+				ctx:  context.Background(),
+				page: object.Page{MinID: 1, MaxID: 100, Limit: 2},
 			},
 			want: []*object.Status{
 				{
 					ID:        1,
 					AccountID: 1,
-					Content:   "content1",
+					Text:      "content1",
 					CreateAt:  object.DateTime{Time: createAt},
 					DeleteAt:  nil,
 				},
 				{
 					ID:        2,
 					AccountID: 2,
-					Content:   "content2",
+					Text:      "content2",
 					CreateAt:  object.DateTime{Time: createAt},
 					DeleteAt:  nil,
 				},
@@ -333,8 +332,8 @@ func Test_status_Select(t *testing.T) {
 		{
 			name: "no rows",
 			query: func(s sqlxmock.Sqlmock) {
-				s.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `status` WHERE `id` BETWEEN ? AND ? AND `delete_at` IS NULL ORDER BY `create_at` DESC LIMIT ?")).
-					WithArgs(1, 100, 2).
+				s.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `status` WHERE `visibility` = ? AND `id` > ? AND `id` < ? AND `delete_at` IS NULL ORDER BY `id` DESC LIMIT ?")).
+					WithArgs(object.VisibilityPublic, 1, 100, 2).
 					WillReturnRows(
 						sqlxmock.NewRows(
 							[]string{
@@ -348,10 +347,8 @@ func Test_status_Select(t *testing.T) {
 					)
 			},
 			args: args{
-				ctx:   context.Background(),
-				minID: 1,
-				maxID: 100,
-				limit: 2,
+				ctx:  context.Background(),
+				page: object.Page{MinID: 1, MaxID: 100, Limit: 2},
 			},
 			want:    []*object.Status{},
 			wantErr: false,
@@ -359,15 +356,13 @@ func Test_status_Select(t *testing.T) {
 		{
 			name: "error",
 			query: func(s sqlxmock.Sqlmock) {
-				s.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `status` WHERE `id` BETWEEN ? AND ? AND `delete_at` IS NULL ORDER BY `create_at` DESC LIMIT ?")).
-					WithArgs(1, 100, 2).
+				s.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `status` WHERE `visibility` = ? AND `id` > ? AND `id` < ? AND `delete_at` IS NULL ORDER BY `id` DESC LIMIT ?")).
+					WithArgs(object.VisibilityPublic, 1, 100, 2).
 					WillReturnError(errors.New("error"))
 			},
 			args: args{
-				ctx:   context.Background(),
-				minID: 1,
-				maxID: 100,
-				limit: 2,
+				ctx:  context.Background(),
+				page: object.Page{MinID: 1, MaxID: 100, Limit: 2},
 			},
 			want:    nil,
 			wantErr: true,
@@ -377,7 +372,7 @@ func Test_status_Select(t *testing.T) {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
 			tt.query(mock)
-			got, err := r.Select(tt.args.ctx, tt.args.minID, tt.args.maxID, tt.args.limit)
+			got, err := r.Select(tt.args.ctx, tt.args.page)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("status.Select() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -402,13 +397,16 @@ func Test_status_Insert(t *testing.T) {
 	}()
 
 	r := &status{
-		db: db,
+		db:  db,
+		ids: &sequence{},
 	}
 
 	type args struct {
-		ctx       context.Context
-		accountID object.AccountID
-		content   string
+		ctx        context.Context
+		accountID  object.AccountID
+		content    string
+		visibility object.Visibility
+		extras     object.StatusExtras
 	}
 	tests := []struct {
 		name    string
@@ -420,31 +418,97 @@ func Test_status_Insert(t *testing.T) {
 		{
 			name: "ok",
 			query: func(s sqlxmock.Sqlmock) {
-				s.ExpectPrepare(regexp.QuoteMeta("INSERT INTO `status` (`account_id`, `content`) VALUES (?, ?)")).
-					ExpectExec().
-					WithArgs(1, "content").
-					WillReturnResult(sqlxmock.NewResult(1, 1))
+				s.ExpectBegin()
+				s.ExpectExec(regexp.QuoteMeta("INSERT INTO `status` (`id`, `account_id`, `content`, `visibility`) VALUES (?, ?, ?, ?)")).
+					WithArgs(1, 1, "content", object.VisibilityPrivate).
+					WillReturnResult(sqlxmock.NewResult(0, 1))
+				s.ExpectExec(regexp.QuoteMeta("UPDATE `account` SET `statuses_count` = `statuses_count` + 1, `last_status_at` = NOW() WHERE `id` = ?")).
+					WithArgs(1).
+					WillReturnResult(sqlxmock.NewResult(0, 1))
+				s.ExpectCommit()
 			},
 			args: args{
-				ctx:       context.Background(),
-				accountID: 1,
-				content:   "content",
+				ctx:        context.Background(),
+				accountID:  1,
+				content:    "content",
+				visibility: object.VisibilityPrivate,
 			},
 			want:    1,
 			wantErr: false,
 		},
+		{
+			name: "mentions",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectBegin()
+				s.ExpectExec(regexp.QuoteMeta("INSERT INTO `status` (`id`, `account_id`, `content`, `visibility`) VALUES (?, ?, ?, ?)")).
+					WithArgs(2, 1, "@john @JOHN @jane", object.VisibilityPublic).
+					WillReturnResult(sqlxmock.NewResult(0, 1))
+				s.ExpectExec(regexp.QuoteMeta("UPDATE `account` SET `statuses_count` = `statuses_count` + 1, `last_status_at` = NOW() WHERE `id` = ?")).
+					WithArgs(1).
+					WillReturnResult(sqlxmock.NewResult(0, 1))
+				s.ExpectExec(regexp.QuoteMeta("INSERT INTO `mention` (`id`, `status_id`, `account_id`) VALUES (?, ?, ?), (?, ?, ?)")).
+					WithArgs(3, 2, 1, 4, 2, 3).
+					WillReturnResult(sqlxmock.NewResult(0, 2))
+				s.ExpectExec(regexp.QuoteMeta("INSERT IGNORE INTO `notification` (`id`, `account_id`, `type`, `from_account_id`, `status_id`) VALUES (?, ?, ?, ?, ?)")).
+					WithArgs(5, 3, object.NotificationMention, 1, 2).
+					WillReturnResult(sqlxmock.NewResult(0, 1))
+				s.ExpectCommit()
+			},
+			args: args{
+				ctx:        context.Background(),
+				accountID:  1,
+				content:    "@john @JOHN @jane",
+				visibility: object.VisibilityPublic,
+				extras: object.StatusExtras{
+					MentionedAccountIDs: []object.AccountID{1, 3},
+					NotifiedAccountIDs:  []object.AccountID{3},
+				},
+			},
+			want:    2,
+			wantErr: false,
+		},
+		{
+			name: "mention error",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectBegin()
+				s.ExpectExec(regexp.QuoteMeta("INSERT INTO `status` (`id`, `account_id`, `content`, `visibility`) VALUES (?, ?, ?, ?)")).
+					WithArgs(sqlxmock.AnyArg(), 1, "@jane", object.VisibilityPublic).
+					WillReturnResult(sqlxmock.NewResult(0, 1))
+				s.ExpectExec(regexp.QuoteMeta("UPDATE `account` SET `statuses_count` = `statuses_count` + 1, `last_status_at` = NOW() WHERE `id` = ?")).
+					WithArgs(1).
+					WillReturnResult(sqlxmock.NewResult(0, 1))
+				s.ExpectExec(regexp.QuoteMeta("INSERT INTO `mention` (`id`, `status_id`, `account_id`) VALUES (?, ?, ?)")).
+					WithArgs(sqlxmock.AnyArg(), sqlxmock.AnyArg(), 3).
+					WillReturnError(errors.New("error"))
+				s.ExpectRollback()
+			},
+			args: args{
+				ctx:        context.Background(),
+				accountID:  1,
+				content:    "@jane",
+				visibility: object.VisibilityPublic,
+				extras: object.StatusExtras{
+					MentionedAccountIDs: []object.AccountID{3},
+					NotifiedAccountIDs:  []object.AccountID{3},
+				},
+			},
+			want:    0,
+			wantErr: true,
+		},
 		{
 			name: "error",
 			query: func(s sqlxmock.Sqlmock) {
-				s.ExpectPrepare(regexp.QuoteMeta("INSERT INTO `status` (`account_id`, `content`) VALUES (?, ?)")).
-					ExpectExec().
-					WithArgs(1, "content").
+				s.ExpectBegin()
+				s.ExpectExec(regexp.QuoteMeta("INSERT INTO `status` (`id`, `account_id`, `content`, `visibility`) VALUES (?, ?, ?, ?)")).
+					WithArgs(sqlxmock.AnyArg(), 1, "content", object.VisibilityPrivate).
 					WillReturnError(errors.New("error"))
+				s.ExpectRollback()
 			},
 			args: args{
-				ctx:       context.Background(),
-				accountID: 1,
-				content:   "content",
+				ctx:        context.Background(),
+				accountID:  1,
+				content:    "content",
+				visibility: object.VisibilityPrivate,
 			},
 			want:    0,
 			wantErr: true,
@@ -454,7 +518,7 @@ func Test_status_Insert(t *testing.T) {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
 			tt.query(mock)
-			got, err := r.Insert(tt.args.ctx, tt.args.accountID, tt.args.content)
+			got, err := r.Insert(tt.args.ctx, tt.args.accountID, tt.args.content, tt.args.visibility, tt.args.extras)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("status.Insert() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -479,7 +543,8 @@ func Test_status_Delete(t *testing.T) {
 	}()
 
 	r := &status{
-		db: db,
+		db:  db,
+		ids: &sequence{},
 	}
 
 	type args struct {
@@ -496,10 +561,20 @@ func Test_status_Delete(t *testing.T) {
 		{
 			name: "ok",
 			query: func(s sqlxmock.Sqlmock) {
-				s.ExpectPrepare(regexp.QuoteMeta("UPDATE `status` SET `delete_at` = NOW() WHERE `id` = ?")).
-					ExpectExec().
+				s.ExpectBegin()
+				s.ExpectExec(regexp.QuoteMeta("UPDATE `status` SET `delete_at` = NOW() WHERE `id` = ? AND `account_id` = ? AND `delete_at` IS NULL")).
 					WithArgs(1, 1).
 					WillReturnResult(sqlxmock.NewResult(1, 1))
+				s.ExpectExec(regexp.QuoteMeta("UPDATE `account` SET `statuses_count` = `statuses_count` - 1 WHERE `id` = ? AND `statuses_count` > 0")).
+					WithArgs(1).
+					WillReturnResult(sqlxmock.NewResult(0, 1))
+				s.ExpectExec(regexp.QuoteMeta("UPDATE `account` SET `last_status_at` = (SELECT MAX(`create_at`) FROM `status` WHERE `account_id` = ? AND `delete_at` IS NULL) WHERE `id` = ?")).
+					WithArgs(1, 1).
+					WillReturnResult(sqlxmock.NewResult(0, 1))
+				s.ExpectExec(regexp.QuoteMeta("DELETE FROM `pinned_status` WHERE `status_id` = ? AND `account_id` = ?")).
+					WithArgs(1, 1).
+					WillReturnResult(sqlxmock.NewResult(0, 1))
+				s.ExpectCommit()
 			},
 			args: args{
 				ctx:       context.Background(),
@@ -511,10 +586,11 @@ func Test_status_Delete(t *testing.T) {
 		{
 			name: "error",
 			query: func(s sqlxmock.Sqlmock) {
-				s.ExpectPrepare(regexp.QuoteMeta("UPDATE `status` SET `delete_at` = NOW() WHERE `id` = ?")).
-					ExpectExec().
+				s.ExpectBegin()
+				s.ExpectExec(regexp.QuoteMeta("UPDATE `status` SET `delete_at` = NOW() WHERE `id` = ? AND `account_id` = ? AND `delete_at` IS NULL")).
 					WithArgs(1, 1).
 					WillReturnError(errors.New("error"))
+				s.ExpectRollback()
 			},
 			args: args{
 				ctx:       context.Background(),
@@ -538,3 +614,394 @@ func Test_status_Delete(t *testing.T) {
 		})
 	}
 }
+
+func Test_status_SelectByTag(t *testing.T) {
+	createAt, _ := time.Parse("2006-01-02", "2020-01-01")
+	const (
+		query = "SELECT `s`.* FROM `status` AS `s` INNER JOIN `status_tag` AS `st` ON `st`.`status_id` = `s`.`id` INNER JOIN `tag` AS `t` ON `t`.`id` = `st`.`tag_id` " +
+			"WHERE `t`.`name` = ? AND `st`.`status_id` > ? AND `st`.`status_id` < ? AND `s`.`visibility` = ? AND `s`.`delete_at` IS NULL"
+		onlyMediaQuery = " AND EXISTS(SELECT 1 FROM `media_attachment` AS `m` WHERE `m`.`status_id` = `s`.`id` AND `m`.`delete_at` IS NULL)"
+		orderQuery     = " ORDER BY `st`.`status_id` DESC LIMIT ?"
+	)
+
+	db, mock, err := sqlxmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	r := &status{
+		db:  db,
+		ids: &sequence{},
+	}
+
+	type args struct {
+		ctx       context.Context
+		tag       string
+		onlyMedia bool
+		page      object.Page
+	}
+	tests := []struct {
+		name    string
+		query   func(s sqlxmock.Sqlmock)
+		args    args
+		want    []*object.Status
+		wantErr bool
+	}{
+		{
+			name: "ok",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectQuery(regexp.QuoteMeta(query+orderQuery)).
+					WithArgs("golang", 1, 100, object.VisibilityPublic, 2).
+					WillReturnRows(
+						sqlxmock.NewRows([]string{"id", "account_id", "content", "create_at", "delete_at"}).
+							AddRow(1, 1, "#golang", createAt, nil),
+					)
+			},
+			args: args{
+				ctx:  context.Background(),
+				tag:  "golang",
+				page: object.Page{MinID: 1, MaxID: 100, Limit: 2},
+			},
+			want: []*object.Status{
+				{
+					ID:        1,
+					AccountID: 1,
+					Text:      "#golang",
+					CreateAt:  object.DateTime{Time: createAt},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "only media",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectQuery(regexp.QuoteMeta(query+onlyMediaQuery+orderQuery)).
+					WithArgs("golang", 1, 100, object.VisibilityPublic, 2).
+					WillReturnRows(sqlxmock.NewRows([]string{"id", "account_id", "content", "create_at", "delete_at"}))
+			},
+			args: args{
+				ctx:       context.Background(),
+				tag:       "golang",
+				onlyMedia: true,
+				page:      object.Page{MinID: 1, MaxID: 100, Limit: 2},
+			},
+			want:    []*object.Status{},
+			wantErr: false,
+		},
+		{
+			name: "error",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectQuery(regexp.QuoteMeta(query+orderQuery)).
+					WithArgs("golang", 1, 100, object.VisibilityPublic, 2).
+					WillReturnError(errors.New("error"))
+			},
+			args: args{
+				ctx:  context.Background(),
+				tag:  "golang",
+				page: object.Page{MinID: 1, MaxID: 100, Limit: 2},
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tt.query(mock)
+			got, err := r.SelectByTag(tt.args.ctx, tt.args.tag, tt.args.onlyMedia, tt.args.page)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("status.SelectByTag() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Errorf("status.SelectByTag() returned diff (want -> got):\n%s", diff)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func Test_status_Update(t *testing.T) {
+	const (
+		insertQuery = "INSERT INTO `status_edit` (`id`, `status_id`, `content`, `media_attachment_ids`, `create_at`) " +
+			"SELECT ?, `id`, `content`, ?, COALESCE(`edit_at`, `create_at`) FROM `status` WHERE `id` = ? AND `account_id` = ? AND `delete_at` IS NULL"
+		updateQuery        = "UPDATE `status` SET `content` = ?, `edit_at` = NOW() WHERE `id` = ? AND `account_id` = ? AND `delete_at` IS NULL"
+		deleteMentionQuery = "DELETE FROM `mention` WHERE `status_id` = ?"
+		detachTagQuery     = "DELETE FROM `status_tag` WHERE `status_id` = ?"
+	)
+
+	db, mock, err := sqlxmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	r := &status{
+		db:  db,
+		ids: &sequence{},
+	}
+
+	type args struct {
+		ctx      context.Context
+		id       object.StatusID
+		account  object.AccountID
+		content  string
+		mediaIDs []object.MediaAttachmentID
+		keepIDs  []object.MediaAttachmentID
+		extras   object.StatusExtras
+	}
+	tests := []struct {
+		name    string
+		query   func(s sqlxmock.Sqlmock)
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "ok",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectBegin()
+				s.ExpectExec(regexp.QuoteMeta(insertQuery)).
+					WithArgs(sqlxmock.AnyArg(), "[1,2]", 1, 1).
+					WillReturnResult(sqlxmock.NewResult(1, 1))
+				s.ExpectExec(regexp.QuoteMeta(updateQuery)).
+					WithArgs("edited @jane", 1, 1).
+					WillReturnResult(sqlxmock.NewResult(0, 1))
+				s.ExpectExec(regexp.QuoteMeta("UPDATE `media_attachment` SET `delete_at` = NOW() WHERE `status_id` = ? AND `delete_at` IS NULL AND `id` NOT IN (?)")).
+					WithArgs(1, 2).
+					WillReturnResult(sqlxmock.NewResult(0, 1))
+				s.ExpectExec(regexp.QuoteMeta(deleteMentionQuery)).
+					WithArgs(1).
+					WillReturnResult(sqlxmock.NewResult(0, 0))
+				s.ExpectExec(regexp.QuoteMeta(detachTagQuery)).
+					WithArgs(1).
+					WillReturnResult(sqlxmock.NewResult(0, 0))
+				s.ExpectExec(regexp.QuoteMeta("INSERT INTO `mention` (`id`, `status_id`, `account_id`) VALUES (?, ?, ?)")).
+					WithArgs(sqlxmock.AnyArg(), 1, 3).
+					WillReturnResult(sqlxmock.NewResult(0, 1))
+				s.ExpectExec(regexp.QuoteMeta("INSERT IGNORE INTO `notification` (`id`, `account_id`, `type`, `from_account_id`, `status_id`) VALUES (?, ?, ?, ?, ?)")).
+					WithArgs(sqlxmock.AnyArg(), 3, object.NotificationMention, 1, 1).
+					WillReturnResult(sqlxmock.NewResult(0, 1))
+				s.ExpectCommit()
+			},
+			args: args{
+				ctx:      context.Background(),
+				id:       1,
+				account:  1,
+				content:  "edited @jane",
+				mediaIDs: []object.MediaAttachmentID{1, 2},
+				keepIDs:  []object.MediaAttachmentID{2},
+				extras: object.StatusExtras{
+					MentionedAccountIDs: []object.AccountID{3},
+					NotifiedAccountIDs:  []object.AccountID{3},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "without media",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectBegin()
+				s.ExpectExec(regexp.QuoteMeta(insertQuery)).
+					WithArgs(sqlxmock.AnyArg(), "[]", 1, 1).
+					WillReturnResult(sqlxmock.NewResult(1, 1))
+				s.ExpectExec(regexp.QuoteMeta(updateQuery)).
+					WithArgs("edited", 1, 1).
+					WillReturnResult(sqlxmock.NewResult(0, 1))
+				s.ExpectExec(regexp.QuoteMeta(deleteMentionQuery)).
+					WithArgs(1).
+					WillReturnResult(sqlxmock.NewResult(0, 0))
+				s.ExpectExec(regexp.QuoteMeta(detachTagQuery)).
+					WithArgs(1).
+					WillReturnResult(sqlxmock.NewResult(0, 0))
+				s.ExpectCommit()
+			},
+			args: args{
+				ctx:     context.Background(),
+				id:      1,
+				account: 1,
+				content: "edited",
+			},
+			wantErr: false,
+		},
+		{
+			/* 他人のステータスや削除済みのステータスでは子テーブルに触れない */
+			name: "not found",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectBegin()
+				s.ExpectExec(regexp.QuoteMeta(insertQuery)).
+					WithArgs(sqlxmock.AnyArg(), "[]", 1, 2).
+					WillReturnResult(sqlxmock.NewResult(0, 0))
+				s.ExpectRollback()
+			},
+			args: args{
+				ctx:     context.Background(),
+				id:      1,
+				account: 2,
+				content: "edited",
+			},
+			wantErr: true,
+		},
+		{
+			name: "error",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectBegin()
+				s.ExpectExec(regexp.QuoteMeta(insertQuery)).
+					WithArgs(sqlxmock.AnyArg(), "[]", 1, 1).
+					WillReturnResult(sqlxmock.NewResult(1, 1))
+				s.ExpectExec(regexp.QuoteMeta(updateQuery)).
+					WithArgs("edited", 1, 1).
+					WillReturnError(errors.New("error"))
+				s.ExpectRollback()
+			},
+			args: args{
+				ctx:     context.Background(),
+				id:      1,
+				account: 1,
+				content: "edited",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tt.query(mock)
+			if err := r.Update(tt.args.ctx, tt.args.id, tt.args.account, tt.args.content, tt.args.mediaIDs, tt.args.keepIDs, tt.args.extras); (err != nil) != tt.wantErr {
+				t.Errorf("status.Update() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func Test_status_SelectByAccountID(t *testing.T) {
+	createAt, _ := time.Parse("2006-01-02", "2020-01-01")
+	const (
+		query              = "SELECT * FROM `status` FORCE INDEX (`idx_account_id`) WHERE `account_id` = ? AND `id` > ? AND `id` < ? AND `visibility` IN (?, ?) AND `delete_at` IS NULL"
+		onlyMediaQuery     = " AND EXISTS(SELECT 1 FROM `media_attachment` AS `m` WHERE `m`.`status_id` = `status`.`id` AND `m`.`delete_at` IS NULL)"
+		excludePinnedQuery = " AND NOT EXISTS(SELECT 1 FROM `pinned_status` AS `p` WHERE `p`.`status_id` = `status`.`id`)"
+		orderQuery         = " ORDER BY `id` DESC LIMIT ?"
+	)
+	visibilities := []object.Visibility{object.VisibilityPublic, object.VisibilityUnlisted}
+
+	db, mock, err := sqlxmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	r := &status{
+		db:  db,
+		ids: &sequence{},
+	}
+
+	type args struct {
+		ctx           context.Context
+		accountID     object.AccountID
+		onlyMedia     bool
+		excludePinned bool
+		page          object.Page
+	}
+	tests := []struct {
+		name    string
+		query   func(s sqlxmock.Sqlmock)
+		args    args
+		want    []*object.Status
+		wantErr bool
+	}{
+		{
+			name: "ok",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectQuery(regexp.QuoteMeta(query+orderQuery)).
+					WithArgs(1, 0, 100, object.VisibilityPublic, object.VisibilityUnlisted, 2).
+					WillReturnRows(
+						sqlxmock.NewRows([]string{"id", "account_id", "content", "visibility", "create_at", "delete_at"}).
+							AddRow(2, 1, "second", object.VisibilityUnlisted, createAt, nil).
+							AddRow(1, 1, "first", object.VisibilityPublic, createAt, nil),
+					)
+			},
+			args: args{
+				ctx:       context.Background(),
+				accountID: 1,
+				page:      object.Page{MinID: 0, MaxID: 100, Limit: 2},
+			},
+			want: []*object.Status{
+				{
+					ID:         2,
+					AccountID:  1,
+					Text:       "second",
+					Visibility: object.VisibilityUnlisted,
+					CreateAt:   object.DateTime{Time: createAt},
+				},
+				{
+					ID:        1,
+					AccountID: 1,
+					Text:      "first",
+					CreateAt:  object.DateTime{Time: createAt},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "only media excluding pinned",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectQuery(regexp.QuoteMeta(query+onlyMediaQuery+excludePinnedQuery+orderQuery)).
+					WithArgs(1, 0, 100, object.VisibilityPublic, object.VisibilityUnlisted, 2).
+					WillReturnRows(sqlxmock.NewRows([]string{"id", "account_id", "content", "visibility", "create_at", "delete_at"}))
+			},
+			args: args{
+				ctx:           context.Background(),
+				accountID:     1,
+				onlyMedia:     true,
+				excludePinned: true,
+				page:          object.Page{MinID: 0, MaxID: 100, Limit: 2},
+			},
+			want:    []*object.Status{},
+			wantErr: false,
+		},
+		{
+			name: "error",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectQuery(regexp.QuoteMeta(query+orderQuery)).
+					WithArgs(1, 0, 100, object.VisibilityPublic, object.VisibilityUnlisted, 2).
+					WillReturnError(errors.New("error"))
+			},
+			args: args{
+				ctx:       context.Background(),
+				accountID: 1,
+				page:      object.Page{MinID: 0, MaxID: 100, Limit: 2},
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tt.query(mock)
+			got, err := r.SelectByAccountID(tt.args.ctx, tt.args.accountID, visibilities, tt.args.onlyMedia, tt.args.excludePinned, tt.args.page)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("status.SelectByAccountID() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Errorf("status.SelectByAccountID() returned diff (want -> got):\n%s", diff)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/satorunooshie/Yatter/app/domain/object"
+	"github.com/satorunooshie/Yatter/app/domain/repository"
+)
+
+type (
+	// Implementation for repository.Tag
+	tag struct {
+		db  *sqlx.DB
+		ids IDGenerator
+	}
+)
+
+func NewTag(db *sqlx.DB, ids IDGenerator) repository.Tag {
+	return &tag{db: db, ids: ids}
+}
+
+func (r *tag) FindByStatusIDs(ctx context.Context, statusIDs []object.StatusID) ([]*object.Tag, error) {
+	query, params, err := sqlx.In("SELECT `t`.*, `st`.`status_id` FROM `tag` AS `t` "+
+		"INNER JOIN `status_tag` AS `st` ON `st`.`tag_id` = `t`.`id` WHERE `st`.`status_id` IN (?) ORDER BY `t`.`id`", statusIDs)
+	if err != nil {
+		return nil, err
+	}
+	return r.selectx(ctx, query, params...)
+}
+
+func (r *tag) Attach(ctx context.Context, statusID object.StatusID, names []string) ([]*object.Tag, error) {
+	return attachTags(ctx, r.db, r.ids, statusID, names)
+}
+
+func (r *tag) Detach(ctx context.Context, statusID object.StatusID) error {
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM `status_tag` WHERE `status_id` = ?", statusID); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *tag) SelectUsages(ctx context.Context, since time.Time) ([]*object.TagUsage, error) {
+	const query = "SELECT `t`.`id` AS `tag_id`, `t`.`name`, `s`.`id` AS `status_id`, `s`.`account_id`, `s`.`create_at` FROM `status_tag` AS `st` " +
+		"INNER JOIN `tag` AS `t` ON `t`.`id` = `st`.`tag_id` " +
+		"INNER JOIN `status` AS `s` ON `s`.`id` = `st`.`status_id` " +
+		"INNER JOIN `account` AS `a` ON `a`.`id` = `s`.`account_id` " +
+		"WHERE `s`.`create_at` >= ? AND `s`.`visibility` = ? AND `s`.`delete_at` IS NULL AND `a`.`delete_at` IS NULL AND `a`.`suspend_at` IS NULL"
+	rows, err := r.db.QueryxContext(ctx, query, since, object.VisibilityPublic)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("[WARN] dao::tag::SelectUsages::rows.Close(): %v", err)
+		}
+	}()
+
+	entities := make([]*object.TagUsage, 0)
+	for rows.Next() {
+		entity := &object.TagUsage{}
+		if err := rows.StructScan(&entity); err != nil {
+			return nil, err
+		}
+		entities = append(entities, entity)
+	}
+	return entities, nil
+}
+
+func (r *tag) selectx(ctx context.Context, query string, args ...interface{}) ([]*object.Tag, error) {
+	return selectTags(ctx, r.db, query, args...)
+}
+
+// Attach tags with db or in a transaction
+func attachTags(ctx context.Context, ext sqlx.ExtContext, ids IDGenerator, statusID object.StatusID, names []string) ([]*object.Tag, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	placeholders := make([]string, 0, len(names))
+	params := make([]interface{}, 0, len(names)*2)
+	for _, name := range names {
+		placeholders = append(placeholders, "(?, ?)")
+		params = append(params, ids.Next(), name)
+	}
+	/* 既にあるタグは ID を変えずに使う */
+	if _, err := ext.ExecContext(ctx, "INSERT INTO `tag` (`id`, `name`) VALUES "+strings.Join(placeholders, ", ")+" ON DUPLICATE KEY UPDATE `id` = `id`", params...); err != nil {
+		return nil, err
+	}
+
+	query, params, err := sqlx.In("SELECT `t`.*, ? AS `status_id` FROM `tag` AS `t` WHERE `t`.`name` IN (?) ORDER BY `t`.`id`", statusID, names)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := selectTags(ctx, ext, query, params...)
+	if err != nil {
+		return nil, err
+	}
+
+	placeholders = placeholders[:0]
+	params = params[:0]
+	for _, t := range tags {
+		placeholders = append(placeholders, "(?, ?)")
+		params = append(params, statusID, t.ID)
+	}
+	if _, err := ext.ExecContext(ctx, "INSERT IGNORE INTO `status_tag` (`status_id`, `tag_id`) VALUES "+strings.Join(placeholders, ", "), params...); err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+func selectTags(ctx context.Context, q sqlx.QueryerContext, query string, args ...interface{}) ([]*object.Tag, error) {
+	rows, err := q.QueryxContext(ctx, query, args...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("[WARN] dao::tag::selectTags::rows.Close(): %v", err)
+		}
+	}()
+
+	entities := make([]*object.Tag, 0)
+	for rows.Next() {
+		entity := &object.Tag{}
+		if err := rows.StructScan(&entity); err != nil {
+			return nil, err
+		}
+		entity.SetURL()
+		entities = append(entities, entity)
+	}
+	return entities, nil
+}
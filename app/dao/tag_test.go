@@ -0,0 +1,320 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	sqlxmock "github.com/zhashkevych/go-sqlxmock"
+
+	"github.com/satorunooshie/Yatter/app/domain/object"
+)
+
+func Test_tag_FindByStatusIDs(t *testing.T) {
+	createAt, _ := time.Parse("2006-01-02", "2020-01-01")
+	const query = "SELECT `t`.*, `st`.`status_id` FROM `tag` AS `t` INNER JOIN `status_tag` AS `st` ON `st`.`tag_id` = `t`.`id` WHERE `st`.`status_id` IN (?, ?) ORDER BY `t`.`id`"
+
+	db, mock, err := sqlxmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	r := &tag{
+		db: db,
+	}
+
+	type args struct {
+		ctx       context.Context
+		statusIDs []object.StatusID
+	}
+	tests := []struct {
+		name    string
+		query   func(s sqlxmock.Sqlmock)
+		args    args
+		want    []*object.Tag
+		wantErr bool
+	}{
+		{
+			name: "ok",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs(1, 2).
+					WillReturnRows(
+						sqlxmock.NewRows(
+							[]string{
+								"id",
+								"name",
+								"create_at",
+								"status_id",
+							},
+						).
+							AddRow(1, "golang", createAt, 1).
+							AddRow(2, "ランチ", createAt, 2),
+					)
+			},
+			args: args{
+				ctx:       context.Background(),
+				statusIDs: []object.StatusID{1, 2},
+			},
+			want: []*object.Tag{
+				{
+					ID:       1,
+					Name:     "golang",
+					CreateAt: object.DateTime{Time: createAt},
+					StatusID: 1,
+					URL:      "/v1/timelines/tag/golang",
+				},
+				{
+					ID:       2,
+					Name:     "ランチ",
+					CreateAt: object.DateTime{Time: createAt},
+					StatusID: 2,
+					URL:      "/v1/timelines/tag/ランチ",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "error",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs(1, 2).
+					WillReturnError(errors.New("error"))
+			},
+			args: args{
+				ctx:       context.Background(),
+				statusIDs: []object.StatusID{1, 2},
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tt.query(mock)
+			got, err := r.FindByStatusIDs(tt.args.ctx, tt.args.statusIDs)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("tag.FindByStatusIDs() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Errorf("tag.FindByStatusIDs() returned diff (want -> got):\n%s", diff)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func Test_tag_Attach(t *testing.T) {
+	createAt, _ := time.Parse("2006-01-02", "2020-01-01")
+
+	db, mock, err := sqlxmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	r := &tag{
+		db:  db,
+		ids: &sequence{},
+	}
+
+	type args struct {
+		ctx      context.Context
+		statusID object.StatusID
+		names    []string
+	}
+	tests := []struct {
+		name    string
+		query   func(s sqlxmock.Sqlmock)
+		args    args
+		want    []*object.Tag
+		wantErr bool
+	}{
+		{
+			name: "ok",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectExec(regexp.QuoteMeta("INSERT INTO `tag` (`id`, `name`) VALUES (?, ?), (?, ?) ON DUPLICATE KEY UPDATE `id` = `id`")).
+					WithArgs(1, "golang", 2, "yatter").
+					WillReturnResult(sqlxmock.NewResult(2, 1))
+				s.ExpectQuery(regexp.QuoteMeta("SELECT `t`.*, ? AS `status_id` FROM `tag` AS `t` WHERE `t`.`name` IN (?, ?) ORDER BY `t`.`id`")).
+					WithArgs(10, "golang", "yatter").
+					WillReturnRows(
+						sqlxmock.NewRows([]string{"id", "name", "create_at", "status_id"}).
+							AddRow(1, "golang", createAt, 10).
+							AddRow(2, "yatter", createAt, 10),
+					)
+				s.ExpectExec(regexp.QuoteMeta("INSERT IGNORE INTO `status_tag` (`status_id`, `tag_id`) VALUES (?, ?), (?, ?)")).
+					WithArgs(10, 1, 10, 2).
+					WillReturnResult(sqlxmock.NewResult(0, 2))
+			},
+			args: args{
+				ctx:      context.Background(),
+				statusID: 10,
+				names:    []string{"golang", "yatter"},
+			},
+			want: []*object.Tag{
+				{
+					ID:       1,
+					Name:     "golang",
+					CreateAt: object.DateTime{Time: createAt},
+					StatusID: 10,
+					URL:      "/v1/timelines/tag/golang",
+				},
+				{
+					ID:       2,
+					Name:     "yatter",
+					CreateAt: object.DateTime{Time: createAt},
+					StatusID: 10,
+					URL:      "/v1/timelines/tag/yatter",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:  "empty",
+			query: func(s sqlxmock.Sqlmock) {},
+			args: args{
+				ctx:      context.Background(),
+				statusID: 10,
+				names:    nil,
+			},
+			want:    nil,
+			wantErr: false,
+		},
+		{
+			name: "error",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectExec(regexp.QuoteMeta("INSERT INTO `tag` (`id`, `name`) VALUES (?, ?) ON DUPLICATE KEY UPDATE `id` = `id`")).
+					WithArgs(3, "golang").
+					WillReturnError(errors.New("error"))
+			},
+			args: args{
+				ctx:      context.Background(),
+				statusID: 10,
+				names:    []string{"golang"},
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tt.query(mock)
+			got, err := r.Attach(tt.args.ctx, tt.args.statusID, tt.args.names)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("tag.Attach() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Errorf("tag.Attach() returned diff (want -> got):\n%s", diff)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func Test_tag_SelectUsages(t *testing.T) {
+	createAt, _ := time.Parse("2006-01-02", "2020-01-01")
+	since := createAt.Add(-24 * time.Hour)
+	const query = "SELECT `t`.`id` AS `tag_id`, `t`.`name`, `s`.`id` AS `status_id`, `s`.`account_id`, `s`.`create_at` FROM `status_tag` AS `st` " +
+		"INNER JOIN `tag` AS `t` ON `t`.`id` = `st`.`tag_id` " +
+		"INNER JOIN `status` AS `s` ON `s`.`id` = `st`.`status_id` " +
+		"INNER JOIN `account` AS `a` ON `a`.`id` = `s`.`account_id` " +
+		"WHERE `s`.`create_at` >= ? AND `s`.`visibility` = ? AND `s`.`delete_at` IS NULL AND `a`.`delete_at` IS NULL AND `a`.`suspend_at` IS NULL"
+
+	db, mock, err := sqlxmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	r := &tag{
+		db: db,
+	}
+
+	type args struct {
+		ctx   context.Context
+		since time.Time
+	}
+	tests := []struct {
+		name    string
+		query   func(s sqlxmock.Sqlmock)
+		args    args
+		want    []*object.TagUsage
+		wantErr bool
+	}{
+		{
+			name: "ok",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs(since, object.VisibilityPublic).
+					WillReturnRows(
+						sqlxmock.NewRows([]string{"tag_id", "name", "status_id", "account_id", "create_at"}).
+							AddRow(1, "golang", 10, 100, createAt),
+					)
+			},
+			args: args{
+				ctx:   context.Background(),
+				since: since,
+			},
+			want: []*object.TagUsage{
+				{
+					TagID:     1,
+					Name:      "golang",
+					StatusID:  10,
+					AccountID: 100,
+					CreateAt:  object.DateTime{Time: createAt},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "error",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs(since, object.VisibilityPublic).
+					WillReturnError(errors.New("error"))
+			},
+			args: args{
+				ctx:   context.Background(),
+				since: since,
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tt.query(mock)
+			got, err := r.SelectUsages(tt.args.ctx, tt.args.since)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("tag.SelectUsages() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Errorf("tag.SelectUsages() returned diff (want -> got):\n%s", diff)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
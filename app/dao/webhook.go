@@ -0,0 +1,107 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/satorunooshie/Yatter/app/domain/object"
+	"github.com/satorunooshie/Yatter/app/domain/repository"
+)
+
+type (
+	// Implementation for repository.Webhook
+	webhook struct {
+		db  *sqlx.DB
+		ids IDGenerator
+	}
+)
+
+func NewWebhook(db *sqlx.DB, ids IDGenerator) repository.Webhook {
+	return &webhook{db: db, ids: ids}
+}
+
+func (r *webhook) FindByID(ctx context.Context, id object.WebhookID) (*object.Webhook, error) {
+	entity := &object.Webhook{}
+	if err := r.db.QueryRowxContext(ctx, "SELECT * FROM `webhook` WHERE `id` = ?", id).StructScan(entity); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return entity, nil
+}
+
+func (r *webhook) Select(ctx context.Context) ([]*object.Webhook, error) {
+	return r.selectx(ctx, "SELECT * FROM `webhook` ORDER BY `id`")
+}
+
+func (r *webhook) SelectByEvent(ctx context.Context, event object.WebhookEvent) ([]*object.Webhook, error) {
+	return r.selectx(ctx, "SELECT * FROM `webhook` WHERE `enabled` = 1 AND JSON_CONTAINS(`events`, JSON_QUOTE(?)) ORDER BY `id`", event)
+}
+
+func (r *webhook) Insert(ctx context.Context, url, secret string, events object.WebhookEvents) (object.WebhookID, error) {
+	id := r.ids.Next()
+	if _, err := r.db.ExecContext(ctx, "INSERT INTO `webhook` (`id`, `url`, `secret`, `events`) VALUES (?, ?, ?, ?)", id, url, secret, events); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r *webhook) Update(ctx context.Context, id object.WebhookID, url string, events object.WebhookEvents, enabled bool) error {
+	if _, err := r.db.ExecContext(ctx, "UPDATE `webhook` SET `url` = ?, `events` = ?, `enabled` = ?, `failure_count` = IF(?, 0, `failure_count`) WHERE `id` = ?",
+		url, events, enabled, enabled, id); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *webhook) Delete(ctx context.Context, id object.WebhookID) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			log.Printf("[WARN] dao::webhook::Delete::tx.Rollback(): %v", err)
+		}
+	}()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM `webhook_delivery` WHERE `webhook_id` = ?", id); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM `webhook` WHERE `id` = ?", id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *webhook) selectx(ctx context.Context, query string, args ...interface{}) ([]*object.Webhook, error) {
+	rows, err := r.db.QueryxContext(ctx, query, args...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("[WARN] dao::webhook::selectx::rows.Close(): %v", err)
+		}
+	}()
+
+	entities := make([]*object.Webhook, 0)
+	for rows.Next() {
+		entity := &object.Webhook{}
+		if err := rows.StructScan(&entity); err != nil {
+			return nil, err
+		}
+		entities = append(entities, entity)
+	}
+	return entities, nil
+}
@@ -0,0 +1,151 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/satorunooshie/Yatter/app/domain/object"
+	"github.com/satorunooshie/Yatter/app/domain/repository"
+)
+
+type (
+	// Implementation for repository.WebhookDelivery
+	webhookDelivery struct {
+		db  *sqlx.DB
+		ids IDGenerator
+	}
+)
+
+func NewWebhookDelivery(db *sqlx.DB, ids IDGenerator) repository.WebhookDelivery {
+	return &webhookDelivery{db: db, ids: ids}
+}
+
+func (r *webhookDelivery) Select(ctx context.Context, webhookID object.WebhookID, page object.Page) ([]*object.WebhookDelivery, error) {
+	entities, err := r.selectx(ctx, "SELECT * FROM `webhook_delivery` WHERE `webhook_id` = ? AND `id` > ? AND `id` < ? ORDER BY `id` "+order(page)+" LIMIT ?", webhookID, page.MinID, page.MaxID, page.Limit)
+	if err != nil {
+		return nil, err
+	}
+	newestFirst(page, entities)
+	return entities, nil
+}
+
+func (r *webhookDelivery) Insert(ctx context.Context, event object.WebhookEvent, payload []byte, webhookIDs []object.WebhookID) error {
+	if len(webhookIDs) == 0 {
+		return nil
+	}
+
+	placeholders := make([]string, 0, len(webhookIDs))
+	params := make([]interface{}, 0, len(webhookIDs)*4)
+	for _, webhookID := range webhookIDs {
+		placeholders = append(placeholders, "(?, ?, ?, ?)")
+		params = append(params, r.ids.Next(), webhookID, event, string(payload))
+	}
+
+	if _, err := r.db.ExecContext(ctx, "INSERT INTO `webhook_delivery` (`id`, `webhook_id`, `event`, `payload`) VALUES "+strings.Join(placeholders, ", "), params...); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *webhookDelivery) SelectDue(ctx context.Context, until time.Time, limit int64) ([]*object.WebhookDelivery, error) {
+	return r.selectx(ctx, "SELECT `d`.* FROM `webhook_delivery` AS `d` INNER JOIN `webhook` AS `w` ON `w`.`id` = `d`.`webhook_id` "+
+		"WHERE `d`.`status` = ? AND `d`.`next_attempt_at` <= ? AND `w`.`enabled` = 1 ORDER BY `d`.`next_attempt_at`, `d`.`id` LIMIT ?",
+		object.WebhookDeliveryPending, until, limit)
+}
+
+func (r *webhookDelivery) Claim(ctx context.Context, id object.WebhookDeliveryID, attempts int64, leaseUntil time.Time) (bool, error) {
+	/* 試行回数をバージョンとして扱い、先に更新したインスタンスだけが配信する */
+	res, err := r.db.ExecContext(ctx, "UPDATE `webhook_delivery` SET `attempts` = `attempts` + 1, `next_attempt_at` = ? WHERE `id` = ? AND `status` = ? AND `attempts` = ?",
+		leaseUntil, id, object.WebhookDeliveryPending, attempts)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+func (r *webhookDelivery) Succeed(ctx context.Context, id object.WebhookDeliveryID, webhookID object.WebhookID, responseStatus int) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			log.Printf("[WARN] dao::webhookDelivery::Succeed::tx.Rollback(): %v", err)
+		}
+	}()
+
+	if _, err := tx.ExecContext(ctx, "UPDATE `webhook_delivery` SET `status` = ?, `response_status` = ?, `error` = NULL, `delivered_at` = NOW() WHERE `id` = ?",
+		object.WebhookDeliverySucceeded, responseStatus, id); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "UPDATE `webhook` SET `failure_count` = 0 WHERE `id` = ?", webhookID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *webhookDelivery) Fail(ctx context.Context, id object.WebhookDeliveryID, webhookID object.WebhookID, responseStatus *int, reason string, nextAttemptAt *time.Time, disableAfter int64) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			log.Printf("[WARN] dao::webhookDelivery::Fail::tx.Rollback(): %v", err)
+		}
+	}()
+
+	status := object.WebhookDeliveryPending
+	if nextAttemptAt == nil {
+		status = object.WebhookDeliveryFailed
+	}
+	if _, err := tx.ExecContext(ctx, "UPDATE `webhook_delivery` SET `status` = ?, `next_attempt_at` = COALESCE(?, `next_attempt_at`), `response_status` = ?, `error` = ? WHERE `id` = ?",
+		status, nextAttemptAt, responseStatus, reason, id); err != nil {
+		return err
+	}
+
+	/* 代入は左から評価されるので enabled は加算後の failure_count で判定される */
+	if _, err := tx.ExecContext(ctx, "UPDATE `webhook` SET `failure_count` = `failure_count` + 1, `enabled` = `enabled` AND `failure_count` < ? WHERE `id` = ?",
+		disableAfter, webhookID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *webhookDelivery) selectx(ctx context.Context, query string, args ...interface{}) ([]*object.WebhookDelivery, error) {
+	rows, err := r.db.QueryxContext(ctx, query, args...)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("[WARN] dao::webhookDelivery::selectx::rows.Close(): %v", err)
+		}
+	}()
+
+	entities := make([]*object.WebhookDelivery, 0)
+	for rows.Next() {
+		entity := &object.WebhookDelivery{}
+		if err := rows.StructScan(&entity); err != nil {
+			return nil, err
+		}
+		entities = append(entities, entity)
+	}
+	return entities, nil
+}
@@ -0,0 +1,230 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	sqlxmock "github.com/zhashkevych/go-sqlxmock"
+
+	"github.com/satorunooshie/Yatter/app/domain/object"
+)
+
+func Test_webhookDelivery_Claim(t *testing.T) {
+	leaseUntil, _ := time.Parse("2006-01-02", "2020-01-01")
+	const query = "UPDATE `webhook_delivery` SET `attempts` = `attempts` + 1, `next_attempt_at` = ? WHERE `id` = ? AND `status` = ? AND `attempts` = ?"
+
+	db, mock, err := sqlxmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	r := &webhookDelivery{
+		db:  db,
+		ids: &sequence{},
+	}
+
+	type args struct {
+		ctx        context.Context
+		id         object.WebhookDeliveryID
+		attempts   int64
+		leaseUntil time.Time
+	}
+	tests := []struct {
+		name    string
+		query   func(s sqlxmock.Sqlmock)
+		args    args
+		want    bool
+		wantErr bool
+	}{
+		{
+			name: "claimed",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectExec(regexp.QuoteMeta(query)).
+					WithArgs(leaseUntil, 1, object.WebhookDeliveryPending, 2).
+					WillReturnResult(sqlxmock.NewResult(0, 1))
+			},
+			args: args{
+				ctx:        context.Background(),
+				id:         1,
+				attempts:   2,
+				leaseUntil: leaseUntil,
+			},
+			want:    true,
+			wantErr: false,
+		},
+		{
+			name: "claimed by another",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectExec(regexp.QuoteMeta(query)).
+					WithArgs(leaseUntil, 1, object.WebhookDeliveryPending, 2).
+					WillReturnResult(sqlxmock.NewResult(0, 0))
+			},
+			args: args{
+				ctx:        context.Background(),
+				id:         1,
+				attempts:   2,
+				leaseUntil: leaseUntil,
+			},
+			want:    false,
+			wantErr: false,
+		},
+		{
+			name: "error",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectExec(regexp.QuoteMeta(query)).
+					WithArgs(leaseUntil, 1, object.WebhookDeliveryPending, 2).
+					WillReturnError(errors.New("error"))
+			},
+			args: args{
+				ctx:        context.Background(),
+				id:         1,
+				attempts:   2,
+				leaseUntil: leaseUntil,
+			},
+			want:    false,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tt.query(mock)
+			got, err := r.Claim(tt.args.ctx, tt.args.id, tt.args.attempts, tt.args.leaseUntil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("webhookDelivery.Claim() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("webhookDelivery.Claim() = %v, want %v", got, tt.want)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func Test_webhookDelivery_Fail(t *testing.T) {
+	nextAttemptAt, _ := time.Parse("2006-01-02", "2020-01-01")
+	responseStatus := 500
+	const (
+		deliveryQuery = "UPDATE `webhook_delivery` SET `status` = ?, `next_attempt_at` = COALESCE(?, `next_attempt_at`), `response_status` = ?, `error` = ? WHERE `id` = ?"
+		webhookQuery  = "UPDATE `webhook` SET `failure_count` = `failure_count` + 1, `enabled` = `enabled` AND `failure_count` < ? WHERE `id` = ?"
+	)
+
+	db, mock, err := sqlxmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	r := &webhookDelivery{
+		db:  db,
+		ids: &sequence{},
+	}
+
+	type args struct {
+		ctx            context.Context
+		id             object.WebhookDeliveryID
+		webhookID      object.WebhookID
+		responseStatus *int
+		reason         string
+		nextAttemptAt  *time.Time
+		disableAfter   int64
+	}
+	tests := []struct {
+		name    string
+		query   func(s sqlxmock.Sqlmock)
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "retry",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectBegin()
+				s.ExpectExec(regexp.QuoteMeta(deliveryQuery)).
+					WithArgs(object.WebhookDeliveryPending, nextAttemptAt, responseStatus, "500 Internal Server Error", 1).
+					WillReturnResult(sqlxmock.NewResult(0, 1))
+				s.ExpectExec(regexp.QuoteMeta(webhookQuery)).
+					WithArgs(10, 2).
+					WillReturnResult(sqlxmock.NewResult(0, 1))
+				s.ExpectCommit()
+			},
+			args: args{
+				ctx:            context.Background(),
+				id:             1,
+				webhookID:      2,
+				responseStatus: &responseStatus,
+				reason:         "500 Internal Server Error",
+				nextAttemptAt:  &nextAttemptAt,
+				disableAfter:   10,
+			},
+			wantErr: false,
+		},
+		{
+			name: "give up",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectBegin()
+				s.ExpectExec(regexp.QuoteMeta(deliveryQuery)).
+					WithArgs(object.WebhookDeliveryFailed, nil, nil, "connection refused", 1).
+					WillReturnResult(sqlxmock.NewResult(0, 1))
+				s.ExpectExec(regexp.QuoteMeta(webhookQuery)).
+					WithArgs(10, 2).
+					WillReturnResult(sqlxmock.NewResult(0, 1))
+				s.ExpectCommit()
+			},
+			args: args{
+				ctx:            context.Background(),
+				id:             1,
+				webhookID:      2,
+				responseStatus: nil,
+				reason:         "connection refused",
+				nextAttemptAt:  nil,
+				disableAfter:   10,
+			},
+			wantErr: false,
+		},
+		{
+			name: "error",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectBegin()
+				s.ExpectExec(regexp.QuoteMeta(deliveryQuery)).
+					WithArgs(object.WebhookDeliveryFailed, nil, nil, "connection refused", 1).
+					WillReturnError(errors.New("error"))
+				s.ExpectRollback()
+			},
+			args: args{
+				ctx:            context.Background(),
+				id:             1,
+				webhookID:      2,
+				responseStatus: nil,
+				reason:         "connection refused",
+				nextAttemptAt:  nil,
+				disableAfter:   10,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tt.query(mock)
+			err := r.Fail(tt.args.ctx, tt.args.id, tt.args.webhookID, tt.args.responseStatus, tt.args.reason, tt.args.nextAttemptAt, tt.args.disableAfter)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("webhookDelivery.Fail() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	sqlxmock "github.com/zhashkevych/go-sqlxmock"
+
+	"github.com/satorunooshie/Yatter/app/domain/object"
+)
+
+func Test_webhook_SelectByEvent(t *testing.T) {
+	createAt, _ := time.Parse("2006-01-02", "2020-01-01")
+	const query = "SELECT * FROM `webhook` WHERE `enabled` = 1 AND JSON_CONTAINS(`events`, JSON_QUOTE(?)) ORDER BY `id`"
+
+	db, mock, err := sqlxmock.Newx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	r := &webhook{
+		db:  db,
+		ids: &sequence{},
+	}
+
+	type args struct {
+		ctx   context.Context
+		event object.WebhookEvent
+	}
+	tests := []struct {
+		name    string
+		query   func(s sqlxmock.Sqlmock)
+		args    args
+		want    []*object.Webhook
+		wantErr bool
+	}{
+		{
+			name: "ok",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs(object.WebhookStatusCreated).
+					WillReturnRows(
+						sqlxmock.NewRows(
+							[]string{
+								"id",
+								"url",
+								"secret",
+								"events",
+								"enabled",
+								"failure_count",
+								"create_at",
+							},
+						).
+							AddRow(1, "http://example.com/hook", "secret", `["account.created","status.created"]`, true, 0, createAt),
+					)
+			},
+			args: args{
+				ctx:   context.Background(),
+				event: object.WebhookStatusCreated,
+			},
+			want: []*object.Webhook{
+				{
+					ID:       1,
+					URL:      "http://example.com/hook",
+					Secret:   "secret",
+					Events:   object.WebhookEvents{object.WebhookAccountCreated, object.WebhookStatusCreated},
+					Enabled:  true,
+					CreateAt: object.DateTime{Time: createAt},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "error",
+			query: func(s sqlxmock.Sqlmock) {
+				s.ExpectQuery(regexp.QuoteMeta(query)).
+					WithArgs(object.WebhookStatusCreated).
+					WillReturnError(errors.New("error"))
+			},
+			args: args{
+				ctx:   context.Background(),
+				event: object.WebhookStatusCreated,
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tt.query(mock)
+			got, err := r.SelectByEvent(tt.args.ctx, tt.args.event)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("webhook.SelectByEvent() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Errorf("webhook.SelectByEvent() returned diff (want -> got):\n%s", diff)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
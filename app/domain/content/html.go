@@ -0,0 +1,139 @@
+package content
+
+import (
+	"html"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/satorunooshie/Yatter/app/domain/object"
+)
+
+var (
+	// Only http(s) URLs are linked so that `javascript:` and the like never become links
+	urlRegexp = regexp.MustCompile(`(?i)\bhttps?://[^\s<>"'` + "`" + `]+`)
+
+	paragraphRegexp = regexp.MustCompile(`\n{2,}`)
+)
+
+type link struct {
+	start, end int
+	html       string
+}
+
+// Render the status text as HTML.
+//
+// Everything but the generated links is escaped. URLs are linked, and mentions and hashtags
+// are linked to the account and the tag timeline if they are in s.Mentions and s.Tags.
+func Render(s *object.Status) string {
+	text := strings.ReplaceAll(strings.ReplaceAll(s.Text, "\r\n", "\n"), "\r", "\n")
+	text = strings.Trim(text, "\n")
+	if text == "" {
+		return ""
+	}
+
+	/* メンションはユーザー名の大文字小文字を区別せずに解決される */
+	mentionURLs := make(map[string]string, len(s.Mentions))
+	for _, m := range s.Mentions {
+		mentionURLs[strings.ToLower(m.Username)] = m.URL
+	}
+	tagURLs := make(map[string]string, len(s.Tags))
+	for _, t := range s.Tags {
+		tagURLs[t.Name] = t.URL
+	}
+
+	links := urlLinks(text)
+	links = append(links, entityLinks(text, links, mentionRegexp, func(name string) string {
+		u, ok := mentionURLs[strings.ToLower(name)]
+		if !ok {
+			return ""
+		}
+		return `<span class="h-card"><a href="` + html.EscapeString(u) + `" class="u-url mention">@<span>` + html.EscapeString(name) + `</span></a></span>`
+	})...)
+	links = append(links, entityLinks(text, links, tagRegexp, func(name string) string {
+		u, ok := tagURLs[NormalizeTag(name)]
+		if !ok {
+			return ""
+		}
+		return `<a href="` + html.EscapeString(u) + `" class="mention hashtag" rel="tag">#<span>` + html.EscapeString(name) + `</span></a>`
+	})...)
+	sort.Slice(links, func(i, j int) bool { return links[i].start < links[j].start })
+
+	var b strings.Builder
+	pos := 0
+	for _, l := range links {
+		b.WriteString(escapeText(text[pos:l.start]))
+		b.WriteString(l.html)
+		pos = l.end
+	}
+	b.WriteString(escapeText(text[pos:]))
+	return "<p>" + b.String() + "</p>"
+}
+
+// Escape text, turning blank lines into paragraphs and newlines into line breaks
+func escapeText(s string) string {
+	escaped := html.EscapeString(s)
+	escaped = paragraphRegexp.ReplaceAllString(escaped, "</p><p>")
+	return strings.ReplaceAll(escaped, "\n", "<br />")
+}
+
+func urlLinks(text string) []link {
+	var links []link
+	for _, loc := range urlRegexp.FindAllStringIndex(text, -1) {
+		raw := trimURL(text[loc[0]:loc[1]])
+		u, err := url.Parse(raw)
+		if err != nil || u.Host == "" {
+			continue
+		}
+		links = append(links, link{
+			start: loc[0],
+			end:   loc[0] + len(raw),
+			html:  `<a href="` + html.EscapeString(u.String()) + `" rel="nofollow noopener noreferrer" target="_blank">` + html.EscapeString(raw) + `</a>`,
+		})
+	}
+	return links
+}
+
+// Drop trailing punctuation which is more likely a part of the sentence, keeping balanced parentheses
+func trimURL(raw string) string {
+	for len(raw) > 0 {
+		last := raw[len(raw)-1]
+		switch {
+		case strings.IndexByte(".,!?;:", last) >= 0:
+			raw = raw[:len(raw)-1]
+		case last == ')' && strings.Count(raw, "(") < strings.Count(raw, ")"):
+			raw = raw[:len(raw)-1]
+		default:
+			return raw
+		}
+	}
+	return raw
+}
+
+// Find `@username` or `#tag` not overlapping with URLs, linking them with render.
+// The first submatch of re must be the name following the prefix character.
+func entityLinks(text string, urls []link, re *regexp.Regexp, render func(name string) string) []link {
+	var links []link
+	for _, loc := range re.FindAllStringSubmatchIndex(text, -1) {
+		start, end := loc[2]-1, loc[3]
+		if overlaps(urls, start, end) {
+			continue
+		}
+		h := render(text[loc[2]:loc[3]])
+		if h == "" {
+			continue
+		}
+		links = append(links, link{start: start, end: end, html: h})
+	}
+	return links
+}
+
+func overlaps(links []link, start, end int) bool {
+	for _, l := range links {
+		if start < l.end && l.start < end {
+			return true
+		}
+	}
+	return false
+}
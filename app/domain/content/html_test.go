@@ -0,0 +1,223 @@
+package content
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/satorunooshie/Yatter/app/domain/object"
+)
+
+func TestRender(t *testing.T) {
+	john := &object.Mention{Username: "john", URL: "/v1/accounts/john"}
+	golang := &object.Tag{Name: "golang", URL: "/v1/timelines/tag/golang"}
+
+	tests := []struct {
+		name   string
+		status *object.Status
+		want   string
+	}{
+		{
+			name:   "empty",
+			status: &object.Status{Text: ""},
+			want:   "",
+		},
+		{
+			name:   "plain",
+			status: &object.Status{Text: "ピタ ゴラ スイッチ♪"},
+			want:   "<p>ピタ ゴラ スイッチ♪</p>",
+		},
+		{
+			name:   "line breaks and paragraphs",
+			status: &object.Status{Text: "a\nb\r\nc\n\n\nd\n"},
+			want:   "<p>a<br />b<br />c</p><p>d</p>",
+		},
+		{
+			name:   "url",
+			status: &object.Status{Text: "see https://example.com/a?b=1&c=2."},
+			want:   `<p>see <a href="https://example.com/a?b=1&amp;c=2" rel="nofollow noopener noreferrer" target="_blank">https://example.com/a?b=1&amp;c=2</a>.</p>`,
+		},
+		{
+			name:   "url in parentheses",
+			status: &object.Status{Text: "(https://en.wikipedia.org/wiki/Go_(programming_language))"},
+			want:   `<p>(<a href="https://en.wikipedia.org/wiki/Go_(programming_language)" rel="nofollow noopener noreferrer" target="_blank">https://en.wikipedia.org/wiki/Go_(programming_language)</a>)</p>`,
+		},
+		{
+			name:   "mention",
+			status: &object.Status{Text: "hi @john", Mentions: []*object.Mention{john}},
+			want:   `<p>hi <span class="h-card"><a href="/v1/accounts/john" class="u-url mention">@<span>john</span></a></span></p>`,
+		},
+		{
+			name:   "mention in other case",
+			status: &object.Status{Text: "hi @John", Mentions: []*object.Mention{john}},
+			want:   `<p>hi <span class="h-card"><a href="/v1/accounts/john" class="u-url mention">@<span>John</span></a></span></p>`,
+		},
+		{
+			name:   "unknown mention",
+			status: &object.Status{Text: "hi @alice"},
+			want:   `<p>hi @alice</p>`,
+		},
+		{
+			name:   "hashtag",
+			status: &object.Status{Text: "I love #GoLang", Tags: []*object.Tag{golang}},
+			want:   `<p>I love <a href="/v1/timelines/tag/golang" class="mention hashtag" rel="tag">#<span>GoLang</span></a></p>`,
+		},
+		{
+			name:   "mention and hashtag in url",
+			status: &object.Status{Text: "https://example.com/@john#golang", Mentions: []*object.Mention{john}, Tags: []*object.Tag{golang}},
+			want:   `<p><a href="https://example.com/@john#golang" rel="nofollow noopener noreferrer" target="_blank">https://example.com/@john#golang</a></p>`,
+		},
+		{
+			name:   "everything",
+			status: &object.Status{Text: "@john #golang https://go.dev", Mentions: []*object.Mention{john}, Tags: []*object.Tag{golang}},
+			want: `<p><span class="h-card"><a href="/v1/accounts/john" class="u-url mention">@<span>john</span></a></span> ` +
+				`<a href="/v1/timelines/tag/golang" class="mention hashtag" rel="tag">#<span>golang</span></a> ` +
+				`<a href="https://go.dev" rel="nofollow noopener noreferrer" target="_blank">https://go.dev</a></p>`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := cmp.Diff(Render(tt.status), tt.want); diff != "" {
+				t.Errorf("Render() returned diff (want -> got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRender_XSS(t *testing.T) {
+	mentions := []*object.Mention{{Username: "john", URL: "/v1/accounts/john"}}
+	tags := []*object.Tag{{Name: "tag", URL: "/v1/timelines/tag/tag"}}
+
+	corpus := []string{
+		`<script>alert(1)</script>`,
+		`<SCRIPT SRC=http://xss.example/xss.js></SCRIPT>`,
+		`<img src=x onerror=alert(1)>`,
+		`<svg/onload=alert(1)>`,
+		`<a href="javascript:alert(1)">click</a>`,
+		`javascript:alert(1)`,
+		`JaVaScRiPt:alert(1)`,
+		`data:text/html;base64,PHNjcmlwdD5hbGVydCgxKTwvc2NyaXB0Pg==`,
+		`vbscript:msgbox(1)`,
+		`<iframe src="https://evil.example"></iframe>`,
+		`"><script>alert(1)</script>`,
+		`'><img src=x onerror=alert(1)>`,
+		`<p style="background:url(javascript:alert(1))">`,
+		`&lt;script&gt;alert(1)&lt;/script&gt;`,
+		`&#60;script&#62;alert(1)&#60;/script&#62;`,
+		`https://example.com/"onmouseover="alert(1)`,
+		`https://example.com/'onmouseover='alert(1)`,
+		`https://example.com/<script>alert(1)</script>`,
+		"https://example.com/`onmouseover=alert(1)",
+		`https://"onmouseover=alert(1)//`,
+		`http://<script>alert(1)</script>`,
+		`@john"><script>alert(1)</script>`,
+		`@john<img src=x onerror=alert(1)>`,
+		`#tag"><script>alert(1)</script>`,
+		`#tag<svg/onload=alert(1)>`,
+		`<<script>script>alert(1)<</script>/script>`,
+		`<scr<script>ipt>alert(1)</script>`,
+		`<!--<script>alert(1)</script>-->`,
+		`<![CDATA[<script>alert(1)</script>]]>`,
+		`<math><mi xlink:href="javascript:alert(1)">x</mi></math>`,
+		`<body onload=alert(1)>`,
+		`<input autofocus onfocus=alert(1)>`,
+		`<details open ontoggle=alert(1)>`,
+		`<object data="javascript:alert(1)">`,
+		`<embed src="javascript:alert(1)">`,
+		`<form action="javascript:alert(1)"><button>x</button></form>`,
+		`<meta http-equiv="refresh" content="0;url=javascript:alert(1)">`,
+		`<link rel=stylesheet href=javascript:alert(1)>`,
+		`<style>@import 'javascript:alert(1)';</style>`,
+		"<scr\x00ipt>alert(1)</scr\x00ipt>",
+		`＜script＞alert(1)＜/script＞`,
+	}
+
+	for _, text := range corpus {
+		text := text
+		t.Run(text, func(t *testing.T) {
+			got := Render(&object.Status{Text: text, Mentions: mentions, Tags: tags})
+			assertSafeHTML(t, got)
+		})
+	}
+}
+
+// Check that every tag in s is one Render generates, and that attribute values can't be broken out of
+func assertSafeHTML(t *testing.T, s string) {
+	t.Helper()
+
+	allowedTags := map[string]bool{
+		"p": true, "/p": true, "br": true,
+		"a": true, "/a": true, "span": true, "/span": true,
+	}
+	allowedAttrs := map[string]bool{
+		"href": true, "rel": true, "target": true, "class": true,
+	}
+
+	rest := s
+	for {
+		i := strings.IndexByte(rest, '<')
+		if i < 0 {
+			break
+		}
+		j := strings.IndexByte(rest[i:], '>')
+		if j < 0 {
+			t.Fatalf("unterminated tag in %q", s)
+		}
+		tag := rest[i+1 : i+j]
+		rest = rest[i+j+1:]
+
+		tag = strings.TrimSuffix(strings.TrimSpace(tag), "/")
+		fields := splitAttrs(t, s, tag)
+		if len(fields) == 0 || !allowedTags[fields[0]] {
+			t.Fatalf("unexpected tag <%s> in %q", tag, s)
+		}
+		for _, attr := range fields[1:] {
+			kv := strings.SplitN(attr, "=", 2)
+			if !allowedAttrs[kv[0]] {
+				t.Fatalf("unexpected attribute %q in %q", attr, s)
+			}
+			if len(kv) != 2 || len(kv[1]) < 2 || kv[1][0] != '"' || kv[1][len(kv[1])-1] != '"' {
+				t.Fatalf("attribute %q is not quoted in %q", attr, s)
+			}
+			value := kv[1][1 : len(kv[1])-1]
+			if strings.ContainsAny(value, `"<>`) {
+				t.Fatalf("attribute %q is not escaped in %q", attr, s)
+			}
+			if kv[0] == "href" && !strings.HasPrefix(value, "/") && !strings.HasPrefix(value, "http://") && !strings.HasPrefix(value, "https://") {
+				t.Fatalf("unsafe href %q in %q", value, s)
+			}
+		}
+	}
+}
+
+// Split tag content into name and attributes, respecting quoted values
+func splitAttrs(t *testing.T, s, tag string) []string {
+	t.Helper()
+
+	var fields []string
+	var cur strings.Builder
+	quoted := false
+	for _, c := range tag {
+		switch {
+		case c == '"':
+			quoted = !quoted
+			cur.WriteRune(c)
+		case c == ' ' && !quoted:
+			if cur.Len() > 0 {
+				fields = append(fields, cur.String())
+				cur.Reset()
+			}
+		default:
+			cur.WriteRune(c)
+		}
+	}
+	if quoted {
+		t.Fatalf("unbalanced quotes in <%s> in %q", tag, s)
+	}
+	if cur.Len() > 0 {
+		fields = append(fields, cur.String())
+	}
+	return fields
+}
@@ -0,0 +1,25 @@
+package content
+
+import (
+	"regexp"
+)
+
+// `@username` preceded by the beginning of the text or a character which can't be a part of username
+var mentionRegexp = regexp.MustCompile(`(?:^|[^\p{L}\p{N}_@/])@([\p{L}\p{N}_]+)`)
+
+// Extract usernames mentioned in the status content.
+// Each username appears once, in order of first appearance.
+func Mentions(text string) []string {
+	matches := mentionRegexp.FindAllStringSubmatch(text, -1)
+	usernames := make([]string, 0, len(matches))
+	seen := make(map[string]struct{}, len(matches))
+	for _, m := range matches {
+		username := m[1]
+		if _, ok := seen[username]; ok {
+			continue
+		}
+		seen[username] = struct{}{}
+		usernames = append(usernames, username)
+	}
+	return usernames
+}
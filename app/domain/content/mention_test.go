@@ -0,0 +1,64 @@
+package content
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMentions(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{
+			name: "none",
+			text: "hello world",
+			want: []string{},
+		},
+		{
+			name: "single",
+			text: "@john hello",
+			want: []string{"john"},
+		},
+		{
+			name: "multiple",
+			text: "hi @john and @alice_1!",
+			want: []string{"john", "alice_1"},
+		},
+		{
+			name: "duplicated",
+			text: "@john @alice @john",
+			want: []string{"john", "alice"},
+		},
+		{
+			name: "unicode",
+			text: "こんにちは @名前 さん",
+			want: []string{"名前"},
+		},
+		{
+			name: "email address",
+			text: "contact john@example.com",
+			want: []string{},
+		},
+		{
+			name: "url path",
+			text: "see https://example.com/@john",
+			want: []string{},
+		},
+		{
+			name: "punctuation",
+			text: "(@john), @alice.",
+			want: []string{"john", "alice"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := cmp.Diff(Mentions(tt.text), tt.want); diff != "" {
+				t.Errorf("Mentions() returned diff (want -> got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package content
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+var (
+	breakRegexp        = regexp.MustCompile(`(?i)<br\s*/?>`)
+	paragraphEndRegexp = regexp.MustCompile(`(?i)</p>\s*<p(\s[^>]*)?>`)
+	htmlTagRegexp      = regexp.MustCompile(`<[^>]*>`)
+)
+
+// Convert HTML content of remote statuses back into plain text as Render takes.
+//
+// Paragraphs become blank lines, line breaks become newlines and the other tags are dropped.
+func PlainText(s string) string {
+	s = paragraphEndRegexp.ReplaceAllString(s, "\n\n")
+	s = breakRegexp.ReplaceAllString(s, "\n")
+	s = htmlTagRegexp.ReplaceAllString(s, "")
+	return strings.TrimSpace(html.UnescapeString(s))
+}
@@ -0,0 +1,52 @@
+package content
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/satorunooshie/Yatter/app/domain/object"
+)
+
+func TestPlainText(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{
+			name: "empty",
+			html: "",
+			want: "",
+		},
+		{
+			name: "paragraphs and line breaks",
+			html: "<p>a<br>b<br />c</p><p>d</p>",
+			want: "a\nb\nc\n\nd",
+		},
+		{
+			name: "links",
+			html: `<p>hi <span class="h-card"><a href="https://example.com/@john" class="u-url mention">@<span>john</span></a></span> <a href="https://example.com/" rel="nofollow">https://example.com/</a></p>`,
+			want: "hi @john https://example.com/",
+		},
+		{
+			name: "entities",
+			html: "<p>&lt;script&gt; &amp; &quot;quoted&quot; &#39;</p>",
+			want: `<script> & "quoted" '`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := cmp.Diff(PlainText(tt.html), tt.want); diff != "" {
+				t.Errorf("PlainText() returned diff (want -> got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPlainText_Render(t *testing.T) {
+	text := "line 1\nline 2\n\nsee https://example.com/a?b=1&c=2 <3"
+	if got := PlainText(Render(&object.Status{Text: text})); got != text {
+		t.Errorf("PlainText(Render(%q)) = %q", text, got)
+	}
+}
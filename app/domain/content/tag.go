@@ -0,0 +1,56 @@
+package content
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	tagChars = `\p{L}\p{M}\p{N}_`
+
+	// Longest tag in characters, which tag.name can hold
+	MaxTagLength = 255
+)
+
+var (
+	// `#tag` preceded by the beginning of the text or a character which can't be a part of tag.
+	// Tags consisting only of digits such as `#1` are not tags.
+	tagRegexp = regexp.MustCompile(`(?:^|[^` + tagChars + `/&#])#([` + tagChars + `]*[\p{L}\p{M}_][` + tagChars + `]*)`)
+
+	validTagRegexp = regexp.MustCompile(`^[` + tagChars + `]*[\p{L}\p{M}_][` + tagChars + `]*$`)
+)
+
+// Extract hashtags in the status content.
+// Each tag is normalized and appears once, in order of first appearance.
+// Tags longer than MaxTagLength are left as text.
+func Tags(text string) []string {
+	matches := tagRegexp.FindAllStringSubmatch(text, -1)
+	names := make([]string, 0, len(matches))
+	seen := make(map[string]struct{}, len(matches))
+	for _, m := range matches {
+		name := NormalizeTag(m[1])
+		if utf8.RuneCountInString(name) > MaxTagLength {
+			continue
+		}
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+		names = append(names, name)
+	}
+	return names
+}
+
+// Normalize tag name so that `#Go` and `#go`, or `#ｇｏ` written in full-width, are the same tag.
+// Accents are kept, so `#café` and `#cafe` are different tags.
+func NormalizeTag(name string) string {
+	return strings.ToLower(norm.NFKC.String(strings.TrimPrefix(name, "#")))
+}
+
+// Check if name can be used as a tag
+func IsValidTag(name string) bool {
+	return utf8.RuneCountInString(name) <= MaxTagLength && validTagRegexp.MatchString(name)
+}
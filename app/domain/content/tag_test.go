@@ -0,0 +1,99 @@
+package content
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTags(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{
+			name: "none",
+			text: "hello world",
+			want: []string{},
+		},
+		{
+			name: "single",
+			text: "#golang is fun",
+			want: []string{"golang"},
+		},
+		{
+			name: "case normalized",
+			text: "#Go #GO #go",
+			want: []string{"go"},
+		},
+		{
+			name: "unicode",
+			text: "今日は #ランチ と #Café",
+			want: []string{"ランチ", "café"},
+		},
+		{
+			/* 合成済みの é と e + 結合アクセントは同じタグ */
+			name: "unicode normalized",
+			text: "#ｇｏ #Cafe\u0301 #café #cafe",
+			want: []string{"go", "café", "cafe"},
+		},
+		{
+			name: "too long",
+			text: "#" + strings.Repeat("あ", MaxTagLength+1) + " #" + strings.Repeat("あ", MaxTagLength),
+			want: []string{strings.Repeat("あ", MaxTagLength)},
+		},
+		{
+			name: "digits only",
+			text: "issue #1 and #2020year",
+			want: []string{"2020year"},
+		},
+		{
+			name: "url fragment",
+			text: "see https://example.com/page#section",
+			want: []string{},
+		},
+		{
+			name: "html entity",
+			text: "&#39; quoted",
+			want: []string{},
+		},
+		{
+			name: "punctuation",
+			text: "(#yatter), #bootcamp.",
+			want: []string{"yatter", "bootcamp"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := cmp.Diff(Tags(tt.text), tt.want); diff != "" {
+				t.Errorf("Tags() returned diff (want -> got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestIsValidTag(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{name: "golang", want: true},
+		{name: "ランチ", want: true},
+		{name: "2020year", want: true},
+		{name: "2020", want: false},
+		{name: "go-lang", want: false},
+		{name: "", want: false},
+		{name: strings.Repeat("a", MaxTagLength+1), want: false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsValidTag(tt.name); got != tt.want {
+				t.Errorf("IsValidTag(%q) = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
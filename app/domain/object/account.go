@@ -1,12 +1,19 @@
 package object
 
 import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
 
 	"github.com/pkg/errors"
 	"golang.org/x/crypto/bcrypt"
 )
 
+// Size of the RSA keys of accounts, which Mastodon also uses
+const keyBits = 2048
+
 type (
 	AccountID    = int64
 	PasswordHash = string
@@ -24,9 +31,34 @@ type (
 		Note     *string   `json:"note,omitempty"`
 		CreateAt DateTime  `json:"create_at,omitempty" db:"create_at"`
 		DeleteAt *DateTime `json:"-" db:"delete_at"`
+		// Suspended accounts are hidden from discovery such as trends
+		SuspendAt *DateTime `json:"-" db:"suspend_at"`
+
+		// Counters maintained in the same transaction as the rows they count
+		StatusesCount  int64     `json:"statuses_count" db:"statuses_count"`
+		FollowersCount int64     `json:"followers_count" db:"followers_count"`
+		FollowingCount int64     `json:"following_count" db:"following_count"`
+		LastStatusAt   *DateTime `json:"last_status_at" db:"last_status_at"`
+
+		// Host of the server the account lives on, empty for local accounts
+		Domain string `json:"domain,omitempty"`
+		// ActivityPub actor ID and inboxes, nil for local accounts
+		URI            *string `json:"-"`
+		InboxURL       *string `json:"-" db:"inbox_url"`
+		SharedInboxURL *string `json:"-" db:"shared_inbox_url"`
+		// PEM of the RSA key pair signing ActivityPub requests, only public key for remote accounts
+		PrivateKey *string `json:"-" db:"private_key"`
+		PublicKey  *string `json:"-" db:"public_key"`
+		// ID of the public key of remote accounts, which signatures must name as keyId
+		PublicKeyID *string `json:"-" db:"public_key_id"`
 	}
 )
 
+// Check if the account lives on this server
+func (a *Account) IsLocal() bool {
+	return a.Domain == ""
+}
+
 // Check if given password is match to account's password
 func (a *Account) CheckPassword(pass string) bool {
 	return bcrypt.CompareHashAndPassword([]byte(a.PasswordHash), []byte(pass)) == nil
@@ -42,6 +74,23 @@ func (a *Account) SetPassword(pass string) error {
 	return nil
 }
 
+// Generate the RSA key pair signing ActivityPub requests of the account
+func (a *Account) GenerateKeys() error {
+	key, err := rsa.GenerateKey(rand.Reader, keyBits)
+	if err != nil {
+		return fmt.Errorf("generating key failed: %w", errors.WithStack(err))
+	}
+	public, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		return fmt.Errorf("encoding public key failed: %w", errors.WithStack(err))
+	}
+	privatePEM := string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}))
+	publicPEM := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: public}))
+	a.PrivateKey = &privatePEM
+	a.PublicKey = &publicPEM
+	return nil
+}
+
 func generatePasswordHash(pass string) (PasswordHash, error) {
 	hash, err := bcrypt.GenerateFromPassword([]byte(pass), bcrypt.DefaultCost)
 	if err != nil {
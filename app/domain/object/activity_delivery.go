@@ -0,0 +1,26 @@
+package object
+
+import (
+	"encoding/json"
+)
+
+type (
+	ActivityDeliveryID = int64
+
+	// ActivityPub activity queued for a remote inbox
+	ActivityDelivery struct {
+		ID ActivityDeliveryID `json:"id,string"`
+		// Local account signing the delivery
+		AccountID AccountID       `json:"account_id,string" db:"account_id"`
+		InboxURL  string          `json:"inbox_url" db:"inbox_url"`
+		Activity  json.RawMessage `json:"activity" db:"activity"`
+		// Goes through the same states as webhook deliveries
+		Status        WebhookDeliveryStatus `json:"status" db:"status"`
+		Attempts      int64                 `json:"attempts" db:"attempts"`
+		NextAttemptAt DateTime              `json:"next_attempt_at" db:"next_attempt_at"`
+		// Why the last attempt failed
+		Error       *string   `json:"error" db:"error"`
+		CreateAt    DateTime  `json:"create_at" db:"create_at"`
+		DeliveredAt *DateTime `json:"delivered_at" db:"delivered_at"`
+	}
+)
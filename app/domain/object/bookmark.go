@@ -0,0 +1,13 @@
+package object
+
+type (
+	BookmarkID = int64
+
+	// Status saved privately by an account
+	Bookmark struct {
+		ID        BookmarkID `json:"-"`
+		AccountID AccountID  `json:"-" db:"account_id"`
+		StatusID  StatusID   `json:"-" db:"status_id"`
+		CreateAt  DateTime   `json:"-" db:"create_at"`
+	}
+)
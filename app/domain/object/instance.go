@@ -0,0 +1,13 @@
+package object
+
+type (
+	// Usage of the instance by local accounts
+	InstanceUsage struct {
+		Users int64 `db:"users"`
+		// Accounts which have posted in the last month and half year
+		ActiveMonth    int64 `db:"active_month"`
+		ActiveHalfyear int64 `db:"active_halfyear"`
+		// Statuses of local accounts, counted by their counters
+		LocalPosts int64 `db:"local_posts"`
+	}
+)
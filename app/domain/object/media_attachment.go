@@ -9,7 +9,7 @@ type (
 
 	MediaAttachment struct {
 		ID          MediaAttachmentID `json:"-"`
-		StatusID    StatusID          `json:"id" db:"status_id"`
+		StatusID    StatusID          `json:"id,string" db:"status_id"`
 		Type        int64             `json:"-" db:"type"`
 		URL         string            `json:"url,omitempty" db:"url"`
 		Description string            `json:"description,omitempty"`
@@ -0,0 +1,20 @@
+package object
+
+type (
+	MentionID = int64
+
+	Mention struct {
+		ID        MentionID `json:"-"`
+		StatusID  StatusID  `json:"-" db:"status_id"`
+		AccountID AccountID `json:"id,string" db:"account_id"`
+		Username  string    `json:"username" db:"username"`
+		CreateAt  DateTime  `json:"-" db:"create_at"`
+
+		// URL to the mentioned account
+		URL string `json:"url" db:"-"`
+	}
+)
+
+func (m *Mention) SetURL() {
+	m.URL = "/v1/accounts/" + m.Username
+}
@@ -0,0 +1,72 @@
+package object
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	// Someone mentioned the account in a status
+	NotificationMention NotificationType = iota
+	// Someone followed the account
+	NotificationFollow
+	// Someone favourited a status of the account
+	NotificationFavourite
+	// Someone reblogged a status of the account
+	NotificationReblog
+)
+
+var notificationTypeNames = map[NotificationType]string{
+	NotificationMention:   "mention",
+	NotificationFollow:    "follow",
+	NotificationFavourite: "favourite",
+	NotificationReblog:    "reblog",
+}
+
+type (
+	NotificationID = int64
+
+	// What happened to the notified account
+	NotificationType int64
+
+	Notification struct {
+		ID            NotificationID   `json:"id,string"`
+		AccountID     AccountID        `json:"-" db:"account_id"`
+		Type          NotificationType `json:"type" db:"type"`
+		FromAccountID AccountID        `json:"-" db:"from_account_id"`
+		// 0 for notifications not about a status such as follow
+		StatusID StatusID `json:"-" db:"status_id"`
+		CreateAt DateTime `json:"create_at" db:"create_at"`
+
+		// Account which performed the action
+		Account *Account `json:"account,omitempty" db:"-"`
+		Status  *Status  `json:"status,omitempty" db:"-"`
+	}
+)
+
+// All notification types
+func NotificationTypes() []NotificationType {
+	return []NotificationType{NotificationMention, NotificationFollow, NotificationFavourite, NotificationReblog}
+}
+
+// Parse notification type name such as "mention" or "follow"
+func ParseNotificationType(s string) (NotificationType, error) {
+	for t, name := range notificationTypeNames {
+		if name == s {
+			return t, nil
+		}
+	}
+	return NotificationMention, fmt.Errorf("unknown notification type: %q", s)
+}
+
+func (t NotificationType) String() string {
+	if name, ok := notificationTypeNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("NotificationType(%d)", int64(t))
+}
+
+// encoding/json/Marshaler
+func (t NotificationType) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.String())
+}
@@ -0,0 +1,25 @@
+package object
+
+import "math"
+
+// Window of a list ordered by ID, excluding both bounds
+type Page struct {
+	// Only items with IDs greater than MinID, 0 for no lower bound
+	MinID int64
+	// Only items with IDs less than MaxID, math.MaxInt64 for no upper bound
+	MaxID int64
+	Limit int64
+	// Take the items right after MinID instead of the newest ones.
+	// Items are returned newest first either way.
+	Ascending bool
+}
+
+// Page of the newest items
+func NewestPage(limit int64) Page {
+	return Page{MinID: 0, MaxID: math.MaxInt64, Limit: limit}
+}
+
+// Whether the page is the newest one without any bound
+func (p Page) IsFirst() bool {
+	return p.MinID == 0 && p.MaxID == math.MaxInt64 && !p.Ascending
+}
@@ -0,0 +1,4 @@
+package object
+
+// Maximum number of statuses an account can pin
+const MaxPinnedStatuses = 5
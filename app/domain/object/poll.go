@@ -0,0 +1,81 @@
+package object
+
+import (
+	"time"
+)
+
+type (
+	PollID       = int64
+	PollOptionID = int64
+
+	Poll struct {
+		ID         PollID    `json:"id,string"`
+		StatusID   StatusID  `json:"-" db:"status_id"`
+		ExpireAt   DateTime  `json:"expires_at" db:"expire_at"`
+		Multiple   bool      `json:"multiple"`
+		HideTotals bool      `json:"-" db:"hide_totals"`
+		CreateAt   DateTime  `json:"-" db:"create_at"`
+		AccountID  AccountID `json:"-" db:"account_id"`
+
+		// Counts are nil while hidden from the viewer
+		VotesCount  *int64 `json:"votes_count" db:"votes_count"`
+		VotersCount *int64 `json:"voters_count" db:"voters_count"`
+
+		Expired  bool          `json:"expired" db:"-"`
+		Options  []*PollOption `json:"options" db:"-"`
+		Voted    *bool         `json:"voted,omitempty" db:"-"`
+		OwnVotes []int         `json:"own_votes,omitempty" db:"-"`
+	}
+
+	PollOption struct {
+		ID       PollOptionID `json:"-"`
+		PollID   PollID       `json:"-" db:"poll_id"`
+		Position int          `json:"-"`
+		Title    string       `json:"title"`
+
+		// nil while hidden from the viewer
+		VotesCount *int64 `json:"votes_count" db:"votes_count"`
+	}
+
+	// Parameters to create a poll with
+	PollParams struct {
+		Options []string `json:"options"`
+		// Duration the poll should be open, in seconds
+		ExpiresIn int64 `json:"expires_in"`
+		Multiple  bool  `json:"multiple"`
+		// Hide vote counts until the poll ends or the viewer votes
+		HideTotals bool `json:"hide_totals"`
+	}
+
+	// Vote for an option of a poll
+	PollVote struct {
+		PollID    PollID    `db:"poll_id"`
+		AccountID AccountID `db:"account_id"`
+		Choice    int       `db:"choice"`
+	}
+)
+
+// Check if the poll can't be voted anymore
+func (p *Poll) IsExpired(now time.Time) bool {
+	return !now.Before(p.ExpireAt.Time)
+}
+
+// Set viewer dependent fields, hiding the counts if the poll is configured to hide them until the viewer votes.
+// viewer is nil for anonymous requests and ownVotes are the choices viewer made.
+func (p *Poll) Prepare(viewer *Account, ownVotes []int, now time.Time) {
+	p.Expired = p.IsExpired(now)
+
+	voted := len(ownVotes) != 0
+	if viewer != nil {
+		p.Voted = &voted
+		p.OwnVotes = ownVotes
+	}
+
+	if p.HideTotals && !p.Expired && !voted && (viewer == nil || viewer.ID != p.AccountID) {
+		p.VotesCount = nil
+		p.VotersCount = nil
+		for _, o := range p.Options {
+			o.VotesCount = nil
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package object
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+type (
+	ScheduledStatusID = int64
+
+	// Status waiting to be published at ScheduledAt
+	ScheduledStatus struct {
+		ID          ScheduledStatusID `json:"id,string"`
+		AccountID   AccountID         `json:"-" db:"account_id"`
+		ScheduledAt DateTime          `json:"scheduled_at" db:"scheduled_at"`
+		Params      StatusParams      `json:"params" db:"params"`
+		CreateAt    DateTime          `json:"-" db:"create_at"`
+	}
+
+	// Parameters to create a status with
+	StatusParams struct {
+		Text       string      `json:"text"`
+		Visibility Visibility  `json:"visibility"`
+		Poll       *PollParams `json:"poll,omitempty"`
+	}
+)
+
+// database/sql/driver/Valuer
+func (p StatusParams) Value() (driver.Value, error) {
+	b, err := json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// database/sql/Scanner
+func (p *StatusParams) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case []byte:
+		return json.Unmarshal(v, p)
+	case string:
+		return json.Unmarshal([]byte(v), p)
+	default:
+		return fmt.Errorf("unsupported type for StatusParams: %T", value)
+	}
+}
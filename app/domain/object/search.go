@@ -0,0 +1,47 @@
+package object
+
+import (
+	"strings"
+)
+
+type (
+	// Parsed search query such as `golang from:john has:media`
+	SearchQuery struct {
+		// Words all of which the results must contain
+		Terms []string
+		// Author given by `from:username` or `from:username@domain`, empty if not given
+		FromUsername string
+		// Host of the author, empty for local accounts
+		FromDomain string
+		// Given by `has:media`
+		HasMedia bool
+	}
+)
+
+// Parse search query, where operators are case insensitive and other words are terms
+func ParseSearchQuery(q string) SearchQuery {
+	query := SearchQuery{Terms: make([]string, 0)}
+	for _, field := range strings.Fields(q) {
+		lower := strings.ToLower(field)
+		switch {
+		case strings.HasPrefix(lower, "from:") && len(field) > len("from:"):
+			/* @john@example.com の形式も受け付ける */
+			from := strings.TrimPrefix(field[len("from:"):], "@")
+			if i := strings.Index(from, "@"); i >= 0 {
+				query.FromUsername, query.FromDomain = from[:i], from[i+1:]
+			} else {
+				query.FromUsername, query.FromDomain = from, ""
+			}
+		case lower == "has:media":
+			query.HasMedia = true
+		default:
+			query.Terms = append(query.Terms, field)
+		}
+	}
+	return query
+}
+
+// Check if the query has neither terms nor operators
+func (q SearchQuery) IsEmpty() bool {
+	return len(q.Terms) == 0 && q.FromUsername == "" && !q.HasMedia
+}
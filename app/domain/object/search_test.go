@@ -0,0 +1,32 @@
+package object
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseSearchQuery(t *testing.T) {
+	tests := []struct {
+		q    string
+		want SearchQuery
+	}{
+		{
+			q:    "  寿司   おいしい ",
+			want: SearchQuery{Terms: []string{"寿司", "おいしい"}},
+		},
+		{
+			q:    "From:@john HAS:media 寿司",
+			want: SearchQuery{Terms: []string{"寿司"}, FromUsername: "john", HasMedia: true},
+		},
+		{
+			q:    "from:jane@remote.example from: has:poll",
+			want: SearchQuery{Terms: []string{"from:", "has:poll"}, FromUsername: "jane", FromDomain: "remote.example"},
+		},
+	}
+	for _, tt := range tests {
+		if diff := cmp.Diff(ParseSearchQuery(tt.q), tt.want); diff != "" {
+			t.Errorf("ParseSearchQuery(%q) returned diff (want -> got):\n%s", tt.q, diff)
+		}
+	}
+}
@@ -1,16 +1,132 @@
 package object
 
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const (
+	VisibilityPublic Visibility = iota
+	VisibilityUnlisted
+	// followers-only
+	VisibilityPrivate
+	VisibilityDirect
+)
+
+var visibilityNames = map[Visibility]string{
+	VisibilityPublic:   "public",
+	VisibilityUnlisted: "unlisted",
+	VisibilityPrivate:  "private",
+	VisibilityDirect:   "direct",
+}
+
 type (
 	StatusID = int64
 
+	// Who can see a status
+	Visibility int64
+
 	Status struct {
-		ID        StatusID  `json:"id"`
-		AccountID AccountID `json:"-" db:"account_id"`
-		Content   string    `json:"content"`
-		CreateAt  DateTime  `json:"create_at,omitempty" db:"create_at"`
-		DeleteAt  *DateTime `json:"-" db:"delete_at"`
+		ID         StatusID   `json:"id,string"`
+		AccountID  AccountID  `json:"-" db:"account_id"`
+		Text       string     `json:"text" db:"content"` // plain text as posted
+		Content    string     `json:"content" db:"-"`    // HTML rendered from Text
+		Visibility Visibility `json:"visibility" db:"visibility"`
+		CreateAt   DateTime   `json:"create_at,omitempty" db:"create_at"`
+		EditedAt   *DateTime  `json:"edited_at" db:"edit_at"`
+		DeleteAt   *DateTime  `json:"-" db:"delete_at"`
+		// ActivityPub object ID, nil for local statuses
+		URI *string `json:"uri,omitempty"`
 
 		Account         *Account           `json:"account,omitempty"`
 		MediaAttachment []*MediaAttachment `json:"media_attachments,omitempty"`
+		Mentions        []*Mention         `json:"mentions,omitempty"`
+		Tags            []*Tag             `json:"tags,omitempty"`
+		Poll            *Poll              `json:"poll,omitempty"`
+
+		// Whether the viewer has bookmarked the status, nil for anonymous requests
+		Bookmarked *bool `json:"bookmarked,omitempty" db:"-"`
+		// Whether the author has pinned the status on the profile
+		Pinned bool `json:"pinned,omitempty" db:"-"`
+	}
+
+	// Entities derived from the text of a status, stored in the same transaction as the status
+	StatusExtras struct {
+		// Accounts mentioned in the text, each only once
+		MentionedAccountIDs []AccountID
+		// Mentioned accounts to notify, leaving out the author
+		NotifiedAccountIDs []AccountID
+		// Names of the tags in the text
+		Tags []string
+		// Poll of a new status, nil for none
+		Poll *PollParams
+		// When the poll ends
+		PollExpireAt time.Time
 	}
 )
+
+// Parse visibility name such as "public" or "private"
+func ParseVisibility(s string) (Visibility, error) {
+	for v, name := range visibilityNames {
+		if name == s {
+			return v, nil
+		}
+	}
+	return VisibilityPublic, fmt.Errorf("unknown visibility: %q", s)
+}
+
+func (v Visibility) String() string {
+	if name, ok := visibilityNames[v]; ok {
+		return name
+	}
+	return fmt.Sprintf("Visibility(%d)", int64(v))
+}
+
+// encoding/json/Marshaler
+func (v Visibility) MarshalJSON() ([]byte, error) {
+	return json.Marshal(v.String())
+}
+
+// encoding/json/Unmarshaler
+func (v *Visibility) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	parsed, err := ParseVisibility(s)
+	if err != nil {
+		return err
+	}
+	*v = parsed
+	return nil
+}
+
+// Check if the account is mentioned in the status
+func (s *Status) IsMentioned(accountID AccountID) bool {
+	for _, m := range s.Mentions {
+		if m.AccountID == accountID {
+			return true
+		}
+	}
+	return false
+}
+
+// Check if the status can be read by viewer.
+// viewer is nil for anonymous requests, following reports whether viewer follows the author
+// and mentioned reports whether viewer is mentioned in the status.
+func (s *Status) IsVisibleTo(viewer *Account, following, mentioned bool) bool {
+	if viewer != nil && viewer.ID == s.AccountID {
+		return true
+	}
+	switch s.Visibility {
+	case VisibilityPublic, VisibilityUnlisted:
+		return true
+	case VisibilityPrivate:
+		return viewer != nil && (following || mentioned)
+	case VisibilityDirect:
+		return viewer != nil && mentioned
+	default:
+		return false
+	}
+}
@@ -0,0 +1,57 @@
+package object
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+type (
+	StatusEditID = int64
+
+	// Previous version of an edited status
+	StatusEdit struct {
+		ID                 StatusEditID       `json:"-"`
+		StatusID           StatusID           `json:"-" db:"status_id"`
+		Text               string             `json:"text" db:"content"` // plain text as posted
+		Content            string             `json:"content" db:"-"`    // HTML rendered from Text
+		MediaAttachmentIDs MediaAttachmentIDs `json:"-" db:"media_attachment_ids"`
+		// The time this version was posted
+		CreateAt DateTime `json:"create_at,omitempty" db:"create_at"`
+
+		Account         *Account           `json:"account,omitempty"`
+		MediaAttachment []*MediaAttachment `json:"media_attachments,omitempty"`
+	}
+
+	// List of media attachment IDs stored as JSON
+	MediaAttachmentIDs []MediaAttachmentID
+)
+
+// database/sql/driver/Valuer
+func (ids MediaAttachmentIDs) Value() (driver.Value, error) {
+	if ids == nil {
+		ids = MediaAttachmentIDs{}
+	}
+	b, err := json.Marshal([]MediaAttachmentID(ids))
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// database/sql/Scanner
+func (ids *MediaAttachmentIDs) Scan(value interface{}) error {
+	var b []byte
+	switch v := value.(type) {
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	case nil:
+		*ids = nil
+		return nil
+	default:
+		return fmt.Errorf("unsupported type for MediaAttachmentIDs: %T", value)
+	}
+	return json.Unmarshal(b, (*[]MediaAttachmentID)(ids))
+}
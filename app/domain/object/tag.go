@@ -0,0 +1,21 @@
+package object
+
+type (
+	TagID = int64
+
+	Tag struct {
+		ID       TagID    `json:"-"`
+		Name     string   `json:"name"`
+		CreateAt DateTime `json:"-" db:"create_at"`
+
+		// Status which the tag is attached to, set when fetched by status
+		StatusID StatusID `json:"-" db:"status_id"`
+
+		// URL to the tag timeline
+		URL string `json:"url" db:"-"`
+	}
+)
+
+func (t *Tag) SetURL() {
+	t.URL = "/v1/timelines/tag/" + t.Name
+}
@@ -0,0 +1,24 @@
+package object
+
+type (
+	// A use of a tag in a status, used to compute trends
+	TagUsage struct {
+		TagID     TagID     `db:"tag_id"`
+		Name      string    `db:"name"`
+		StatusID  StatusID  `db:"status_id"`
+		AccountID AccountID `db:"account_id"`
+		CreateAt  DateTime  `db:"create_at"`
+	}
+
+	TrendTag struct {
+		Name string `json:"name"`
+		// URL to the tag timeline
+		URL string `json:"url"`
+		// Time-decayed score used for ranking
+		Score float64 `json:"score"`
+		// Number of statuses using the tag within the window
+		Uses int64 `json:"uses"`
+		// Number of accounts using the tag within the window
+		Accounts int64 `json:"accounts"`
+	}
+)
@@ -0,0 +1,131 @@
+package object
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	// New account, carrying the Account
+	WebhookAccountCreated WebhookEvent = "account.created"
+	// New public status, carrying the Status
+	WebhookStatusCreated WebhookEvent = "status.created"
+)
+
+const (
+	WebhookDeliveryPending WebhookDeliveryStatus = iota
+	WebhookDeliverySucceeded
+	// Given up after running out of retries
+	WebhookDeliveryFailed
+)
+
+var webhookDeliveryStatusNames = map[WebhookDeliveryStatus]string{
+	WebhookDeliveryPending:   "pending",
+	WebhookDeliverySucceeded: "succeeded",
+	WebhookDeliveryFailed:    "failed",
+}
+
+type (
+	WebhookID         = int64
+	WebhookDeliveryID = int64
+
+	// Kind of event webhooks can subscribe to
+	WebhookEvent string
+
+	// Events a webhook subscribes to, stored as JSON
+	WebhookEvents []WebhookEvent
+
+	WebhookDeliveryStatus int64
+
+	// Subscription of a URL to events
+	Webhook struct {
+		ID     WebhookID     `json:"id,string"`
+		URL    string        `json:"url" db:"url"`
+		Secret string        `json:"-" db:"secret"`
+		Events WebhookEvents `json:"events" db:"events"`
+		// Disabled webhooks are not delivered to
+		Enabled bool `json:"enabled" db:"enabled"`
+		// Failed attempts in a row, which disable the webhook once they reach the limit
+		FailureCount int64    `json:"failure_count" db:"failure_count"`
+		CreateAt     DateTime `json:"create_at" db:"create_at"`
+	}
+
+	// Event queued for a webhook, kept as the delivery log
+	WebhookDelivery struct {
+		ID            WebhookDeliveryID     `json:"id,string"`
+		WebhookID     WebhookID             `json:"-" db:"webhook_id"`
+		Event         WebhookEvent          `json:"event" db:"event"`
+		Payload       json.RawMessage       `json:"payload" db:"payload"`
+		Status        WebhookDeliveryStatus `json:"status" db:"status"`
+		Attempts      int64                 `json:"attempts" db:"attempts"`
+		NextAttemptAt DateTime              `json:"next_attempt_at" db:"next_attempt_at"`
+		// HTTP status of the last attempt, nil if no response was received
+		ResponseStatus *int64 `json:"response_status" db:"response_status"`
+		// Why the last attempt failed
+		Error       *string   `json:"error" db:"error"`
+		CreateAt    DateTime  `json:"create_at" db:"create_at"`
+		DeliveredAt *DateTime `json:"delivered_at" db:"delivered_at"`
+	}
+)
+
+// All events webhooks can subscribe to
+func WebhookEventList() []WebhookEvent {
+	return []WebhookEvent{WebhookAccountCreated, WebhookStatusCreated}
+}
+
+// Check if the event is one webhooks can subscribe to
+func (e WebhookEvent) IsValid() bool {
+	for _, v := range WebhookEventList() {
+		if v == e {
+			return true
+		}
+	}
+	return false
+}
+
+// Check if the events contain e
+func (es WebhookEvents) Contains(e WebhookEvent) bool {
+	for _, v := range es {
+		if v == e {
+			return true
+		}
+	}
+	return false
+}
+
+// database/sql/driver/Valuer
+func (es WebhookEvents) Value() (driver.Value, error) {
+	if es == nil {
+		es = WebhookEvents{}
+	}
+	b, err := json.Marshal([]WebhookEvent(es))
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// database/sql/Scanner
+func (es *WebhookEvents) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case []byte:
+		return json.Unmarshal(v, (*[]WebhookEvent)(es))
+	case string:
+		return json.Unmarshal([]byte(v), (*[]WebhookEvent)(es))
+	default:
+		return fmt.Errorf("unsupported type for WebhookEvents: %T", value)
+	}
+}
+
+func (s WebhookDeliveryStatus) String() string {
+	if name, ok := webhookDeliveryStatusNames[s]; ok {
+		return name
+	}
+	return fmt.Sprintf("WebhookDeliveryStatus(%d)", int64(s))
+}
+
+// encoding/json/Marshaler
+func (s WebhookDeliveryStatus) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
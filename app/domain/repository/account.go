@@ -10,6 +10,13 @@ import (
 type Account interface {
 	FindByID(ctx context.Context, id object.AccountID) (*object.Account, error)
 	FindByIDs(ctx context.Context, ids []object.AccountID) ([]*object.Account, error)
+	// Find a local account by username
 	FindByUsername(ctx context.Context, username string) (*object.Account, error)
-	Insert(ctx context.Context, username, passwordHash string, createAt time.Time) error
+	// Find a remote account by its ActivityPub actor ID
+	FindByURI(ctx context.Context, uri string) (*object.Account, error)
+	Insert(ctx context.Context, username, passwordHash, privateKey, publicKey string, createAt time.Time) error
+	// Count local accounts and their statuses, with accounts which have posted since each of the times as active
+	Usage(ctx context.Context, activeMonthSince, activeHalfyearSince time.Time) (*object.InstanceUsage, error)
+	// Store a remote account, updating its profile, inboxes and public key if already stored
+	UpsertRemote(ctx context.Context, account *object.Account) (object.AccountID, error)
 }
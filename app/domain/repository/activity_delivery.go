@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/satorunooshie/Yatter/app/domain/object"
+)
+
+type ActivityDelivery interface {
+	// Queue the activity for each of the inboxes, to be signed by the account
+	Insert(ctx context.Context, accountID object.AccountID, activity []byte, inboxURLs []string) error
+	// Select pending deliveries whose next attempt is due, oldest first
+	SelectDue(ctx context.Context, until time.Time, limit int64) ([]*object.ActivityDelivery, error)
+	// Start an attempt of the pending delivery which has been attempted the times,
+	// postponing its next attempt to leaseUntil in case the attempt never finishes.
+	// false if another instance started it first.
+	Claim(ctx context.Context, id object.ActivityDeliveryID, attempts int64, leaseUntil time.Time) (bool, error)
+	// Record the attempt succeeded
+	Succeed(ctx context.Context, id object.ActivityDeliveryID) error
+	// Record the attempt failed, retrying at nextAttemptAt or giving up if it is nil
+	Fail(ctx context.Context, id object.ActivityDeliveryID, reason string, nextAttemptAt *time.Time) error
+}
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/satorunooshie/Yatter/app/domain/object"
+)
+
+type Bookmark interface {
+	// Select bookmarks of the account whose statuses are not deleted, newest first
+	Select(ctx context.Context, accountID object.AccountID, page object.Page) ([]*object.Bookmark, error)
+	// Find which of the statuses the account has bookmarked
+	FindBookmarked(ctx context.Context, accountID object.AccountID, statusIDs []object.StatusID) ([]object.StatusID, error)
+	// Bookmark the status, doing nothing if already bookmarked
+	Insert(ctx context.Context, accountID object.AccountID, statusID object.StatusID) error
+	Delete(ctx context.Context, accountID object.AccountID, statusID object.StatusID) error
+}
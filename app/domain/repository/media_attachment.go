@@ -7,6 +7,12 @@ import (
 )
 
 type MediaAttachment interface {
+	// Find media attachments including deleted ones, which may be referred by edit history
+	FindByIDs(ctx context.Context, ids []object.MediaAttachmentID) ([]*object.MediaAttachment, error)
 	FindByStatusIDs(ctx context.Context, statusIDs []object.StatusID) ([]*object.MediaAttachment, error)
-	Select(ctx context.Context, minID, maxID, limit int64) ([]*object.MediaAttachment, error)
+	// Select media attachments of public statuses, newest status first.
+	// The page is of status IDs and takes all the attachments of each status.
+	Select(ctx context.Context, page object.Page) ([]*object.MediaAttachment, error)
+	// Delete media attachments of the status except the ones to keep
+	Detach(ctx context.Context, statusID object.StatusID, keepIDs []object.MediaAttachmentID) error
 }
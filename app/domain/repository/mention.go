@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/satorunooshie/Yatter/app/domain/object"
+)
+
+type Mention interface {
+	FindByStatusIDs(ctx context.Context, statusIDs []object.StatusID) ([]*object.Mention, error)
+	Insert(ctx context.Context, statusID object.StatusID, accountIDs []object.AccountID) error
+	// Delete all mentions in the status
+	Delete(ctx context.Context, statusID object.StatusID) error
+}
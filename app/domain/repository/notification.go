@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/satorunooshie/Yatter/app/domain/object"
+)
+
+type Notification interface {
+	// Select notifications of the account with any of the types, newest first
+	//
+	// Notifications about deleted statuses or from deleted accounts are skipped.
+	Select(ctx context.Context, accountID object.AccountID, types []object.NotificationType, page object.Page) ([]*object.Notification, error)
+	// Find notifications about the status
+	FindByStatusID(ctx context.Context, statusID object.StatusID) ([]*object.Notification, error)
+	// Notify the accounts of the action, ignoring the ones already notified of the same action by the same account
+	Insert(ctx context.Context, typ object.NotificationType, fromAccountID object.AccountID, statusID object.StatusID, accountIDs []object.AccountID) error
+	// Dismiss a notification of the account
+	Delete(ctx context.Context, id object.NotificationID, accountID object.AccountID) error
+	// Dismiss all notifications of the account
+	DeleteAll(ctx context.Context, accountID object.AccountID) error
+}
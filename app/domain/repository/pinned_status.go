@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/satorunooshie/Yatter/app/domain/object"
+)
+
+// Returned by PinnedStatus.Insert when the account has already pinned as many statuses as allowed
+var ErrPinLimit = errors.New("too many pinned statuses")
+
+type PinnedStatus interface {
+	// Find IDs of statuses pinned by the account, most recently pinned first
+	FindByAccountID(ctx context.Context, accountID object.AccountID) ([]object.StatusID, error)
+	// Find which of the statuses are pinned
+	FindPinned(ctx context.Context, statusIDs []object.StatusID) ([]object.StatusID, error)
+	// Pin the status, doing nothing if already pinned and failing with ErrPinLimit if max statuses are pinned
+	Insert(ctx context.Context, accountID object.AccountID, statusID object.StatusID, max int) error
+	Delete(ctx context.Context, accountID object.AccountID, statusID object.StatusID) error
+}
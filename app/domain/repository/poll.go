@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/satorunooshie/Yatter/app/domain/object"
+)
+
+// Returned by Poll.Vote when the account has already voted
+var ErrAlreadyVoted = errors.New("already voted")
+
+type Poll interface {
+	FindByID(ctx context.Context, id object.PollID) (*object.Poll, error)
+	FindByStatusIDs(ctx context.Context, statusIDs []object.StatusID) ([]*object.Poll, error)
+	// Find votes the account made in the polls
+	FindVotes(ctx context.Context, pollIDs []object.PollID, accountID object.AccountID) ([]*object.PollVote, error)
+	Insert(ctx context.Context, statusID object.StatusID, options []string, expireAt time.Time, multiple, hideTotals bool) (object.PollID, error)
+	// Vote for the choices, failing with ErrAlreadyVoted if the account has already voted
+	Vote(ctx context.Context, id object.PollID, accountID object.AccountID, choices []int) error
+}
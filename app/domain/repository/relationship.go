@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/satorunooshie/Yatter/app/domain/object"
+)
+
+type Relationship interface {
+	// Check if follower is following followee
+	IsFollowing(ctx context.Context, followerID, followeeID object.AccountID) (bool, error)
+	// Select inboxes of remote accounts following followee, each shared inbox once
+	SelectFollowerInboxes(ctx context.Context, followeeID object.AccountID) ([]string, error)
+	// Make follower follow followee, notifying followee if local, doing nothing if already following
+	Follow(ctx context.Context, followerID, followeeID object.AccountID) error
+	// Make follower stop following followee, doing nothing if not following
+	Unfollow(ctx context.Context, followerID, followeeID object.AccountID) error
+}
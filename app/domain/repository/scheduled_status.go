@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/satorunooshie/Yatter/app/domain/object"
+)
+
+// Returned by ScheduledStatus.Update and Delete when the account has no such scheduled status,
+// which may have been published or canceled meanwhile
+var ErrScheduledStatusNotFound = errors.New("scheduled status does not exist")
+
+type ScheduledStatus interface {
+	FindByID(ctx context.Context, id object.ScheduledStatusID) (*object.ScheduledStatus, error)
+	// Select scheduled statuses of the account, earliest first
+	SelectByAccountID(ctx context.Context, accountID object.AccountID, limit int64) ([]*object.ScheduledStatus, error)
+	// Select scheduled statuses due by the time, earliest first
+	SelectDue(ctx context.Context, until time.Time, limit int64) ([]*object.ScheduledStatus, error)
+	Insert(ctx context.Context, accountID object.AccountID, scheduledAt time.Time, params object.StatusParams) (object.ScheduledStatusID, error)
+	// Reschedule the scheduled status
+	Update(ctx context.Context, id object.ScheduledStatusID, accountID object.AccountID, scheduledAt time.Time) error
+	// Cancel the scheduled status
+	Delete(ctx context.Context, id object.ScheduledStatusID, accountID object.AccountID) error
+	// Insert the status with its mentions, tags and poll and remove the scheduled one atomically if it is due by the time.
+	// Returns 0 if it has already been published, canceled or rescheduled.
+	Publish(ctx context.Context, id object.ScheduledStatusID, until time.Time, extras object.StatusExtras) (object.StatusID, error)
+}
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/satorunooshie/Yatter/app/domain/object"
+)
+
+// Full-text search
+//
+// The MySQL implementation is in dao; search.Memory keeps everything in memory for tests.
+type Search interface {
+	// Search statuses the viewer (0 for anonymous) is allowed to read, newest first
+	Statuses(ctx context.Context, query object.SearchQuery, viewerID object.AccountID, page object.Page) ([]*object.Status, error)
+	// Search accounts which are neither suspended nor deleted by username and display name, newest first
+	Accounts(ctx context.Context, query object.SearchQuery, page object.Page) ([]*object.Account, error)
+	// Search tags whose names start with the first term, newest first
+	Hashtags(ctx context.Context, query object.SearchQuery, page object.Page) ([]*object.Tag, error)
+	// Find accounts which are neither suspended nor deleted whose username or display name starts with the prefix,
+	// accounts the viewer (0 for anonymous) follows first and then in the order of username
+	AccountsByPrefix(ctx context.Context, prefix string, viewerID object.AccountID, limit int64) ([]*object.Account, error)
+}
@@ -2,14 +2,33 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/satorunooshie/Yatter/app/domain/object"
 )
 
+var ErrStatusNotFound = errors.New("status does not exist")
+
 type Status interface {
 	FindByID(ctx context.Context, id object.StatusID) (*object.Status, error)
 	FindByIDs(ctx context.Context, id []object.StatusID) ([]*object.Status, error)
-	Select(ctx context.Context, minID, maxID, limit int64) ([]*object.Status, error)
-	Insert(ctx context.Context, accountID object.AccountID, content string) (object.StatusID, error)
+	// Find a remote status by its ActivityPub object ID
+	FindByURI(ctx context.Context, uri string) (*object.Status, error)
+	// Select public statuses, newest first
+	Select(ctx context.Context, page object.Page) ([]*object.Status, error)
+	// Select public statuses tagged with the tag, newest first
+	SelectByTag(ctx context.Context, tag string, onlyMedia bool, page object.Page) ([]*object.Status, error)
+	// Select statuses of the account with the visibilities, newest first
+	SelectByAccountID(ctx context.Context, accountID object.AccountID, visibilities []object.Visibility, onlyMedia, excludePinned bool, page object.Page) ([]*object.Status, error)
+	// Insert the status with its mentions, tags and poll, notifying the mentioned accounts
+	Insert(ctx context.Context, accountID object.AccountID, content string, visibility object.Visibility, extras object.StatusExtras) (object.StatusID, error)
+	// Store a status received from a remote server
+	InsertRemote(ctx context.Context, accountID object.AccountID, uri, content string, visibility object.Visibility, createAt time.Time) (object.StatusID, error)
+	// Update content, keeping the previous version with its media attachments as a StatusEdit.
+	// Media attachments other than keepMediaAttachmentIDs are detached unless it is nil,
+	// and mentions and tags are replaced with the ones in extras, notifying only newly mentioned accounts.
+	// Fails with ErrStatusNotFound unless the account has the status.
+	Update(ctx context.Context, id object.StatusID, accountID object.AccountID, content string, prevMediaAttachmentIDs, keepMediaAttachmentIDs []object.MediaAttachmentID, extras object.StatusExtras) error
 	Delete(ctx context.Context, id object.StatusID, accountID object.AccountID) error
 }
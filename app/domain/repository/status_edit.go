@@ -0,0 +1,12 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/satorunooshie/Yatter/app/domain/object"
+)
+
+type StatusEdit interface {
+	// Find previous versions of the status, oldest first
+	FindByStatusID(ctx context.Context, statusID object.StatusID) ([]*object.StatusEdit, error)
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/satorunooshie/Yatter/app/domain/object"
+)
+
+type Tag interface {
+	FindByStatusIDs(ctx context.Context, statusIDs []object.StatusID) ([]*object.Tag, error)
+	// Attach tags to the status, creating tags which do not exist yet
+	Attach(ctx context.Context, statusID object.StatusID, names []string) ([]*object.Tag, error)
+	// Detach all tags from the status
+	Detach(ctx context.Context, statusID object.StatusID) error
+	// Select uses of tags in public statuses of active accounts created since the time
+	SelectUsages(ctx context.Context, since time.Time) ([]*object.TagUsage, error)
+}
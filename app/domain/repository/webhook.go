@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/satorunooshie/Yatter/app/domain/object"
+)
+
+type Webhook interface {
+	FindByID(ctx context.Context, id object.WebhookID) (*object.Webhook, error)
+	// Select all webhooks, oldest first
+	Select(ctx context.Context) ([]*object.Webhook, error)
+	// Select enabled webhooks subscribed to the event
+	SelectByEvent(ctx context.Context, event object.WebhookEvent) ([]*object.Webhook, error)
+	Insert(ctx context.Context, url, secret string, events object.WebhookEvents) (object.WebhookID, error)
+	// Update the webhook, clearing its failure count when it is enabled
+	Update(ctx context.Context, id object.WebhookID, url string, events object.WebhookEvents, enabled bool) error
+	// Delete the webhook with its delivery log
+	Delete(ctx context.Context, id object.WebhookID) error
+}
+
+type WebhookDelivery interface {
+	// Select deliveries of the webhook, newest first
+	Select(ctx context.Context, webhookID object.WebhookID, page object.Page) ([]*object.WebhookDelivery, error)
+	// Queue the event for each of the webhooks
+	Insert(ctx context.Context, event object.WebhookEvent, payload []byte, webhookIDs []object.WebhookID) error
+	// Select pending deliveries of enabled webhooks whose next attempt is due, oldest first
+	SelectDue(ctx context.Context, until time.Time, limit int64) ([]*object.WebhookDelivery, error)
+	// Start an attempt of the pending delivery which has been attempted the times,
+	// postponing its next attempt to leaseUntil in case the attempt never finishes.
+	// false if another instance started it first.
+	Claim(ctx context.Context, id object.WebhookDeliveryID, attempts int64, leaseUntil time.Time) (bool, error)
+	// Record the attempt succeeded, clearing the failure count of the webhook
+	Succeed(ctx context.Context, id object.WebhookDeliveryID, webhookID object.WebhookID, responseStatus int) error
+	// Record the attempt failed, retrying at nextAttemptAt or giving up if it is nil.
+	// The webhook is disabled once it fails disableAfter times in a row.
+	Fail(ctx context.Context, id object.WebhookDeliveryID, webhookID object.WebhookID, responseStatus *int, reason string, nextAttemptAt *time.Time, disableAfter int64) error
+}
@@ -0,0 +1,84 @@
+package feed
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+type (
+	atomFeed struct {
+		XMLName  xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+		ID       string      `xml:"id"`
+		Title    string      `xml:"title"`
+		Subtitle string      `xml:"subtitle,omitempty"`
+		Updated  string      `xml:"updated"`
+		Links    []atomLink  `xml:"link"`
+		Entries  []atomEntry `xml:"entry"`
+	}
+
+	atomLink struct {
+		Rel    string `xml:"rel,attr"`
+		Type   string `xml:"type,attr,omitempty"`
+		Href   string `xml:"href,attr"`
+		Length int64  `xml:"length,attr,omitempty"`
+	}
+
+	atomEntry struct {
+		ID         string         `xml:"id"`
+		Title      string         `xml:"title"`
+		Published  string         `xml:"published"`
+		Updated    string         `xml:"updated"`
+		Author     atomAuthor     `xml:"author"`
+		Content    atomContent    `xml:"content"`
+		Links      []atomLink     `xml:"link"`
+		Categories []atomCategory `xml:"category"`
+	}
+
+	atomAuthor struct {
+		Name string `xml:"name"`
+	}
+
+	atomContent struct {
+		Type  string `xml:"type,attr"`
+		Value string `xml:",chardata"`
+	}
+
+	atomCategory struct {
+		Term string `xml:"term,attr"`
+	}
+)
+
+// Render the feed as Atom (RFC 4287)
+func Atom(f *Feed) ([]byte, error) {
+	doc := &atomFeed{
+		ID:       f.Self,
+		Title:    f.Title,
+		Subtitle: f.Description,
+		Updated:  f.Updated.UTC().Format(time.RFC3339),
+		Links: []atomLink{
+			{Rel: "alternate", Href: f.Link},
+			{Rel: "self", Type: AtomContentType, Href: f.Self},
+		},
+	}
+
+	for _, v := range f.Items {
+		entry := atomEntry{
+			ID:        v.Link,
+			Title:     v.Title,
+			Published: v.Published.UTC().Format(time.RFC3339),
+			Updated:   v.Updated.UTC().Format(time.RFC3339),
+			Author:    atomAuthor{Name: v.Author},
+			Content:   atomContent{Type: "html", Value: v.Content},
+			Links:     []atomLink{{Rel: "alternate", Href: v.Link}},
+		}
+		for _, e := range v.Enclosures {
+			entry.Links = append(entry.Links, atomLink{Rel: "enclosure", Type: e.Type, Href: e.URL, Length: e.Length})
+		}
+		for _, c := range v.Categories {
+			entry.Categories = append(entry.Categories, atomCategory{Term: c})
+		}
+		doc.Entries = append(doc.Entries, entry)
+	}
+
+	return marshal(doc)
+}
@@ -0,0 +1,48 @@
+package feed
+
+import (
+	"time"
+)
+
+const (
+	// Media type of RSS 2.0 feeds
+	RSSContentType = "application/rss+xml"
+
+	// Media type of Atom feeds
+	AtomContentType = "application/atom+xml"
+)
+
+type (
+	// Feed rendered either as RSS or Atom
+	Feed struct {
+		Title       string
+		Description string
+		// URL of the page the feed is about
+		Link string
+		// URL of the feed itself
+		Self    string
+		Updated time.Time
+		Items   []*Item
+	}
+
+	Item struct {
+		// Permanent URL of the item, used as its ID as well
+		Link   string
+		Title  string
+		Author string
+		// HTML content
+		Content    string
+		Published  time.Time
+		Updated    time.Time
+		Categories []string
+		Enclosures []*Enclosure
+	}
+
+	// Media attached to the item
+	Enclosure struct {
+		URL  string
+		Type string
+		// Size in bytes, 0 if unknown
+		Length int64
+	}
+)
@@ -0,0 +1,96 @@
+package feed
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+var (
+	published = time.Date(2022, 4, 1, 9, 0, 0, 0, time.UTC)
+	edited    = time.Date(2022, 4, 2, 9, 0, 0, 0, time.UTC)
+
+	testFeed = &Feed{
+		Title:       "john",
+		Description: "Public statuses of john",
+		Link:        "http://localhost:8080/users/john",
+		Self:        "http://localhost:8080/@john.rss",
+		Updated:     edited,
+		Items: []*Item{
+			{
+				Link:       "http://localhost:8080/users/john/statuses/2",
+				Title:      "New status by john",
+				Author:     "john",
+				Content:    "<p>hello <b>world</b> &amp; #go</p>",
+				Published:  published,
+				Updated:    edited,
+				Categories: []string{"go"},
+				Enclosures: []*Enclosure{{URL: "http://localhost:8080/media/a.png", Type: "image/png"}},
+			},
+		},
+	}
+)
+
+func TestRSS(t *testing.T) {
+	b, err := RSS(testFeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom">
+  <channel>
+    <title>john</title>
+    <link>http://localhost:8080/users/john</link>
+    <description>Public statuses of john</description>
+    <atom:link rel="self" type="application/rss+xml" href="http://localhost:8080/@john.rss"></atom:link>
+    <lastBuildDate>Sat, 02 Apr 2022 09:00:00 +0000</lastBuildDate>
+    <item>
+      <title>New status by john</title>
+      <link>http://localhost:8080/users/john/statuses/2</link>
+      <guid isPermaLink="true">http://localhost:8080/users/john/statuses/2</guid>
+      <pubDate>Fri, 01 Apr 2022 09:00:00 +0000</pubDate>
+      <description>&lt;p&gt;hello &lt;b&gt;world&lt;/b&gt; &amp;amp; #go&lt;/p&gt;</description>
+      <enclosure url="http://localhost:8080/media/a.png" type="image/png" length="0"></enclosure>
+      <category>go</category>
+    </item>
+  </channel>
+</rss>`
+	if diff := cmp.Diff(string(b), want); diff != "" {
+		t.Errorf("RSS() returned diff (want -> got):\n%s", diff)
+	}
+}
+
+func TestAtom(t *testing.T) {
+	b, err := Atom(testFeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <id>http://localhost:8080/@john.rss</id>
+  <title>john</title>
+  <subtitle>Public statuses of john</subtitle>
+  <updated>2022-04-02T09:00:00Z</updated>
+  <link rel="alternate" href="http://localhost:8080/users/john"></link>
+  <link rel="self" type="application/atom+xml" href="http://localhost:8080/@john.rss"></link>
+  <entry>
+    <id>http://localhost:8080/users/john/statuses/2</id>
+    <title>New status by john</title>
+    <published>2022-04-01T09:00:00Z</published>
+    <updated>2022-04-02T09:00:00Z</updated>
+    <author>
+      <name>john</name>
+    </author>
+    <content type="html">&lt;p&gt;hello &lt;b&gt;world&lt;/b&gt; &amp;amp; #go&lt;/p&gt;</content>
+    <link rel="alternate" href="http://localhost:8080/users/john/statuses/2"></link>
+    <link rel="enclosure" type="image/png" href="http://localhost:8080/media/a.png"></link>
+    <category term="go"></category>
+  </entry>
+</feed>`
+	if diff := cmp.Diff(string(b), want); diff != "" {
+		t.Errorf("Atom() returned diff (want -> got):\n%s", diff)
+	}
+}
@@ -0,0 +1,87 @@
+package feed
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+type (
+	rss struct {
+		XMLName xml.Name   `xml:"rss"`
+		Version string     `xml:"version,attr"`
+		Atom    string     `xml:"xmlns:atom,attr"`
+		Channel rssChannel `xml:"channel"`
+	}
+
+	rssChannel struct {
+		Title         string    `xml:"title"`
+		Link          string    `xml:"link"`
+		Description   string    `xml:"description"`
+		Self          atomLink  `xml:"atom:link"`
+		LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+		Items         []rssItem `xml:"item"`
+	}
+
+	rssItem struct {
+		Title       string         `xml:"title,omitempty"`
+		Link        string         `xml:"link"`
+		GUID        rssGUID        `xml:"guid"`
+		PubDate     string         `xml:"pubDate"`
+		Description string         `xml:"description"`
+		Enclosures  []rssEnclosure `xml:"enclosure"`
+		Categories  []string       `xml:"category"`
+	}
+
+	rssGUID struct {
+		IsPermaLink bool   `xml:"isPermaLink,attr"`
+		Value       string `xml:",chardata"`
+	}
+
+	rssEnclosure struct {
+		URL    string `xml:"url,attr"`
+		Type   string `xml:"type,attr"`
+		Length int64  `xml:"length,attr"`
+	}
+)
+
+// Render the feed as RSS 2.0
+func RSS(f *Feed) ([]byte, error) {
+	doc := &rss{
+		Version: "2.0",
+		Atom:    "http://www.w3.org/2005/Atom",
+		Channel: rssChannel{
+			Title:       f.Title,
+			Link:        f.Link,
+			Description: f.Description,
+			Self:        atomLink{Rel: "self", Type: RSSContentType, Href: f.Self},
+		},
+	}
+	if !f.Updated.IsZero() {
+		doc.Channel.LastBuildDate = f.Updated.UTC().Format(time.RFC1123Z)
+	}
+
+	for _, v := range f.Items {
+		item := rssItem{
+			Title:       v.Title,
+			Link:        v.Link,
+			GUID:        rssGUID{IsPermaLink: true, Value: v.Link},
+			PubDate:     v.Published.UTC().Format(time.RFC1123Z),
+			Description: v.Content,
+			Categories:  v.Categories,
+		}
+		for _, e := range v.Enclosures {
+			item.Enclosures = append(item.Enclosures, rssEnclosure{URL: e.URL, Type: e.Type, Length: e.Length})
+		}
+		doc.Channel.Items = append(doc.Channel.Items, item)
+	}
+
+	return marshal(doc)
+}
+
+func marshal(v interface{}) ([]byte, error) {
+	b, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), b...), nil
+}
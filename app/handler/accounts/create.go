@@ -3,11 +3,14 @@ package accounts
 import (
 	"encoding/json"
 	"errors"
+	"log"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/satorunooshie/Yatter/app/domain/object"
 	"github.com/satorunooshie/Yatter/app/handler/httperror"
+	"github.com/satorunooshie/Yatter/app/webhook"
 )
 
 // Request body for `POST /v1/accounts`
@@ -16,6 +19,9 @@ type AccountCreateRequest struct {
 	Password string `json:"password"`
 }
 
+// Usernames which would be shadowed by the other paths under `/v1/accounts/`
+var reservedUsernames = []string{"search"}
+
 // Handle request for `POST /v1/accounts`
 func (h *handler) Create(w http.ResponseWriter, r *http.Request) {
 	var req AccountCreateRequest
@@ -23,12 +29,22 @@ func (h *handler) Create(w http.ResponseWriter, r *http.Request) {
 		httperror.BadRequest(w, err)
 		return
 	}
+	for _, v := range reservedUsernames {
+		if strings.EqualFold(req.Username, v) {
+			httperror.BadRequest(w, errors.New("account name is reserved"))
+			return
+		}
+	}
 
 	account := new(object.Account)
 	if err := account.SetPassword(req.Password); err != nil {
 		httperror.InternalServerError(w, err)
 		return
 	}
+	if err := account.GenerateKeys(); err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
 
 	ctx := r.Context()
 	accountRepo := h.app.Dao.Account() // domain/repository の取得
@@ -45,11 +61,21 @@ func (h *handler) Create(w http.ResponseWriter, r *http.Request) {
 
 	account.Username = req.Username
 	account.CreateAt = object.DateTime{Time: time.Now()}
-	if err := accountRepo.Insert(ctx, account.Username, account.PasswordHash, account.CreateAt.Time); err != nil {
+	if err := accountRepo.Insert(ctx, account.Username, account.PasswordHash, *account.PrivateKey, *account.PublicKey, account.CreateAt.Time); err != nil {
 		httperror.InternalServerError(w, err)
 		return
 	}
 
+	/* 作成済みなので通知の失敗はログに残すだけにする */
+	created, err := accountRepo.FindByUsername(ctx, account.Username)
+	if err != nil {
+		log.Printf("[WARN] accounts::Create::FindByUsername(%s): %v", account.Username, err)
+	} else if created != nil {
+		if err := webhook.Enqueue(ctx, h.app.Dao, object.WebhookAccountCreated, created); err != nil {
+			log.Printf("[WARN] accounts::Create::webhook.Enqueue(%s): %v", account.Username, err)
+		}
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(account); err != nil {
 		httperror.InternalServerError(w, err)
@@ -6,6 +6,7 @@ import (
 	"github.com/go-chi/chi"
 
 	"github.com/satorunooshie/Yatter/app/app"
+	"github.com/satorunooshie/Yatter/app/handler/auth"
 )
 
 // Implementation of handler
@@ -19,7 +20,9 @@ func NewRouter(app *app.App) http.Handler {
 
 	h := &handler{app: app}
 	r.Post("/", h.Create)
+	r.With(auth.OptionalMiddleware(app)).Get("/search", h.Search)
 	r.Get("/{username}", h.Get)
+	r.With(auth.OptionalMiddleware(app)).Get("/{username}/statuses", h.GetStatuses)
 
 	return r
 }
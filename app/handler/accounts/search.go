@@ -0,0 +1,49 @@
+package accounts
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/satorunooshie/Yatter/app/domain/object"
+	"github.com/satorunooshie/Yatter/app/handler/auth"
+	"github.com/satorunooshie/Yatter/app/handler/httperror"
+	"github.com/satorunooshie/Yatter/app/handler/pagination"
+)
+
+// Handle request for `GET /v1/accounts/search`
+//
+// For autocompleting mentions, `q` is the beginning of username or display name with or without "@".
+func (h *handler) Search(w http.ResponseWriter, r *http.Request) {
+	prefix := strings.TrimPrefix(strings.TrimSpace(r.URL.Query().Get("q")), "@")
+	if prefix == "" {
+		httperror.BadRequest(w, errors.New("q is required"))
+		return
+	}
+	limit, err := pagination.Limit(r, 40, 80)
+	if err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+
+	var viewerID object.AccountID
+	if viewer := auth.AccountOf(r); viewer != nil {
+		viewerID = viewer.ID
+	}
+
+	accounts, err := h.app.Dao.Search().AccountsByPrefix(r.Context(), prefix, viewerID, limit)
+	if err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+	if accounts == nil {
+		accounts = make([]*object.Account, 0)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(&accounts); err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+}
@@ -0,0 +1,112 @@
+package accounts
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi"
+
+	"github.com/satorunooshie/Yatter/app/domain/object"
+	"github.com/satorunooshie/Yatter/app/handler/auth"
+	"github.com/satorunooshie/Yatter/app/handler/fill"
+	"github.com/satorunooshie/Yatter/app/handler/httperror"
+	"github.com/satorunooshie/Yatter/app/handler/pagination"
+	"github.com/satorunooshie/Yatter/app/handler/request"
+	"github.com/satorunooshie/Yatter/app/handler/visibility"
+)
+
+// Handle request for `GET /v1/accounts/{username}/statuses`
+//
+// The first page starts with the pinned statuses, which are left out of the rest of the listing.
+// With only_media, pinned statuses are not put first and appear in their place instead.
+// exclude_replies and exclude_reblogs are accepted but have nothing to leave out, as statuses can't be replies nor reblogs yet.
+func (h *handler) GetStatuses(w http.ResponseWriter, r *http.Request) {
+	username := chi.URLParam(r, "username")
+	if username == "" {
+		httperror.BadRequest(w, errors.New("invalid params"))
+		return
+	}
+
+	q, err := validateStatusesQuery(r)
+	if err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+
+	ctx := r.Context()
+	viewer := auth.AccountOf(r)
+
+	account, err := h.app.Dao.Account().FindByUsername(ctx, username)
+	if err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+	if account == nil {
+		httperror.BadRequest(w, errors.New("account does not exist"))
+		return
+	}
+
+	visibilities, err := visibility.Visibilities(ctx, h.app.Dao, viewer, account.ID)
+	if err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+
+	statuses := make([]*object.Status, 0, q.page.Limit)
+
+	/* 絞り込みのない一覧では固定したステータスを最初のページの先頭に詰め、残りからは除く */
+	pinnedFirst := !q.onlyMedia
+	if pinnedFirst && q.page.IsFirst() {
+		pinned, err := visibility.PinnedStatuses(ctx, h.app.Dao, account.ID, visibilities)
+		if err != nil {
+			httperror.InternalServerError(w, err)
+			return
+		}
+		statuses = append(statuses, pinned...)
+	}
+
+	found, err := h.app.Dao.Status().SelectByAccountID(ctx, account.ID, visibilities, q.onlyMedia, pinnedFirst, q.page)
+	if err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+	statuses = append(statuses, found...)
+
+	if err := fill.Statuses(ctx, h.app.Dao, viewer, statuses); err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+
+	/* 固定したステータスは ID 順に並ばないので前後のページは残りから決める */
+	if len(found) != 0 {
+		pagination.SetLink(w, r, pagination.CursorStyle, found[0].ID, found[len(found)-1].ID)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(&statuses); err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+}
+
+// Query parameters for `GET /v1/accounts/{username}/statuses`
+type statusesQuery struct {
+	page      object.Page
+	onlyMedia bool
+}
+
+func validateStatusesQuery(r *http.Request) (q statusesQuery, err error) {
+	if q.page, err = pagination.Parse(r, 20, 40); err != nil {
+		return
+	}
+	if q.onlyMedia, err = request.DecodeParam2Bool(r, "only_media"); err != nil {
+		return
+	}
+	/* 返信もブーストもまだないので、指定されても除くものがない */
+	for _, name := range []string{"exclude_replies", "exclude_reblogs"} {
+		if _, err := request.DecodeParam2Bool(r, name); err != nil {
+			return q, err
+		}
+	}
+	return q, nil
+}
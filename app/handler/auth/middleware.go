@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/satorunooshie/Yatter/app/app"
+	"github.com/satorunooshie/Yatter/app/config"
 	"github.com/satorunooshie/Yatter/app/domain/object"
 	"github.com/satorunooshie/Yatter/app/handler/httperror"
 )
@@ -16,24 +17,7 @@ var contextKey = new(struct{})
 func Middleware(app *app.App) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			ctx := r.Context()
-
-			// ヘッダーから Username を取り出すだけの超安易な認証
-			a := r.Header.Get("Authentication")
-			pair := strings.SplitN(a, " ", 2)
-			if len(pair) < 2 {
-				httperror.Error(w, http.StatusUnauthorized)
-				return
-			}
-
-			authType := pair[0]
-			if !strings.EqualFold(authType, "username") {
-				httperror.Error(w, http.StatusUnauthorized)
-				return
-			}
-
-			username := pair[1]
-			account, err := app.Dao.Account().FindByUsername(ctx, username)
+			account, err := authenticate(app, r)
 			if err != nil {
 				httperror.InternalServerError(w, err)
 				return
@@ -47,6 +31,54 @@ func Middleware(app *app.App) func(http.Handler) http.Handler {
 	}
 }
 
+// Auth by header if present, letting anonymous requests through
+func OptionalMiddleware(app *app.App) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authentication") == "" && r.Header.Get("Authorization") == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			Middleware(app)(next).ServeHTTP(w, r)
+		})
+	}
+}
+
+// Auth by header, letting only accounts listed as admins through
+func AdminMiddleware(app *app.App) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return Middleware(app)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !config.Admin.IsAdmin(AccountOf(r).Username) {
+				httperror.Error(w, http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		}))
+	}
+}
+
+func authenticate(app *app.App, r *http.Request) (*object.Account, error) {
+	// ヘッダーから Username を取り出すだけの超安易な認証
+	a := r.Header.Get("Authentication")
+	authType := "username"
+	if a == "" {
+		/* Mastodon のクライアントはトークンとして Username を送る */
+		a = r.Header.Get("Authorization")
+		authType = "bearer"
+	}
+	pair := strings.SplitN(a, " ", 2)
+	if len(pair) < 2 {
+		return nil, nil
+	}
+
+	if !strings.EqualFold(pair[0], authType) {
+		return nil, nil
+	}
+
+	username := pair[1]
+	return app.Dao.Account().FindByUsername(r.Context(), username)
+}
+
 // Read Account data from authorized request
 func AccountOf(r *http.Request) *object.Account {
 	cv := r.Context().Value(contextKey)
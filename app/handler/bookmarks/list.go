@@ -0,0 +1,75 @@
+package bookmarks
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/satorunooshie/Yatter/app/domain/object"
+	"github.com/satorunooshie/Yatter/app/handler/auth"
+	"github.com/satorunooshie/Yatter/app/handler/fill"
+	"github.com/satorunooshie/Yatter/app/handler/httperror"
+	"github.com/satorunooshie/Yatter/app/handler/pagination"
+)
+
+// Handle request for `GET /v1/bookmarks`
+//
+// Cursors are bookmark IDs rather than status IDs, so they are given in the Link header.
+func (h *handler) List(w http.ResponseWriter, r *http.Request) {
+	account := auth.AccountOf(r)
+	if account == nil {
+		httperror.Error(w, http.StatusUnauthorized)
+		return
+	}
+
+	page, err := pagination.Parse(r, 20, 40)
+	if err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+
+	ctx := r.Context()
+
+	bookmarks, err := h.app.Dao.Bookmark().Select(ctx, account.ID, page)
+	if err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+
+	statuses := make([]*object.Status, 0, len(bookmarks))
+	if len(bookmarks) != 0 {
+		statusIDs := make([]object.StatusID, 0, len(bookmarks))
+		for _, v := range bookmarks {
+			statusIDs = append(statusIDs, v.StatusID)
+		}
+		found, err := h.app.Dao.Status().FindByIDs(ctx, statusIDs)
+		if err != nil {
+			httperror.InternalServerError(w, err)
+			return
+		}
+
+		/* ブックマークした順に並べる */
+		statusMap := make(map[object.StatusID]*object.Status, len(found))
+		for _, v := range found {
+			statusMap[v.ID] = v
+		}
+		for _, v := range bookmarks {
+			if s, ok := statusMap[v.StatusID]; ok {
+				statuses = append(statuses, s)
+			}
+		}
+	}
+
+	if err := fill.Statuses(ctx, h.app.Dao, account, statuses); err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+
+	if len(bookmarks) != 0 {
+		pagination.SetLink(w, r, pagination.CursorStyle, bookmarks[0].ID, bookmarks[len(bookmarks)-1].ID)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(&statuses); err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+}
@@ -0,0 +1,26 @@
+package bookmarks
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi"
+
+	"github.com/satorunooshie/Yatter/app/app"
+	"github.com/satorunooshie/Yatter/app/handler/auth"
+)
+
+// Implementation of handler
+type handler struct {
+	app *app.App
+}
+
+// Create Handler for `/v1/bookmarks/`
+func NewRouter(app *app.App) http.Handler {
+	r := chi.NewRouter()
+
+	h := &handler{app: app}
+	r.Use(auth.Middleware(app))
+	r.Get("/", h.List)
+
+	return r
+}
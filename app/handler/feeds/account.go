@@ -0,0 +1,51 @@
+package feeds
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi"
+
+	"github.com/satorunooshie/Yatter/app/domain/object"
+	"github.com/satorunooshie/Yatter/app/feed"
+	"github.com/satorunooshie/Yatter/app/handler/fill"
+	"github.com/satorunooshie/Yatter/app/handler/httperror"
+)
+
+// Handle request for `GET /@{username}.rss` and `GET /@{username}.atom`
+func (h *handler) GetAccount(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	account, err := h.app.Dao.Account().FindByUsername(ctx, chi.URLParam(r, "username"))
+	if err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+	if account == nil {
+		httperror.Error(w, http.StatusNotFound)
+		return
+	}
+
+	statuses, err := h.app.Dao.Status().SelectByAccountID(ctx, account.ID, []object.Visibility{object.VisibilityPublic}, false, false, object.NewestPage(feedLimit))
+	if err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+	if err := fill.Statuses(ctx, h.app.Dao, nil, statuses); err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+
+	title := account.Username
+	if account.DisplayName != nil && *account.DisplayName != "" {
+		title = *account.DisplayName
+	}
+	serve(w, r, &feed.Feed{
+		Title:       title,
+		Description: "Public statuses of @" + account.Username,
+		Link:        h.app.Federation.ActorURI(account.Username),
+		Self:        h.app.Federation.BaseURL() + "/@" + account.Username + "." + chi.URLParam(r, "format"),
+		/* 投稿がなければアカウントの作成以降変わっていない */
+		Updated: account.CreateAt.Time,
+		Items:   h.items(statuses),
+	})
+}
@@ -0,0 +1,124 @@
+package feeds
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+
+	"github.com/go-chi/chi"
+
+	"github.com/satorunooshie/Yatter/app/app"
+	"github.com/satorunooshie/Yatter/app/domain/object"
+	"github.com/satorunooshie/Yatter/app/feed"
+	"github.com/satorunooshie/Yatter/app/handler/httperror"
+)
+
+// Number of the latest statuses in a feed
+const feedLimit = 20
+
+// Implementation of handler
+type handler struct {
+	app *app.App
+}
+
+// Create Handler for RSS and Atom feeds of public statuses
+func NewRouter(app *app.App) http.Handler {
+	r := chi.NewRouter()
+
+	h := &handler{app: app}
+	r.Get("/@{username}.{format:rss|atom}", h.GetAccount)
+	r.Get("/tags/{hashtag}.{format:rss|atom}", h.GetTag)
+
+	return r
+}
+
+// Convert statuses filled with their accounts and media attachments into feed items
+func (h *handler) items(statuses []*object.Status) []*feed.Item {
+	base, _ := url.Parse(h.app.Federation.BaseURL())
+
+	items := make([]*feed.Item, 0, len(statuses))
+	for _, s := range statuses {
+		if s.Account == nil {
+			continue
+		}
+		item := &feed.Item{
+			Title:     "New status by " + acct(s.Account),
+			Author:    acct(s.Account),
+			Content:   s.Content,
+			Published: s.CreateAt.Time,
+			Updated:   s.CreateAt.Time,
+		}
+		if s.URI != nil {
+			item.Link = *s.URI
+		} else {
+			item.Link = h.app.Federation.StatusURI(s.Account.Username, s.ID)
+		}
+		if s.EditedAt != nil {
+			item.Updated = s.EditedAt.Time
+		}
+		for _, t := range s.Tags {
+			item.Categories = append(item.Categories, t.Name)
+		}
+		for _, m := range s.MediaAttachment {
+			ref, err := url.Parse(m.URL)
+			if err != nil {
+				continue
+			}
+			/* 種類が分からない場合も添付されていることは伝える */
+			mediaType := mime.TypeByExtension(path.Ext(ref.Path))
+			if mediaType == "" {
+				mediaType = "application/octet-stream"
+			}
+			item.Enclosures = append(item.Enclosures, &feed.Enclosure{URL: base.ResolveReference(ref).String(), Type: mediaType})
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// Username with the domain for remote accounts
+func acct(a *object.Account) string {
+	if a.IsLocal() {
+		return a.Username
+	}
+	return a.Username + "@" + a.Domain
+}
+
+// Response with the feed in the format of the path.
+//
+// The ETag is the hash of the rendered feed and Last-Modified is the time of the latest update,
+// so that conditional requests of feed readers are answered with Not Modified (304).
+func serve(w http.ResponseWriter, r *http.Request, f *feed.Feed) {
+	for _, v := range f.Items {
+		if v.Updated.After(f.Updated) {
+			f.Updated = v.Updated
+		}
+	}
+
+	var (
+		b           []byte
+		contentType string
+		err         error
+	)
+	switch chi.URLParam(r, "format") {
+	case "atom":
+		b, err = feed.Atom(f)
+		contentType = feed.AtomContentType
+	default:
+		b, err = feed.RSS(f)
+		contentType = feed.RSSContentType
+	}
+	if err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+
+	sum := sha256.Sum256(b)
+	w.Header().Set("Content-Type", contentType+"; charset=utf-8")
+	w.Header().Set("ETag", `"`+hex.EncodeToString(sum[:16])+`"`)
+	http.ServeContent(w, r, "", f.Updated, bytes.NewReader(b))
+}
@@ -0,0 +1,49 @@
+package feeds
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/go-chi/chi"
+
+	"github.com/satorunooshie/Yatter/app/domain/content"
+	"github.com/satorunooshie/Yatter/app/domain/object"
+	"github.com/satorunooshie/Yatter/app/feed"
+	"github.com/satorunooshie/Yatter/app/handler/fill"
+	"github.com/satorunooshie/Yatter/app/handler/httperror"
+)
+
+// Handle request for `GET /tags/{hashtag}.rss` and `GET /tags/{hashtag}.atom`
+func (h *handler) GetTag(w http.ResponseWriter, r *http.Request) {
+	hashtag, err := url.PathUnescape(chi.URLParam(r, "hashtag"))
+	if err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+	hashtag = content.NormalizeTag(hashtag)
+	if !content.IsValidTag(hashtag) {
+		httperror.BadRequest(w, errors.New("invalid hashtag"))
+		return
+	}
+
+	ctx := r.Context()
+	statuses, err := h.app.Dao.Status().SelectByTag(ctx, hashtag, false, object.NewestPage(feedLimit))
+	if err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+	if err := fill.Statuses(ctx, h.app.Dao, nil, statuses); err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+
+	baseURL := h.app.Federation.BaseURL()
+	serve(w, r, &feed.Feed{
+		Title:       "#" + hashtag,
+		Description: "Public statuses tagged with #" + hashtag,
+		Link:        baseURL + "/v1/timelines/tag/" + url.PathEscape(hashtag),
+		Self:        baseURL + "/tags/" + url.PathEscape(hashtag) + "." + chi.URLParam(r, "format"),
+		Items:       h.items(statuses),
+	})
+}
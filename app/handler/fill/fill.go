@@ -0,0 +1,155 @@
+package fill
+
+import (
+	"context"
+	"time"
+
+	"github.com/satorunooshie/Yatter/app/dao"
+	"github.com/satorunooshie/Yatter/app/domain/content"
+	"github.com/satorunooshie/Yatter/app/domain/object"
+)
+
+// Fill statuses with their accounts, media attachments, mentions, tags, polls, whether they are pinned
+// and whether viewer bookmarked them, and render their content.
+// viewer is nil for anonymous requests.
+func Statuses(ctx context.Context, d dao.Dao, viewer *object.Account, statuses []*object.Status) error {
+	if len(statuses) == 0 {
+		return nil
+	}
+
+	statusIDs := make([]object.StatusID, 0, len(statuses))
+	accountIDs := make([]object.AccountID, 0, len(statuses))
+	for _, v := range statuses {
+		statusIDs = append(statusIDs, v.ID)
+		accountIDs = append(accountIDs, v.AccountID)
+	}
+
+	/* MediaAttachmentをレスポンスに詰める */
+	media, err := d.MediaAttachment().FindByStatusIDs(ctx, statusIDs)
+	if err != nil {
+		return err
+	}
+	mediaAttachmentMap := make(map[object.StatusID][]*object.MediaAttachment, len(media))
+	for _, v := range media {
+		mediaAttachmentMap[v.StatusID] = append(mediaAttachmentMap[v.StatusID], v)
+	}
+
+	/* Mentionをレスポンスに詰める */
+	mentions, err := d.Mention().FindByStatusIDs(ctx, statusIDs)
+	if err != nil {
+		return err
+	}
+	mentionMap := make(map[object.StatusID][]*object.Mention, len(mentions))
+	for _, v := range mentions {
+		mentionMap[v.StatusID] = append(mentionMap[v.StatusID], v)
+	}
+
+	/* Tagをレスポンスに詰める */
+	tags, err := d.Tag().FindByStatusIDs(ctx, statusIDs)
+	if err != nil {
+		return err
+	}
+	tagMap := make(map[object.StatusID][]*object.Tag, len(tags))
+	for _, v := range tags {
+		tagMap[v.StatusID] = append(tagMap[v.StatusID], v)
+	}
+
+	/* Pollをレスポンスに詰める */
+	pollMap, err := polls(ctx, d, viewer, statusIDs)
+	if err != nil {
+		return err
+	}
+
+	/* 固定されているかをレスポンスに詰める */
+	pinnedIDs, err := d.PinnedStatus().FindPinned(ctx, statusIDs)
+	if err != nil {
+		return err
+	}
+	pinned := make(map[object.StatusID]bool, len(pinnedIDs))
+	for _, id := range pinnedIDs {
+		pinned[id] = true
+	}
+
+	/* 閲覧者のBookmarkをレスポンスに詰める */
+	var bookmarked map[object.StatusID]bool
+	if viewer != nil {
+		ids, err := d.Bookmark().FindBookmarked(ctx, viewer.ID, statusIDs)
+		if err != nil {
+			return err
+		}
+		bookmarked = make(map[object.StatusID]bool, len(ids))
+		for _, id := range ids {
+			bookmarked[id] = true
+		}
+	}
+
+	/* AccountIDsからAccountを取得しレスポンスに詰める */
+	accounts, err := d.Account().FindByIDs(ctx, accountIDs)
+	if err != nil {
+		return err
+	}
+	accountMap := make(map[object.AccountID]*object.Account, len(accounts))
+	for _, v := range accounts {
+		accountMap[v.ID] = v
+	}
+
+	for _, v := range statuses {
+		v.MediaAttachment = mediaAttachmentMap[v.ID]
+		v.Mentions = mentionMap[v.ID]
+		v.Tags = tagMap[v.ID]
+		v.Poll = pollMap[v.ID]
+		v.Account = accountMap[v.AccountID]
+		v.Pinned = pinned[v.ID]
+		if bookmarked != nil {
+			b := bookmarked[v.ID]
+			v.Bookmarked = &b
+		}
+		v.Content = content.Render(v)
+	}
+	return nil
+}
+
+// Find polls attached to the statuses, prepared for viewer
+func polls(ctx context.Context, d dao.Dao, viewer *object.Account, statusIDs []object.StatusID) (map[object.StatusID]*object.Poll, error) {
+	polls, err := d.Poll().FindByStatusIDs(ctx, statusIDs)
+	if err != nil {
+		return nil, err
+	}
+	if err := PreparePolls(ctx, d, viewer, polls); err != nil {
+		return nil, err
+	}
+
+	pollMap := make(map[object.StatusID]*object.Poll, len(polls))
+	for _, v := range polls {
+		pollMap[v.StatusID] = v
+	}
+	return pollMap, nil
+}
+
+// Set viewer dependent fields of the polls
+func PreparePolls(ctx context.Context, d dao.Dao, viewer *object.Account, polls []*object.Poll) error {
+	if len(polls) == 0 {
+		return nil
+	}
+
+	ownVotes := make(map[object.PollID][]int)
+	if viewer != nil {
+		pollIDs := make([]object.PollID, 0, len(polls))
+		for _, v := range polls {
+			pollIDs = append(pollIDs, v.ID)
+		}
+		votes, err := d.Poll().FindVotes(ctx, pollIDs, viewer.ID)
+		if err != nil {
+			return err
+		}
+		for _, v := range votes {
+			ownVotes[v.PollID] = append(ownVotes[v.PollID], v.Choice)
+		}
+	}
+
+	now := time.Now()
+	for _, v := range polls {
+		v.Prepare(viewer, ownVotes[v.ID], now)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package follows
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/satorunooshie/Yatter/app/handler/auth"
+	"github.com/satorunooshie/Yatter/app/handler/httperror"
+)
+
+// Request body for `POST /v1/follows`
+type FollowCreateRequest struct {
+	// ActivityPub actor ID of the remote account
+	URI string `json:"uri"`
+}
+
+// Handle request for `POST /v1/follows`, following a remote account.
+//
+// The follow takes effect once the remote server accepts it.
+func (h *handler) Create(w http.ResponseWriter, r *http.Request) {
+	account := auth.AccountOf(r)
+	if account == nil {
+		httperror.Error(w, http.StatusUnauthorized)
+		return
+	}
+
+	var req FollowCreateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+	if req.URI == "" {
+		httperror.BadRequest(w, errors.New("uri must not be empty"))
+		return
+	}
+
+	followee, err := h.app.Federation.Follow(r.Context(), account, req.URI)
+	if err != nil {
+		/* 相手のサーバーから取得できないのはリクエストの問題として扱う */
+		httperror.BadRequest(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(followee); err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+}
@@ -0,0 +1,26 @@
+package follows
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi"
+
+	"github.com/satorunooshie/Yatter/app/app"
+	"github.com/satorunooshie/Yatter/app/handler/auth"
+)
+
+// Implementation of handler
+type handler struct {
+	app *app.App
+}
+
+// Create Handler for `/v1/follows/`
+func NewRouter(app *app.App) http.Handler {
+	r := chi.NewRouter()
+
+	h := &handler{app: app}
+	r.Use(auth.Middleware(app))
+	r.Post("/", h.Create)
+
+	return r
+}
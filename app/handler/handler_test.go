@@ -39,6 +39,15 @@ func TestAccountRegistration(t *testing.T) {
 		}
 	}()
 
+	func() {
+		/* /v1/accounts/search と衝突するので作れない */
+		resp, err := c.PostJSON("/v1/accounts", `{"username":"Search"}`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, resp.StatusCode, http.StatusBadRequest)
+	}()
+
 	func() {
 		resp, err := c.Get("/v1/accounts/john")
 		if err != nil {
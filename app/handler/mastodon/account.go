@@ -0,0 +1,75 @@
+package mastodon
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/satorunooshie/Yatter/app/config"
+	"github.com/satorunooshie/Yatter/app/handler/auth"
+	"github.com/satorunooshie/Yatter/app/handler/httperror"
+	"github.com/satorunooshie/Yatter/app/handler/request"
+)
+
+// Handle request for `GET /api/v1/accounts/verify_credentials`
+func (h *handler) VerifyCredentials(w http.ResponseWriter, r *http.Request) {
+	account := auth.AccountOf(r)
+	if account == nil {
+		httperror.Error(w, http.StatusUnauthorized)
+		return
+	}
+
+	encode(w, h.converter.credentialAccount(account))
+}
+
+// Handle request for `GET /api/v1/accounts/{id}`
+func (h *handler) GetAccount(w http.ResponseWriter, r *http.Request) {
+	id, err := request.IDOf(r)
+	if err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+
+	account, err := h.app.Dao.Account().FindByID(r.Context(), id)
+	if err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+	if account == nil {
+		httperror.Error(w, http.StatusNotFound)
+		return
+	}
+
+	encode(w, h.converter.account(account))
+}
+
+// Handle request for `GET /api/v1/accounts/lookup`
+//
+// Only local accounts are looked up, given as username or username@domain of this server.
+func (h *handler) LookupAccount(w http.ResponseWriter, r *http.Request) {
+	acct := strings.TrimPrefix(r.URL.Query().Get("acct"), "@")
+	if acct == "" {
+		httperror.BadRequest(w, errors.New("acct is required"))
+		return
+	}
+	username := acct
+	if i := strings.IndexByte(acct, '@'); i >= 0 {
+		if !strings.EqualFold(acct[i+1:], config.Federation.Domain()) {
+			httperror.Error(w, http.StatusNotFound)
+			return
+		}
+		username = acct[:i]
+	}
+
+	account, err := h.app.Dao.Account().FindByUsername(r.Context(), username)
+	if err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+	if account == nil {
+		httperror.Error(w, http.StatusNotFound)
+		return
+	}
+
+	encode(w, h.converter.account(account))
+}
@@ -0,0 +1,76 @@
+package mastodon
+
+import (
+	"net/http"
+
+	"github.com/satorunooshie/Yatter/app/handler/auth"
+	"github.com/satorunooshie/Yatter/app/handler/httperror"
+	"github.com/satorunooshie/Yatter/app/handler/pagination"
+	"github.com/satorunooshie/Yatter/app/handler/request"
+	"github.com/satorunooshie/Yatter/app/handler/visibility"
+)
+
+// Handle request for `GET /api/v1/accounts/{id}/statuses`
+//
+// Unlike `/v1/accounts/{username}/statuses`, pinned statuses are listed only with pinned=true
+// as Mastodon clients fetch them separately.
+func (h *handler) GetAccountStatuses(w http.ResponseWriter, r *http.Request) {
+	id, err := request.IDOf(r)
+	if err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+	page, err := pagination.Parse(r, 20, 40)
+	if err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+	onlyMedia, err := request.DecodeParam2Bool(r, "only_media")
+	if err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+	pinned, err := request.DecodeParam2Bool(r, "pinned")
+	if err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+
+	ctx := r.Context()
+
+	account, err := h.app.Dao.Account().FindByID(ctx, id)
+	if err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+	if account == nil {
+		httperror.Error(w, http.StatusNotFound)
+		return
+	}
+
+	visibilities, err := visibility.Visibilities(ctx, h.app.Dao, auth.AccountOf(r), account.ID)
+	if err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+
+	/* 固定したステータスはページに分けない */
+	if pinned {
+		statuses, err := visibility.PinnedStatuses(ctx, h.app.Dao, account.ID, visibilities)
+		if err != nil {
+			httperror.InternalServerError(w, err)
+			return
+		}
+		h.respondStatuses(w, r, statuses)
+		return
+	}
+
+	statuses, err := h.app.Dao.Status().SelectByAccountID(ctx, account.ID, visibilities, onlyMedia, false, page)
+	if err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+
+	setLink(w, r, statuses)
+	h.respondStatuses(w, r, statuses)
+}
@@ -0,0 +1,313 @@
+package mastodon
+
+import (
+	"html"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/satorunooshie/Yatter/app/activitypub"
+	"github.com/satorunooshie/Yatter/app/domain/object"
+)
+
+// Format of timestamps, ISO 8601 in UTC with milliseconds
+const timestampFormat = "2006-01-02T15:04:05.000Z"
+
+type (
+	// Entities of the Mastodon API, whose IDs are strings
+	Account struct {
+		ID             string        `json:"id"`
+		Username       string        `json:"username"`
+		Acct           string        `json:"acct"`
+		DisplayName    string        `json:"display_name"`
+		Locked         bool          `json:"locked"`
+		Bot            bool          `json:"bot"`
+		Group          bool          `json:"group"`
+		CreatedAt      string        `json:"created_at"`
+		Note           string        `json:"note"`
+		URL            string        `json:"url"`
+		Avatar         string        `json:"avatar"`
+		AvatarStatic   string        `json:"avatar_static"`
+		Header         string        `json:"header"`
+		HeaderStatic   string        `json:"header_static"`
+		FollowersCount int64         `json:"followers_count"`
+		FollowingCount int64         `json:"following_count"`
+		StatusesCount  int64         `json:"statuses_count"`
+		LastStatusAt   *string       `json:"last_status_at"`
+		Emojis         []interface{} `json:"emojis"`
+		Fields         []interface{} `json:"fields"`
+	}
+
+	// Account of the authenticated user with the source of the profile
+	CredentialAccount struct {
+		*Account
+		Source Source `json:"source"`
+	}
+
+	Source struct {
+		Privacy   string        `json:"privacy"`
+		Sensitive bool          `json:"sensitive"`
+		Language  string        `json:"language"`
+		Note      string        `json:"note"`
+		Fields    []interface{} `json:"fields"`
+	}
+
+	Status struct {
+		ID                 string             `json:"id"`
+		URI                string             `json:"uri"`
+		URL                string             `json:"url"`
+		CreatedAt          string             `json:"created_at"`
+		EditedAt           *string            `json:"edited_at"`
+		Account            *Account           `json:"account"`
+		Content            string             `json:"content"`
+		Visibility         string             `json:"visibility"`
+		Sensitive          bool               `json:"sensitive"`
+		SpoilerText        string             `json:"spoiler_text"`
+		InReplyToID        *string            `json:"in_reply_to_id"`
+		InReplyToAccountID *string            `json:"in_reply_to_account_id"`
+		Reblog             *Status            `json:"reblog"`
+		Language           *string            `json:"language"`
+		RepliesCount       int64              `json:"replies_count"`
+		ReblogsCount       int64              `json:"reblogs_count"`
+		FavouritesCount    int64              `json:"favourites_count"`
+		MediaAttachments   []*MediaAttachment `json:"media_attachments"`
+		Mentions           []*Mention         `json:"mentions"`
+		Tags               []*Tag             `json:"tags"`
+		Emojis             []interface{}      `json:"emojis"`
+		Card               interface{}        `json:"card"`
+		Poll               *Poll              `json:"poll"`
+		// Set only for authenticated requests
+		Bookmarked *bool `json:"bookmarked,omitempty"`
+		Pinned     *bool `json:"pinned,omitempty"`
+	}
+
+	MediaAttachment struct {
+		ID          string      `json:"id"`
+		Type        string      `json:"type"`
+		URL         string      `json:"url"`
+		PreviewURL  string      `json:"preview_url"`
+		RemoteURL   *string     `json:"remote_url"`
+		Description *string     `json:"description"`
+		Blurhash    *string     `json:"blurhash"`
+		Meta        interface{} `json:"meta"`
+	}
+
+	Mention struct {
+		ID       string `json:"id"`
+		Username string `json:"username"`
+		Acct     string `json:"acct"`
+		URL      string `json:"url"`
+	}
+
+	Tag struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	}
+
+	Poll struct {
+		ID          string        `json:"id"`
+		ExpiresAt   string        `json:"expires_at"`
+		Expired     bool          `json:"expired"`
+		Multiple    bool          `json:"multiple"`
+		VotesCount  *int64        `json:"votes_count"`
+		VotersCount *int64        `json:"voters_count"`
+		Options     []*PollOption `json:"options"`
+		Emojis      []interface{} `json:"emojis"`
+		Voted       *bool         `json:"voted,omitempty"`
+		OwnVotes    []int         `json:"own_votes,omitempty"`
+	}
+
+	PollOption struct {
+		Title      string `json:"title"`
+		VotesCount *int64 `json:"votes_count"`
+	}
+)
+
+// Converter of the objects into the entities, building URLs from the ActivityPub IDs
+type converter struct {
+	federation *activitypub.Federation
+}
+
+func (c *converter) account(a *object.Account) *Account {
+	entity := &Account{
+		ID:             strconv.FormatInt(a.ID, 10),
+		Username:       a.Username,
+		Acct:           acct(a.Username, a.Domain),
+		DisplayName:    a.Username,
+		CreatedAt:      timestamp(a.CreateAt.Time),
+		URL:            c.federation.ActorURI(a.Username),
+		FollowersCount: a.FollowersCount,
+		FollowingCount: a.FollowingCount,
+		StatusesCount:  a.StatusesCount,
+		Emojis:         []interface{}{},
+		Fields:         []interface{}{},
+	}
+	if a.URI != nil {
+		entity.URL = *a.URI
+	}
+	if a.DisplayName != nil && *a.DisplayName != "" {
+		entity.DisplayName = *a.DisplayName
+	}
+	if a.Note != nil {
+		entity.Note = noteHTML(*a.Note)
+	}
+	if a.Avatar != nil {
+		entity.Avatar = c.absolute(*a.Avatar)
+		entity.AvatarStatic = entity.Avatar
+	}
+	if a.Header != nil {
+		entity.Header = c.absolute(*a.Header)
+		entity.HeaderStatic = entity.Header
+	}
+	if a.LastStatusAt != nil {
+		/* 日付のみを返す */
+		date := a.LastStatusAt.UTC().Format("2006-01-02")
+		entity.LastStatusAt = &date
+	}
+	return entity
+}
+
+func (c *converter) credentialAccount(a *object.Account) *CredentialAccount {
+	entity := &CredentialAccount{
+		Account: c.account(a),
+		Source: Source{
+			Privacy: object.VisibilityPublic.String(),
+			Fields:  []interface{}{},
+		},
+	}
+	if a.Note != nil {
+		entity.Source.Note = *a.Note
+	}
+	return entity
+}
+
+// Convert the status filled with its account and attachments
+func (c *converter) status(s *object.Status) *Status {
+	entity := &Status{
+		ID:               strconv.FormatInt(s.ID, 10),
+		CreatedAt:        timestamp(s.CreateAt.Time),
+		Content:          s.Content,
+		Visibility:       s.Visibility.String(),
+		MediaAttachments: make([]*MediaAttachment, 0, len(s.MediaAttachment)),
+		Mentions:         make([]*Mention, 0, len(s.Mentions)),
+		Tags:             make([]*Tag, 0, len(s.Tags)),
+		Emojis:           []interface{}{},
+		Bookmarked:       s.Bookmarked,
+	}
+	if s.Account != nil {
+		entity.Account = c.account(s.Account)
+		entity.URI = c.federation.StatusURI(s.Account.Username, s.ID)
+	}
+	if s.URI != nil {
+		entity.URI = *s.URI
+	}
+	entity.URL = entity.URI
+	if s.EditedAt != nil {
+		editedAt := timestamp(s.EditedAt.Time)
+		entity.EditedAt = &editedAt
+	}
+	if s.Bookmarked != nil {
+		/* 閲覧者がいる場合のみ返す */
+		pinned := s.Pinned
+		entity.Pinned = &pinned
+	}
+
+	for _, m := range s.MediaAttachment {
+		entity.MediaAttachments = append(entity.MediaAttachments, c.mediaAttachment(m))
+	}
+	for _, m := range s.Mentions {
+		entity.Mentions = append(entity.Mentions, &Mention{
+			ID:       strconv.FormatInt(m.AccountID, 10),
+			Username: m.Username,
+			Acct:     m.Username,
+			URL:      c.federation.ActorURI(m.Username),
+		})
+	}
+	for _, t := range s.Tags {
+		entity.Tags = append(entity.Tags, &Tag{
+			Name: t.Name,
+			URL:  c.federation.BaseURL() + "/v1/timelines/tag/" + url.PathEscape(t.Name),
+		})
+	}
+	if s.Poll != nil {
+		entity.Poll = poll(s.Poll)
+	}
+	return entity
+}
+
+func (c *converter) statuses(statuses []*object.Status) []*Status {
+	entities := make([]*Status, 0, len(statuses))
+	for _, s := range statuses {
+		entities = append(entities, c.status(s))
+	}
+	return entities
+}
+
+func (c *converter) mediaAttachment(m *object.MediaAttachment) *MediaAttachment {
+	entity := &MediaAttachment{
+		ID:   strconv.FormatInt(m.ID, 10),
+		Type: "unknown",
+		URL:  c.absolute(m.URL),
+	}
+	if m.Type == object.TypeImage {
+		entity.Type = "image"
+	}
+	entity.PreviewURL = entity.URL
+	if m.Description != "" {
+		entity.Description = &m.Description
+	}
+	return entity
+}
+
+func poll(p *object.Poll) *Poll {
+	entity := &Poll{
+		ID:          strconv.FormatInt(p.ID, 10),
+		ExpiresAt:   timestamp(p.ExpireAt.Time),
+		Expired:     p.Expired,
+		Multiple:    p.Multiple,
+		VotesCount:  p.VotesCount,
+		VotersCount: p.VotersCount,
+		Options:     make([]*PollOption, 0, len(p.Options)),
+		Emojis:      []interface{}{},
+		Voted:       p.Voted,
+		OwnVotes:    p.OwnVotes,
+	}
+	for _, o := range p.Options {
+		entity.Options = append(entity.Options, &PollOption{Title: o.Title, VotesCount: o.VotesCount})
+	}
+	return entity
+}
+
+// Resolve the URL relative to this server
+func (c *converter) absolute(raw string) string {
+	base, err := url.Parse(c.federation.BaseURL())
+	if err != nil {
+		return raw
+	}
+	ref, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+	return base.ResolveReference(ref).String()
+}
+
+// Username with the domain for remote accounts
+func acct(username, domain string) string {
+	if domain == "" {
+		return username
+	}
+	return username + "@" + domain
+}
+
+func timestamp(t time.Time) string {
+	return t.UTC().Format(timestampFormat)
+}
+
+// Render the plain text biography as HTML
+func noteHTML(note string) string {
+	if note == "" {
+		return ""
+	}
+	return "<p>" + strings.ReplaceAll(html.EscapeString(note), "\n", "<br>") + "</p>"
+}
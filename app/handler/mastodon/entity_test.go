@@ -0,0 +1,210 @@
+package mastodon
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/satorunooshie/Yatter/app/activitypub"
+	"github.com/satorunooshie/Yatter/app/domain/object"
+)
+
+func TestConverter_Status(t *testing.T) {
+	c := &converter{federation: activitypub.New(nil, "http://localhost:8080")}
+
+	var (
+		displayName = "John"
+		note        = "Hello <world>\nbye"
+		avatar      = "/media/avatar.png"
+		remoteURI   = "https://remote.example/users/alice/statuses/1"
+		actorURI    = "https://remote.example/users/alice"
+		votes       = int64(3)
+		bookmarked  = true
+		createAt    = object.DateTime{Time: time.Date(2022, 4, 1, 9, 0, 0, 123e6, time.UTC)}
+	)
+	tests := []struct {
+		name   string
+		status *object.Status
+		want   string
+	}{
+		{
+			name: "local status",
+			status: &object.Status{
+				ID:         5,
+				Content:    "<p>hi #go</p>",
+				Visibility: object.VisibilityUnlisted,
+				CreateAt:   createAt,
+				Account: &object.Account{
+					ID:            1,
+					Username:      "john",
+					DisplayName:   &displayName,
+					Note:          &note,
+					Avatar:        &avatar,
+					CreateAt:      createAt,
+					StatusesCount: 1,
+					LastStatusAt:  &createAt,
+				},
+				MediaAttachment: []*object.MediaAttachment{{ID: 7, StatusID: 5, Type: object.TypeImage, URL: "/media/a.png"}},
+				Mentions:        []*object.Mention{{AccountID: 2, Username: "bob"}},
+				Tags:            []*object.Tag{{Name: "go"}},
+				Poll: &object.Poll{
+					ID:          9,
+					ExpireAt:    createAt,
+					VotesCount:  &votes,
+					VotersCount: &votes,
+					Options:     []*object.PollOption{{Title: "yes", VotesCount: &votes}},
+				},
+				Bookmarked: &bookmarked,
+				Pinned:     true,
+			},
+			want: `{
+				"id": "5",
+				"uri": "http://localhost:8080/users/john/statuses/5",
+				"url": "http://localhost:8080/users/john/statuses/5",
+				"created_at": "2022-04-01T09:00:00.123Z",
+				"edited_at": null,
+				"account": {
+					"id": "1",
+					"username": "john",
+					"acct": "john",
+					"display_name": "John",
+					"locked": false,
+					"bot": false,
+					"group": false,
+					"created_at": "2022-04-01T09:00:00.123Z",
+					"note": "<p>Hello &lt;world&gt;<br>bye</p>",
+					"url": "http://localhost:8080/users/john",
+					"avatar": "http://localhost:8080/media/avatar.png",
+					"avatar_static": "http://localhost:8080/media/avatar.png",
+					"header": "",
+					"header_static": "",
+					"followers_count": 0,
+					"following_count": 0,
+					"statuses_count": 1,
+					"last_status_at": "2022-04-01",
+					"emojis": [],
+					"fields": []
+				},
+				"content": "<p>hi #go</p>",
+				"visibility": "unlisted",
+				"sensitive": false,
+				"spoiler_text": "",
+				"in_reply_to_id": null,
+				"in_reply_to_account_id": null,
+				"reblog": null,
+				"language": null,
+				"replies_count": 0,
+				"reblogs_count": 0,
+				"favourites_count": 0,
+				"media_attachments": [{
+					"id": "7",
+					"type": "image",
+					"url": "http://localhost:8080/media/a.png",
+					"preview_url": "http://localhost:8080/media/a.png",
+					"remote_url": null,
+					"description": null,
+					"blurhash": null,
+					"meta": null
+				}],
+				"mentions": [{"id": "2", "username": "bob", "acct": "bob", "url": "http://localhost:8080/users/bob"}],
+				"tags": [{"name": "go", "url": "http://localhost:8080/v1/timelines/tag/go"}],
+				"emojis": [],
+				"card": null,
+				"poll": {
+					"id": "9",
+					"expires_at": "2022-04-01T09:00:00.123Z",
+					"expired": false,
+					"multiple": false,
+					"votes_count": 3,
+					"voters_count": 3,
+					"options": [{"title": "yes", "votes_count": 3}],
+					"emojis": []
+				},
+				"bookmarked": true,
+				"pinned": true
+			}`,
+		},
+		{
+			name: "remote status for anonymous",
+			status: &object.Status{
+				ID:       6,
+				URI:      &remoteURI,
+				Content:  "<p>hello</p>",
+				CreateAt: createAt,
+				Account: &object.Account{
+					ID:       3,
+					Username: "alice",
+					Domain:   "remote.example",
+					URI:      &actorURI,
+					CreateAt: createAt,
+				},
+			},
+			want: `{
+				"id": "6",
+				"uri": "https://remote.example/users/alice/statuses/1",
+				"url": "https://remote.example/users/alice/statuses/1",
+				"created_at": "2022-04-01T09:00:00.123Z",
+				"edited_at": null,
+				"account": {
+					"id": "3",
+					"username": "alice",
+					"acct": "alice@remote.example",
+					"display_name": "alice",
+					"locked": false,
+					"bot": false,
+					"group": false,
+					"created_at": "2022-04-01T09:00:00.123Z",
+					"note": "",
+					"url": "https://remote.example/users/alice",
+					"avatar": "",
+					"avatar_static": "",
+					"header": "",
+					"header_static": "",
+					"followers_count": 0,
+					"following_count": 0,
+					"statuses_count": 0,
+					"last_status_at": null,
+					"emojis": [],
+					"fields": []
+				},
+				"content": "<p>hello</p>",
+				"visibility": "public",
+				"sensitive": false,
+				"spoiler_text": "",
+				"in_reply_to_id": null,
+				"in_reply_to_account_id": null,
+				"reblog": null,
+				"language": null,
+				"replies_count": 0,
+				"reblogs_count": 0,
+				"favourites_count": 0,
+				"media_attachments": [],
+				"mentions": [],
+				"tags": [],
+				"emojis": [],
+				"card": null,
+				"poll": null
+			}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := json.Marshal(c.status(tt.status))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got, want interface{}
+			if err := json.Unmarshal(b, &got); err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal([]byte(tt.want), &want); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(got, want); diff != "" {
+				t.Errorf("status() returned diff (want -> got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package mastodon
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/satorunooshie/Yatter/app/config"
+	"github.com/satorunooshie/Yatter/app/handler/httperror"
+	"github.com/satorunooshie/Yatter/app/handler/statuses"
+)
+
+// Version of the Mastodon API the clients can expect
+const compatibleVersion = "3.5.0 (compatible; Yatter)"
+
+type (
+	// Instance entity of the version 1 API
+	Instance struct {
+		URI              string        `json:"uri"`
+		Title            string        `json:"title"`
+		ShortDescription string        `json:"short_description"`
+		Description      string        `json:"description"`
+		Email            string        `json:"email"`
+		Version          string        `json:"version"`
+		URLs             InstanceURLs  `json:"urls"`
+		Stats            InstanceStats `json:"stats"`
+		Thumbnail        *string       `json:"thumbnail"`
+		Languages        []string      `json:"languages"`
+		Registrations    bool          `json:"registrations"`
+		ApprovalRequired bool          `json:"approval_required"`
+		InvitesEnabled   bool          `json:"invites_enabled"`
+		Configuration    struct {
+			Polls struct {
+				MaxOptions             int   `json:"max_options"`
+				MaxCharactersPerOption int   `json:"max_characters_per_option"`
+				MinExpiration          int64 `json:"min_expiration"`
+				MaxExpiration          int64 `json:"max_expiration"`
+			} `json:"polls"`
+		} `json:"configuration"`
+		ContactAccount *Account      `json:"contact_account"`
+		Rules          []interface{} `json:"rules"`
+	}
+
+	InstanceURLs struct {
+		StreamingAPI string `json:"streaming_api"`
+	}
+
+	InstanceStats struct {
+		UserCount   int64 `json:"user_count"`
+		StatusCount int64 `json:"status_count"`
+		// Remote servers are not counted
+		DomainCount int64 `json:"domain_count"`
+	}
+)
+
+// Handle request for `GET /api/v1/instance`
+func (h *handler) GetInstance(w http.ResponseWriter, r *http.Request) {
+	now := time.Now()
+	usage, err := h.app.Dao.Account().Usage(r.Context(), now, now)
+	if err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+
+	instance := &Instance{
+		URI:           config.Federation.Domain(),
+		Title:         "Yatter",
+		Version:       compatibleVersion,
+		URLs:          InstanceURLs{StreamingAPI: strings.Replace(h.app.Federation.BaseURL(), "http", "ws", 1)},
+		Stats:         InstanceStats{UserCount: usage.Users, StatusCount: usage.LocalPosts},
+		Languages:     []string{},
+		Registrations: true,
+		Rules:         []interface{}{},
+	}
+	instance.Configuration.Polls.MaxOptions = statuses.PollMaxOptions
+	instance.Configuration.Polls.MaxCharactersPerOption = statuses.PollMaxOptionLength
+	instance.Configuration.Polls.MinExpiration = statuses.PollMinExpiresIn
+	instance.Configuration.Polls.MaxExpiration = statuses.PollMaxExpiresIn
+
+	encode(w, instance)
+}
@@ -0,0 +1,56 @@
+package mastodon
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi"
+
+	"github.com/satorunooshie/Yatter/app/app"
+	"github.com/satorunooshie/Yatter/app/domain/object"
+	"github.com/satorunooshie/Yatter/app/handler/auth"
+	"github.com/satorunooshie/Yatter/app/handler/httperror"
+	"github.com/satorunooshie/Yatter/app/handler/pagination"
+)
+
+// Implementation of handler
+type handler struct {
+	app       *app.App
+	converter *converter
+}
+
+// Create Handler for `/api/v1/`, serving the repositories with the entities of the Mastodon API
+// so that its clients can be used
+func NewRouter(app *app.App) http.Handler {
+	r := chi.NewRouter()
+
+	h := &handler{app: app, converter: &converter{federation: app.Federation}}
+	r.Get("/instance", h.GetInstance)
+	r.With(auth.Middleware(app)).Get("/accounts/verify_credentials", h.VerifyCredentials)
+	r.With(auth.OptionalMiddleware(app)).Get("/accounts/lookup", h.LookupAccount)
+	r.With(auth.OptionalMiddleware(app)).Get("/accounts/{id}", h.GetAccount)
+	r.With(auth.OptionalMiddleware(app)).Get("/accounts/{id}/statuses", h.GetAccountStatuses)
+	r.With(auth.Middleware(app)).Post("/statuses", h.CreateStatus)
+	r.With(auth.OptionalMiddleware(app)).Get("/statuses/{id}", h.GetStatus)
+	r.With(auth.OptionalMiddleware(app)).Get("/timelines/public", h.GetPublic)
+	r.With(auth.OptionalMiddleware(app)).Get("/timelines/tag/{hashtag}", h.GetTag)
+
+	return r
+}
+
+// Set Link header of the statuses, newest first
+func setLink(w http.ResponseWriter, r *http.Request, statuses []*object.Status) {
+	if len(statuses) == 0 {
+		return
+	}
+	pagination.SetLink(w, r, pagination.IDStyle, statuses[0].ID, statuses[len(statuses)-1].ID)
+}
+
+// Response with the entity as JSON
+func encode(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+}
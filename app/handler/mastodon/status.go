@@ -0,0 +1,164 @@
+package mastodon
+
+import (
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/satorunooshie/Yatter/app/domain/object"
+	"github.com/satorunooshie/Yatter/app/handler/auth"
+	"github.com/satorunooshie/Yatter/app/handler/fill"
+	"github.com/satorunooshie/Yatter/app/handler/httperror"
+	"github.com/satorunooshie/Yatter/app/handler/request"
+	"github.com/satorunooshie/Yatter/app/handler/statuses"
+	"github.com/satorunooshie/Yatter/app/handler/visibility"
+)
+
+type (
+	// Request body for `POST /api/v1/statuses`, given as JSON or as a form
+	statusCreateRequest struct {
+		Status      string       `json:"status"`
+		Visibility  string       `json:"visibility"`
+		Poll        *pollRequest `json:"poll"`
+		MediaIDs    []string     `json:"media_ids"`
+		ScheduledAt *string      `json:"scheduled_at"`
+	}
+
+	pollRequest struct {
+		Options    []string `json:"options"`
+		ExpiresIn  int64    `json:"expires_in"`
+		Multiple   bool     `json:"multiple"`
+		HideTotals bool     `json:"hide_totals"`
+	}
+)
+
+// Handle request for `POST /api/v1/statuses`
+//
+// Media attachments and scheduling are not supported, which are rejected rather than ignored.
+func (h *handler) CreateStatus(w http.ResponseWriter, r *http.Request) {
+	account := auth.AccountOf(r)
+	if account == nil {
+		httperror.Error(w, http.StatusUnauthorized)
+		return
+	}
+
+	req, err := decodeStatusCreateRequest(r)
+	if err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+	if len(req.MediaIDs) != 0 {
+		httperror.BadRequest(w, errors.New("media_ids are not supported"))
+		return
+	}
+	if req.ScheduledAt != nil && *req.ScheduledAt != "" {
+		httperror.BadRequest(w, errors.New("scheduled_at is not supported"))
+		return
+	}
+
+	params := object.StatusParams{
+		Text:       req.Status,
+		Visibility: object.VisibilityPublic,
+	}
+	if req.Visibility != "" {
+		if params.Visibility, err = object.ParseVisibility(req.Visibility); err != nil {
+			httperror.BadRequest(w, err)
+			return
+		}
+	}
+	if req.Poll != nil {
+		params.Poll = &object.PollParams{
+			Options:    req.Poll.Options,
+			ExpiresIn:  req.Poll.ExpiresIn,
+			Multiple:   req.Poll.Multiple,
+			HideTotals: req.Poll.HideTotals,
+		}
+		if err := statuses.ValidatePoll(params.Poll); err != nil {
+			httperror.BadRequest(w, err)
+			return
+		}
+	}
+	if params.Text == "" && params.Poll == nil {
+		httperror.BadRequest(w, errors.New("status can't be blank"))
+		return
+	}
+
+	status, err := statuses.Post(r.Context(), h.app, account, params)
+	if err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+
+	encode(w, h.converter.status(status))
+}
+
+func decodeStatusCreateRequest(r *http.Request) (*statusCreateRequest, error) {
+	req := &statusCreateRequest{}
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == "application/json" {
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			return nil, err
+		}
+		return req, nil
+	}
+
+	if err := r.ParseForm(); err != nil {
+		return nil, err
+	}
+	req.Status = r.PostForm.Get("status")
+	req.Visibility = r.PostForm.Get("visibility")
+	req.MediaIDs = r.PostForm["media_ids[]"]
+	if v, ok := r.PostForm["scheduled_at"]; ok {
+		req.ScheduledAt = &v[0]
+	}
+	if options, ok := r.PostForm["poll[options][]"]; ok {
+		req.Poll = &pollRequest{Options: options}
+		var err error
+		if req.Poll.ExpiresIn, err = strconv.ParseInt(r.PostForm.Get("poll[expires_in]"), 10, 64); err != nil {
+			return nil, errors.New("poll[expires_in] was not number")
+		}
+		req.Poll.Multiple, _ = strconv.ParseBool(r.PostForm.Get("poll[multiple]"))
+		req.Poll.HideTotals, _ = strconv.ParseBool(r.PostForm.Get("poll[hide_totals]"))
+	}
+	return req, nil
+}
+
+// Handle request for `GET /api/v1/statuses/{id}`
+func (h *handler) GetStatus(w http.ResponseWriter, r *http.Request) {
+	id, err := request.IDOf(r)
+	if err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+
+	ctx := r.Context()
+	viewer := auth.AccountOf(r)
+
+	status, err := h.app.Dao.Status().FindByID(ctx, id)
+	if err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+	if status == nil {
+		httperror.Error(w, http.StatusNotFound)
+		return
+	}
+
+	if err := fill.Statuses(ctx, h.app.Dao, viewer, []*object.Status{status}); err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+	visible, err := visibility.IsVisible(ctx, h.app.Dao, viewer, status)
+	if err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+	if !visible {
+		/* 存在も明かさない */
+		httperror.Error(w, http.StatusNotFound)
+		return
+	}
+
+	encode(w, h.converter.status(status))
+}
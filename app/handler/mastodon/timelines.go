@@ -0,0 +1,123 @@
+package mastodon
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/go-chi/chi"
+
+	"github.com/satorunooshie/Yatter/app/domain/content"
+	"github.com/satorunooshie/Yatter/app/domain/object"
+	"github.com/satorunooshie/Yatter/app/handler/auth"
+	"github.com/satorunooshie/Yatter/app/handler/fill"
+	"github.com/satorunooshie/Yatter/app/handler/httperror"
+	"github.com/satorunooshie/Yatter/app/handler/pagination"
+	"github.com/satorunooshie/Yatter/app/handler/request"
+)
+
+// Handle request for `GET /api/v1/timelines/public`
+//
+// local and remote are accepted but not filtered by.
+func (h *handler) GetPublic(w http.ResponseWriter, r *http.Request) {
+	page, err := pagination.Parse(r, 20, 40)
+	if err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+	onlyMedia, err := request.DecodeParam2Bool(r, "only_media")
+	if err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+
+	ctx := r.Context()
+
+	var statuses []*object.Status
+	if onlyMedia {
+		media, err := h.app.Dao.MediaAttachment().Select(ctx, page)
+		if err != nil {
+			httperror.InternalServerError(w, err)
+			return
+		}
+		/* 添付ファイルはステータスごとにまとまって並んでいる */
+		statusIDs := make([]object.StatusID, 0, len(media))
+		for _, v := range media {
+			if len(statusIDs) == 0 || statusIDs[len(statusIDs)-1] != v.StatusID {
+				statusIDs = append(statusIDs, v.StatusID)
+			}
+		}
+		if len(statusIDs) != 0 {
+			found, err := h.app.Dao.Status().FindByIDs(ctx, statusIDs)
+			if err != nil {
+				httperror.InternalServerError(w, err)
+				return
+			}
+
+			/* 添付ファイルを取得した順に並べる */
+			statusMap := make(map[object.StatusID]*object.Status, len(found))
+			for _, v := range found {
+				statusMap[v.ID] = v
+			}
+			statuses = make([]*object.Status, 0, len(statusIDs))
+			for _, id := range statusIDs {
+				if s, ok := statusMap[id]; ok {
+					statuses = append(statuses, s)
+				}
+			}
+			pagination.SetLink(w, r, pagination.IDStyle, statusIDs[0], statusIDs[len(statusIDs)-1])
+		}
+	} else {
+		if statuses, err = h.app.Dao.Status().Select(ctx, page); err != nil {
+			httperror.InternalServerError(w, err)
+			return
+		}
+		setLink(w, r, statuses)
+	}
+
+	h.respondStatuses(w, r, statuses)
+}
+
+// Handle request for `GET /api/v1/timelines/tag/{hashtag}`
+func (h *handler) GetTag(w http.ResponseWriter, r *http.Request) {
+	hashtag, err := url.PathUnescape(chi.URLParam(r, "hashtag"))
+	if err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+	hashtag = content.NormalizeTag(hashtag)
+	if !content.IsValidTag(hashtag) {
+		httperror.BadRequest(w, errors.New("invalid hashtag"))
+		return
+	}
+
+	page, err := pagination.Parse(r, 20, 40)
+	if err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+	onlyMedia, err := request.DecodeParam2Bool(r, "only_media")
+	if err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+
+	statuses, err := h.app.Dao.Status().SelectByTag(r.Context(), hashtag, onlyMedia, page)
+	if err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+
+	setLink(w, r, statuses)
+	h.respondStatuses(w, r, statuses)
+}
+
+// Response with the statuses filled for the viewer
+func (h *handler) respondStatuses(w http.ResponseWriter, r *http.Request, statuses []*object.Status) {
+	if err := fill.Statuses(r.Context(), h.app.Dao, auth.AccountOf(r), statuses); err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+
+	encode(w, h.converter.statuses(statuses))
+}
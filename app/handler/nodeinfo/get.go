@@ -0,0 +1,87 @@
+package nodeinfo
+
+import (
+	"encoding/json"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"github.com/go-chi/chi"
+
+	"github.com/satorunooshie/Yatter/app/handler/httperror"
+)
+
+const (
+	activeMonth    = 30 * 24 * time.Hour
+	activeHalfyear = 180 * 24 * time.Hour
+)
+
+type (
+	// NodeInfo document of schema 2.0 and 2.1
+	NodeInfo struct {
+		Version           string   `json:"version"`
+		Software          Software `json:"software"`
+		Protocols         []string `json:"protocols"`
+		Services          Services `json:"services"`
+		OpenRegistrations bool     `json:"openRegistrations"`
+		Usage             Usage    `json:"usage"`
+		Metadata          struct{} `json:"metadata"`
+	}
+
+	Software struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	}
+
+	Services struct {
+		Inbound  []string `json:"inbound"`
+		Outbound []string `json:"outbound"`
+	}
+
+	Usage struct {
+		Users struct {
+			Total          int64 `json:"total"`
+			ActiveMonth    int64 `json:"activeMonth"`
+			ActiveHalfyear int64 `json:"activeHalfyear"`
+		} `json:"users"`
+		LocalPosts int64 `json:"localPosts"`
+	}
+)
+
+// Handle request for `GET /nodeinfo/{version}`
+func (h *handler) Get(w http.ResponseWriter, r *http.Request) {
+	version := chi.URLParam(r, "version")
+
+	now := time.Now()
+	usage, err := h.app.Dao.Account().Usage(r.Context(), now.Add(-activeMonth), now.Add(-activeHalfyear))
+	if err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+
+	info := &NodeInfo{
+		Version:           version,
+		Software:          Software{Name: "yatter", Version: softwareVersion()},
+		Protocols:         []string{"activitypub"},
+		Services:          Services{Inbound: []string{}, Outbound: []string{"atom1.0", "rss2.0"}},
+		OpenRegistrations: true,
+	}
+	info.Usage.Users.Total = usage.Users
+	info.Usage.Users.ActiveMonth = usage.ActiveMonth
+	info.Usage.Users.ActiveHalfyear = usage.ActiveHalfyear
+	info.Usage.LocalPosts = usage.LocalPosts
+
+	w.Header().Set("Content-Type", `application/json; profile="http://nodeinfo.diaspora.software/ns/schema/`+version+`#"`)
+	if err := json.NewEncoder(w).Encode(info); err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+}
+
+// Version of the running binary, known only when built as a module
+func softwareVersion() string {
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+	return "unknown"
+}
@@ -0,0 +1,24 @@
+package nodeinfo
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi"
+
+	"github.com/satorunooshie/Yatter/app/app"
+)
+
+// Implementation of handler
+type handler struct {
+	app *app.App
+}
+
+// Create Handler for `/nodeinfo/`
+func NewRouter(app *app.App) http.Handler {
+	r := chi.NewRouter()
+
+	h := &handler{app: app}
+	r.Get("/{version:2\\.[01]}", h.Get)
+
+	return r
+}
@@ -0,0 +1,57 @@
+package notifications
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/satorunooshie/Yatter/app/handler/auth"
+	"github.com/satorunooshie/Yatter/app/handler/httperror"
+	"github.com/satorunooshie/Yatter/app/handler/request"
+)
+
+// Handle request for `POST /v1/notifications/{id}/dismiss`
+func (h *handler) Dismiss(w http.ResponseWriter, r *http.Request) {
+	account := auth.AccountOf(r)
+	if account == nil {
+		httperror.Error(w, http.StatusUnauthorized)
+		return
+	}
+
+	id, err := request.IDOf(r)
+	if err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+
+	/* 他人の通知は消えない */
+	if err := h.app.Dao.Notification().Delete(r.Context(), id, account.ID); err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(&struct{}{}); err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+}
+
+// Handle request for `POST /v1/notifications/clear`
+func (h *handler) Clear(w http.ResponseWriter, r *http.Request) {
+	account := auth.AccountOf(r)
+	if account == nil {
+		httperror.Error(w, http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.app.Dao.Notification().DeleteAll(r.Context(), account.ID); err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(&struct{}{}); err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+}
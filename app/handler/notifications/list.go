@@ -0,0 +1,138 @@
+package notifications
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/satorunooshie/Yatter/app/domain/object"
+	"github.com/satorunooshie/Yatter/app/handler/auth"
+	"github.com/satorunooshie/Yatter/app/handler/fill"
+	"github.com/satorunooshie/Yatter/app/handler/httperror"
+	"github.com/satorunooshie/Yatter/app/handler/pagination"
+)
+
+// Handle request for `GET /v1/notifications`
+func (h *handler) List(w http.ResponseWriter, r *http.Request) {
+	account := auth.AccountOf(r)
+	if account == nil {
+		httperror.Error(w, http.StatusUnauthorized)
+		return
+	}
+
+	page, err := pagination.Parse(r, 15, 30)
+	if err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+	types, err := validateTypes(r)
+	if err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+
+	ctx := r.Context()
+
+	notifications, err := h.app.Dao.Notification().Select(ctx, account.ID, types, page)
+	if err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+	if notifications == nil {
+		notifications = make([]*object.Notification, 0)
+	}
+
+	if err := h.fill(r, account, notifications); err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+
+	if len(notifications) != 0 {
+		pagination.SetLink(w, r, pagination.CursorStyle, notifications[0].ID, notifications[len(notifications)-1].ID)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(&notifications); err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+}
+
+// Fill notifications with the accounts which performed the actions and the statuses
+func (h *handler) fill(r *http.Request, viewer *object.Account, notifications []*object.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+
+	ctx := r.Context()
+
+	accountIDs := make([]object.AccountID, 0, len(notifications))
+	statusIDs := make([]object.StatusID, 0, len(notifications))
+	for _, v := range notifications {
+		accountIDs = append(accountIDs, v.FromAccountID)
+		if v.StatusID != 0 {
+			statusIDs = append(statusIDs, v.StatusID)
+		}
+	}
+
+	accounts, err := h.app.Dao.Account().FindByIDs(ctx, accountIDs)
+	if err != nil {
+		return err
+	}
+	accountMap := make(map[object.AccountID]*object.Account, len(accounts))
+	for _, v := range accounts {
+		accountMap[v.ID] = v
+	}
+
+	statusMap := make(map[object.StatusID]*object.Status, len(statusIDs))
+	if len(statusIDs) != 0 {
+		statuses, err := h.app.Dao.Status().FindByIDs(ctx, statusIDs)
+		if err != nil {
+			return err
+		}
+		if err := fill.Statuses(ctx, h.app.Dao, viewer, statuses); err != nil {
+			return err
+		}
+		for _, v := range statuses {
+			statusMap[v.ID] = v
+		}
+	}
+
+	for _, v := range notifications {
+		v.Account = accountMap[v.FromAccountID]
+		v.Status = statusMap[v.StatusID]
+	}
+	return nil
+}
+
+// Read `types[]` and `exclude_types[]`, returning the types to include
+func validateTypes(r *http.Request) ([]object.NotificationType, error) {
+	q := r.URL.Query()
+
+	included := make(map[object.NotificationType]bool)
+	for _, name := range q["types[]"] {
+		t, err := object.ParseNotificationType(name)
+		if err != nil {
+			return nil, err
+		}
+		included[t] = true
+	}
+	excluded := make(map[object.NotificationType]bool)
+	for _, name := range q["exclude_types[]"] {
+		t, err := object.ParseNotificationType(name)
+		if err != nil {
+			return nil, err
+		}
+		excluded[t] = true
+	}
+
+	types := make([]object.NotificationType, 0, len(object.NotificationTypes()))
+	for _, t := range object.NotificationTypes() {
+		if len(included) != 0 && !included[t] {
+			continue
+		}
+		if excluded[t] {
+			continue
+		}
+		types = append(types, t)
+	}
+	return types, nil
+}
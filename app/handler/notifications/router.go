@@ -0,0 +1,28 @@
+package notifications
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi"
+
+	"github.com/satorunooshie/Yatter/app/app"
+	"github.com/satorunooshie/Yatter/app/handler/auth"
+)
+
+// Implementation of handler
+type handler struct {
+	app *app.App
+}
+
+// Create Handler for `/v1/notifications/`
+func NewRouter(app *app.App) http.Handler {
+	r := chi.NewRouter()
+
+	h := &handler{app: app}
+	r.Use(auth.Middleware(app))
+	r.Get("/", h.List)
+	r.Post("/clear", h.Clear)
+	r.Post("/{id}/dismiss", h.Dismiss)
+
+	return r
+}
@@ -0,0 +1,175 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/satorunooshie/Yatter/app/domain/object"
+	"github.com/satorunooshie/Yatter/app/snowflake"
+)
+
+// Query parameter of the opaque cursor given in Link headers
+const cursorParam = "cursor"
+
+// Style of the URLs in Link headers
+type Style int
+
+const (
+	// Links with an opaque `cursor`, so that clients do not depend on the IDs
+	CursorStyle Style = iota
+	// Links with `max_id` and `min_id`, which Mastodon clients read to keep their position
+	IDStyle
+)
+
+// Read `limit`, `max_id`, `since_id`, `min_id` and `cursor` of the request.
+//
+// `since_id` takes the newest items after the ID while `min_id` takes the items right after it.
+// The IDs may be given as RFC 3339 times instead, to page from the items created at the time.
+// `cursor` is the one given in Link headers and cannot be combined with the IDs.
+func Parse(r *http.Request, defaultLimit, maxLimit int64) (object.Page, error) {
+	limit, err := Limit(r, defaultLimit, maxLimit)
+	if err != nil {
+		return object.Page{}, err
+	}
+	page := object.NewestPage(limit)
+
+	maxID, hasMaxID, err := decodeID(r, "max_id")
+	if err != nil {
+		return object.Page{}, err
+	}
+	sinceID, hasSinceID, err := decodeID(r, "since_id")
+	if err != nil {
+		return object.Page{}, err
+	}
+	minID, hasMinID, err := decodeID(r, "min_id")
+	if err != nil {
+		return object.Page{}, err
+	}
+
+	if cursor := r.URL.Query().Get(cursorParam); cursor != "" {
+		if hasMaxID || hasSinceID || hasMinID {
+			return object.Page{}, errors.New("cursor cannot be combined with max_id, since_id or min_id")
+		}
+		key, id, err := decodeCursor(cursor)
+		if err != nil {
+			return object.Page{}, err
+		}
+		switch key {
+		case "max_id":
+			maxID, hasMaxID = id, true
+		case "min_id":
+			minID, hasMinID = id, true
+		}
+	}
+
+	if hasMaxID {
+		page.MaxID = maxID
+	}
+	if hasSinceID {
+		page.MinID = sinceID
+	}
+	if hasMinID {
+		/* since_id と併用された場合は狭い方を使う */
+		if minID > page.MinID {
+			page.MinID = minID
+		}
+		page.Ascending = true
+	}
+	if page.MinID >= page.MaxID {
+		return object.Page{}, errors.New("since_id and min_id must be less than max_id")
+	}
+	return page, nil
+}
+
+// Read `limit`, defaultLimit if absent and clamped between 1 and maxLimit
+func Limit(r *http.Request, defaultLimit, maxLimit int64) (int64, error) {
+	str := r.URL.Query().Get("limit")
+	if str == "" {
+		return defaultLimit, nil
+	}
+	limit, err := strconv.ParseInt(str, 10, 64)
+	if err != nil {
+		return 0, errors.Errorf("query value (?limit=%v) was not number", str)
+	}
+	if limit < 1 {
+		return 1, nil
+	}
+	if limit > maxLimit {
+		return maxLimit, nil
+	}
+	return limit, nil
+}
+
+// Set Link header (RFC 8288) pointing to the older page after oldestID and the newer page before newestID.
+// Other query parameters of the request are kept.
+func SetLink(w http.ResponseWriter, r *http.Request, style Style, newestID, oldestID int64) {
+	page := func(key string, id int64) string {
+		u := url.URL{Path: r.URL.Path}
+		q := r.URL.Query()
+		for _, v := range []string{"max_id", "since_id", "min_id", cursorParam} {
+			q.Del(v)
+		}
+		switch style {
+		case CursorStyle:
+			q.Set(cursorParam, encodeCursor(key, id))
+		case IDStyle:
+			q.Set(key, strconv.FormatInt(id, 10))
+		}
+		u.RawQuery = q.Encode()
+		return u.String()
+	}
+	w.Header().Set("Link", strings.Join([]string{
+		fmt.Sprintf(`<%s>; rel="next"`, page("max_id", oldestID)),
+		fmt.Sprintf(`<%s>; rel="prev"`, page("min_id", newestID)),
+	}, ", "))
+}
+
+// Read the ID parameter, reporting whether it is given
+func decodeID(r *http.Request, key string) (int64, bool, error) {
+	str := r.URL.Query().Get(key)
+	if str == "" {
+		return 0, false, nil
+	}
+	id, err := strconv.ParseInt(str, 10, 64)
+	if err != nil {
+		/* ID は生成時刻の順なので、時刻はその時刻に生成された最小の ID に読み替える */
+		t, terr := time.Parse(time.RFC3339, str)
+		if terr != nil {
+			return 0, false, errors.Errorf("query value (?%s=%v) was neither number nor time", key, str)
+		}
+		return snowflake.FromTime(t), true, nil
+	}
+	if id < 0 {
+		return 0, false, errors.Errorf("query value (?%s=%v) was negative", key, str)
+	}
+	return id, true, nil
+}
+
+func encodeCursor(key string, id int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(key + ":" + strconv.FormatInt(id, 10)))
+}
+
+func decodeCursor(cursor string) (string, int64, error) {
+	invalid := errors.Errorf("query value (?%s=%v) was not valid cursor", cursorParam, cursor)
+
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", 0, invalid
+	}
+	parts := strings.SplitN(string(b), ":", 2)
+	if len(parts) != 2 || (parts[0] != "max_id" && parts[0] != "min_id") {
+		return "", 0, invalid
+	}
+	id, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || id < 0 {
+		return "", 0, invalid
+	}
+	return parts[0], id, nil
+}
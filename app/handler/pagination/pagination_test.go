@@ -0,0 +1,252 @@
+package pagination
+
+import (
+	"fmt"
+	"math"
+	"net/http/httptest"
+	"regexp"
+	"sync"
+	"testing"
+	"testing/quick"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/satorunooshie/Yatter/app/domain/object"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		target  string
+		want    object.Page
+		wantErr bool
+	}{
+		{
+			name:   "default",
+			target: "/v1/timelines/public",
+			want:   object.Page{MinID: 0, MaxID: math.MaxInt64, Limit: 20},
+		},
+		{
+			name:   "max_id and since_id",
+			target: "/v1/timelines/public?max_id=10&since_id=3&limit=5",
+			want:   object.Page{MinID: 3, MaxID: 10, Limit: 5},
+		},
+		{
+			name:   "min_id",
+			target: "/v1/timelines/public?min_id=3",
+			want:   object.Page{MinID: 3, MaxID: math.MaxInt64, Limit: 20, Ascending: true},
+		},
+		{
+			name:   "narrower of since_id and min_id",
+			target: "/v1/timelines/public?since_id=5&min_id=3",
+			want:   object.Page{MinID: 5, MaxID: math.MaxInt64, Limit: 20, Ascending: true},
+		},
+		{
+			name:   "limit clamped to max",
+			target: "/v1/timelines/public?limit=1000",
+			want:   object.Page{MinID: 0, MaxID: math.MaxInt64, Limit: 40},
+		},
+		{
+			name:   "limit clamped to 1",
+			target: "/v1/timelines/public?limit=0",
+			want:   object.Page{MinID: 0, MaxID: math.MaxInt64, Limit: 1},
+		},
+		{
+			name:   "next cursor",
+			target: "/v1/timelines/public?cursor=" + encodeCursor("max_id", 7),
+			want:   object.Page{MinID: 0, MaxID: 7, Limit: 20},
+		},
+		{
+			name:   "prev cursor",
+			target: "/v1/timelines/public?cursor=" + encodeCursor("min_id", 7),
+			want:   object.Page{MinID: 7, MaxID: math.MaxInt64, Limit: 20, Ascending: true},
+		},
+		{
+			/* エポックの 1 秒後に生成された最小の ID */
+			name:   "max_id as time",
+			target: "/v1/timelines/public?max_id=2022-01-01T00:00:01Z",
+			want:   object.Page{MinID: 0, MaxID: 1000 << 22, Limit: 20},
+		},
+		{
+			name:   "min_id as time before epoch",
+			target: "/v1/timelines/public?min_id=2021-12-31T00:00:00Z",
+			want:   object.Page{MinID: 0, MaxID: math.MaxInt64, Limit: 20, Ascending: true},
+		},
+		{
+			name:    "not number",
+			target:  "/v1/timelines/public?max_id=abc",
+			wantErr: true,
+		},
+		{
+			name:    "negative",
+			target:  "/v1/timelines/public?since_id=-1",
+			wantErr: true,
+		},
+		{
+			name:    "empty range",
+			target:  "/v1/timelines/public?since_id=10&max_id=10",
+			wantErr: true,
+		},
+		{
+			name:    "cursor with max_id",
+			target:  "/v1/timelines/public?max_id=10&cursor=" + encodeCursor("max_id", 7),
+			wantErr: true,
+		},
+		{
+			name:    "invalid cursor",
+			target:  "/v1/timelines/public?cursor=" + encodeCursor("since_id", 7),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(httptest.NewRequest("GET", tt.target, nil), 20, 40)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Errorf("Parse() returned diff (want -> got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSetLink(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		style  Style
+		want   string
+	}{
+		{
+			name:   "cursor",
+			target: "/v1/timelines/public?limit=2&cursor=" + encodeCursor("max_id", 9),
+			style:  CursorStyle,
+			want: `</v1/timelines/public?cursor=` + encodeCursor("max_id", 5) + `&limit=2>; rel="next", ` +
+				`</v1/timelines/public?cursor=` + encodeCursor("min_id", 8) + `&limit=2>; rel="prev"`,
+		},
+		{
+			name:   "id",
+			target: "/api/v1/timelines/public?local=true&since_id=1",
+			style:  IDStyle,
+			want:   `</api/v1/timelines/public?local=true&max_id=5>; rel="next", </api/v1/timelines/public?local=true&min_id=8>; rel="prev"`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			SetLink(w, httptest.NewRequest("GET", tt.target, nil), tt.style, 8, 5)
+			if diff := cmp.Diff(w.Header().Get("Link"), tt.want); diff != "" {
+				t.Errorf("SetLink() returned diff (want -> got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// Select the page of the IDs sorted in ascending order, newest first as the repositories do
+func selectPage(ids []int64, page object.Page) []int64 {
+	selected := make([]int64, 0, page.Limit)
+	if page.Ascending {
+		for _, id := range ids {
+			if id > page.MinID && id < page.MaxID && int64(len(selected)) < page.Limit {
+				selected = append([]int64{id}, selected...)
+			}
+		}
+		return selected
+	}
+	for i := len(ids) - 1; i >= 0; i-- {
+		if ids[i] > page.MinID && ids[i] < page.MaxID && int64(len(selected)) < page.Limit {
+			selected = append(selected, ids[i])
+		}
+	}
+	return selected
+}
+
+var linkPattern = regexp.MustCompile(`<([^>]+)>; rel="(next|prev)"`)
+
+func TestSetLink_walk(t *testing.T) {
+	f := func(n, inserts, limit uint8, prev bool) bool {
+		var (
+			mu  sync.Mutex
+			ids []int64
+		)
+		for i := 1; i <= int(n%100); i++ {
+			ids = append(ids, int64(i))
+		}
+		expected := append([]int64(nil), ids...)
+
+		/* 辿っている間に新しい ID を追加し続ける */
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for i := 0; i < int(inserts%50); i++ {
+				mu.Lock()
+				ids = append(ids, int64(len(ids)+1))
+				mu.Unlock()
+			}
+		}()
+
+		rel, target := "next", fmt.Sprintf("/v1/timelines/public?limit=%d", limit%10+1)
+		if prev {
+			rel, target = "prev", target+"&min_id=0"
+		}
+		walked := make([]int64, 0)
+		for {
+			r := httptest.NewRequest("GET", target, nil)
+			page, err := Parse(r, 20, 40)
+			if err != nil {
+				t.Fatal(err)
+			}
+			mu.Lock()
+			selected := selectPage(ids, page)
+			mu.Unlock()
+			if len(selected) == 0 {
+				break
+			}
+
+			/* 前のページを辿るときは古い順に並べ直す */
+			if prev {
+				for i := len(selected) - 1; i >= 0; i-- {
+					walked = append(walked, selected[i])
+				}
+			} else {
+				walked = append(walked, selected...)
+			}
+
+			w := httptest.NewRecorder()
+			SetLink(w, r, CursorStyle, selected[0], selected[len(selected)-1])
+			target = ""
+			for _, m := range linkPattern.FindAllStringSubmatch(w.Header().Get("Link"), -1) {
+				if m[2] == rel {
+					target = m[1]
+				}
+			}
+			if target == "" {
+				t.Fatalf("Link header has no %s: %s", rel, w.Header().Get("Link"))
+			}
+		}
+		<-done
+
+		found := make(map[int64]bool, len(walked))
+		for i, id := range walked {
+			if i > 0 && (prev && walked[i-1] >= id || !prev && walked[i-1] <= id) {
+				t.Logf("walked out of order or duplicated at %d: %v", i, walked)
+				return false
+			}
+			found[id] = true
+		}
+		for _, id := range expected {
+			if !found[id] {
+				t.Logf("skipped %d: walked %v", id, walked)
+				return false
+			}
+		}
+		return true
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
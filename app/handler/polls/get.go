@@ -0,0 +1,47 @@
+package polls
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/pkg/errors"
+
+	"github.com/satorunooshie/Yatter/app/domain/object"
+	"github.com/satorunooshie/Yatter/app/handler/auth"
+	"github.com/satorunooshie/Yatter/app/handler/fill"
+	"github.com/satorunooshie/Yatter/app/handler/httperror"
+	"github.com/satorunooshie/Yatter/app/handler/request"
+)
+
+// Handle request for `GET /v1/polls/{id}`
+func (h *handler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := request.IDOf(r)
+	if err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+
+	ctx := r.Context()
+	viewer := auth.AccountOf(r)
+
+	poll, err := h.find(ctx, viewer, id)
+	if err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+	if poll == nil {
+		httperror.BadRequest(w, errors.New("poll does not exist"))
+		return
+	}
+
+	if err := fill.PreparePolls(ctx, h.app.Dao, viewer, []*object.Poll{poll}); err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(poll); err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+}
@@ -0,0 +1,52 @@
+package polls
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-chi/chi"
+
+	"github.com/satorunooshie/Yatter/app/app"
+	"github.com/satorunooshie/Yatter/app/domain/object"
+	"github.com/satorunooshie/Yatter/app/handler/auth"
+	"github.com/satorunooshie/Yatter/app/handler/visibility"
+)
+
+// Implementation of handler
+type handler struct {
+	app *app.App
+}
+
+// Create Handler for `/v1/polls/`
+func NewRouter(app *app.App) http.Handler {
+	r := chi.NewRouter()
+
+	h := &handler{app: app}
+	r.With(auth.OptionalMiddleware(app)).Get("/{id}", h.Get)
+	r.With(auth.Middleware(app)).Post("/{id}/votes", h.Vote)
+
+	return r
+}
+
+// Find the poll, returning nil if it doesn't exist or its status can't be read by viewer
+func (h *handler) find(ctx context.Context, viewer *object.Account, id object.PollID) (*object.Poll, error) {
+	poll, err := h.app.Dao.Poll().FindByID(ctx, id)
+	if err != nil || poll == nil {
+		return nil, err
+	}
+
+	status, err := h.app.Dao.Status().FindByID(ctx, poll.StatusID)
+	if err != nil || status == nil {
+		return nil, err
+	}
+	status.Mentions, err = h.app.Dao.Mention().FindByStatusIDs(ctx, []object.StatusID{status.ID})
+	if err != nil {
+		return nil, err
+	}
+
+	visible, err := visibility.IsVisible(ctx, h.app.Dao, viewer, status)
+	if err != nil || !visible {
+		return nil, err
+	}
+	return poll, nil
+}
@@ -0,0 +1,114 @@
+package polls
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/satorunooshie/Yatter/app/domain/object"
+	"github.com/satorunooshie/Yatter/app/domain/repository"
+	"github.com/satorunooshie/Yatter/app/handler/auth"
+	"github.com/satorunooshie/Yatter/app/handler/fill"
+	"github.com/satorunooshie/Yatter/app/handler/httperror"
+	"github.com/satorunooshie/Yatter/app/handler/request"
+)
+
+// Request body for `POST /v1/polls/{id}/votes`
+type VoteRequest struct {
+	// Indices of the options to vote for
+	Choices []int `json:"choices"`
+}
+
+// Handle request for `POST /v1/polls/{id}/votes`
+func (h *handler) Vote(w http.ResponseWriter, r *http.Request) {
+	account := auth.AccountOf(r)
+	if account == nil {
+		httperror.Error(w, http.StatusUnauthorized)
+		return
+	}
+
+	id, err := request.IDOf(r)
+	if err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+
+	var req VoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+
+	ctx := r.Context()
+	pollRepo := h.app.Dao.Poll() // domain/repository の取得
+
+	poll, err := h.find(ctx, account, id)
+	if err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+	if poll == nil {
+		httperror.BadRequest(w, errors.New("poll does not exist"))
+		return
+	}
+
+	if err := validateVote(poll, account, req.Choices); err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+
+	if err := pollRepo.Vote(ctx, poll.ID, account.ID, req.Choices); err != nil {
+		if errors.Is(err, repository.ErrAlreadyVoted) {
+			httperror.BadRequest(w, err)
+			return
+		}
+		httperror.InternalServerError(w, err)
+		return
+	}
+
+	/* 投票を反映するために取得し直す */
+	poll, err = pollRepo.FindByID(ctx, poll.ID)
+	if err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+	if err := fill.PreparePolls(ctx, h.app.Dao, account, []*object.Poll{poll}); err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(poll); err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+}
+
+func validateVote(poll *object.Poll, account *object.Account, choices []int) error {
+	if poll.IsExpired(time.Now()) {
+		return errors.New("poll has already ended")
+	}
+	if poll.AccountID == account.ID {
+		return errors.New("cannot vote in own poll")
+	}
+	if len(choices) == 0 {
+		return errors.New("choices must not be empty")
+	}
+	if !poll.Multiple && len(choices) > 1 {
+		return errors.New("poll does not allow multiple choices")
+	}
+
+	seen := make(map[int]bool, len(choices))
+	for _, c := range choices {
+		if c < 0 || c >= len(poll.Options) {
+			return errors.Errorf("choice %d is out of range", c)
+		}
+		if seen[c] {
+			return errors.Errorf("choice %d is duplicated", c)
+		}
+		seen[c] = true
+	}
+	return nil
+}
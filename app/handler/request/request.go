@@ -1,6 +1,7 @@
 package request
 
 import (
+	"encoding/json"
 	"net/http"
 	"strconv"
 
@@ -40,3 +41,42 @@ func DecodeParam2Int64(r *http.Request, key string) (int64, error) {
 	}
 	return n, nil
 }
+
+// Read boolean query parameter such as `?only_media=true`, false if absent
+func DecodeParam2Bool(r *http.Request, key string) (bool, error) {
+	q := r.URL.Query()
+	str := q.Get(key)
+	if str == "" {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(str)
+	if err != nil {
+		return false, errors.Errorf("query value (?%s=%v) was not boolean", key, str)
+	}
+	return b, nil
+}
+
+// IDs in a request body, given as strings like they are responded or as numbers
+type IDs []int64
+
+// encoding/json/Unmarshaler
+func (ids *IDs) UnmarshalJSON(b []byte) error {
+	/* json.Number は数字だけの文字列も受け付ける */
+	var vs []json.Number
+	if err := json.Unmarshal(b, &vs); err != nil {
+		return err
+	}
+	if vs == nil {
+		return nil
+	}
+	parsed := make(IDs, 0, len(vs))
+	for _, v := range vs {
+		id, err := strconv.ParseInt(v.String(), 10, 64)
+		if err != nil {
+			return errors.Errorf("id (%s) was not integer", v)
+		}
+		parsed = append(parsed, id)
+	}
+	*ids = parsed
+	return nil
+}
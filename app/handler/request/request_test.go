@@ -0,0 +1,71 @@
+package request
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestIDs_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    IDs
+		wantErr bool
+	}{
+		{
+			name: "strings",
+			body: `{"ids":["1","9007199254740993"]}`,
+			want: IDs{1, 9007199254740993},
+		},
+		{
+			name: "numbers",
+			body: `{"ids":[1,2]}`,
+			want: IDs{1, 2},
+		},
+		{
+			name: "empty",
+			body: `{"ids":[]}`,
+			want: IDs{},
+		},
+		{
+			name: "absent",
+			body: `{}`,
+			want: nil,
+		},
+		{
+			name: "null",
+			body: `{"ids":null}`,
+			want: nil,
+		},
+		{
+			name:    "not integer",
+			body:    `{"ids":["1.5"]}`,
+			wantErr: true,
+		},
+		{
+			name:    "not number",
+			body:    `{"ids":["abc"]}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var got struct {
+				IDs IDs `json:"ids"`
+			}
+			err := json.Unmarshal([]byte(tt.body), &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("json.Unmarshal() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if diff := cmp.Diff(got.IDs, tt.want); diff != "" {
+				t.Errorf("IDs returned diff (want -> got):\n%s", diff)
+			}
+		})
+	}
+}
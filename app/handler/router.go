@@ -10,9 +10,23 @@ import (
 
 	"github.com/satorunooshie/Yatter/app/app"
 	"github.com/satorunooshie/Yatter/app/handler/accounts"
+	"github.com/satorunooshie/Yatter/app/handler/bookmarks"
+	"github.com/satorunooshie/Yatter/app/handler/feeds"
+	"github.com/satorunooshie/Yatter/app/handler/follows"
 	"github.com/satorunooshie/Yatter/app/handler/health"
+	"github.com/satorunooshie/Yatter/app/handler/mastodon"
+	"github.com/satorunooshie/Yatter/app/handler/nodeinfo"
+	"github.com/satorunooshie/Yatter/app/handler/notifications"
+	"github.com/satorunooshie/Yatter/app/handler/polls"
+	"github.com/satorunooshie/Yatter/app/handler/scheduled"
+	"github.com/satorunooshie/Yatter/app/handler/search"
 	"github.com/satorunooshie/Yatter/app/handler/statuses"
+	"github.com/satorunooshie/Yatter/app/handler/streaming"
 	"github.com/satorunooshie/Yatter/app/handler/timelines"
+	"github.com/satorunooshie/Yatter/app/handler/trends"
+	"github.com/satorunooshie/Yatter/app/handler/users"
+	"github.com/satorunooshie/Yatter/app/handler/webhooks"
+	"github.com/satorunooshie/Yatter/app/handler/wellknown"
 )
 
 func NewRouter(app *app.App) http.Handler {
@@ -25,18 +39,44 @@ func NewRouter(app *app.App) http.Handler {
 	r.Use(middleware.Recoverer)
 	r.Use(newCORS().Handler)
 
-	// Set a timeout value on the request context (ctx), that will signal
-	// through ctx.Done() that the request has timed out and further
-	// processing should be stopped.
-	r.Use(middleware.Timeout(60 * time.Second))
+	/* connections last long, so no timeout */
+	r.Mount("/v1/streaming", streaming.NewRouter(app))
 
-	r.Mount("/v1/health", health.NewRouter())
+	r.Group(func(r chi.Router) {
+		// Set a timeout value on the request context (ctx), that will signal
+		// through ctx.Done() that the request has timed out and further
+		// processing should be stopped.
+		r.Use(middleware.Timeout(60 * time.Second))
 
-	r.Mount("/v1/accounts", accounts.NewRouter(app))
+		r.Mount("/v1/health", health.NewRouter())
 
-	/* including auth */
-	r.Mount("/v1/statuses", statuses.NewRouter(app))
-	r.Mount("/v1/timelines", timelines.NewRouter(app))
+		r.Mount("/v1/accounts", accounts.NewRouter(app))
+
+		/* including auth */
+		r.Mount("/v1/statuses", statuses.NewRouter(app))
+		r.Mount("/v1/timelines", timelines.NewRouter(app))
+		r.Mount("/v1/trends", trends.NewRouter(app))
+		r.Mount("/v1/polls", polls.NewRouter(app))
+		r.Mount("/v1/scheduled_statuses", scheduled.NewRouter(app))
+		r.Mount("/v1/bookmarks", bookmarks.NewRouter(app))
+		r.Mount("/v1/notifications", notifications.NewRouter(app))
+		r.Mount("/v1/follows", follows.NewRouter(app))
+		r.Mount("/v2/search", search.NewRouter(app))
+
+		/* admin only */
+		r.Mount("/v1/admin/webhooks", webhooks.NewRouter(app))
+
+		/* compatible with the Mastodon API */
+		r.Mount("/api/v1", mastodon.NewRouter(app))
+
+		/* ActivityPub */
+		r.Mount("/users", users.NewRouter(app))
+		r.Mount("/.well-known", wellknown.NewRouter(app))
+		r.Mount("/nodeinfo", nodeinfo.NewRouter(app))
+
+		/* RSS and Atom feeds such as /@{username}.rss */
+		r.Mount("/", feeds.NewRouter(app))
+	})
 
 	return r
 }
@@ -0,0 +1,54 @@
+package scheduled
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/satorunooshie/Yatter/app/domain/repository"
+	"github.com/satorunooshie/Yatter/app/handler/auth"
+	"github.com/satorunooshie/Yatter/app/handler/httperror"
+	"github.com/satorunooshie/Yatter/app/handler/request"
+)
+
+// Handle request for `DELETE /v1/scheduled_statuses/{id}`
+func (h *handler) Delete(w http.ResponseWriter, r *http.Request) {
+	account := auth.AccountOf(r)
+	if account == nil {
+		httperror.Error(w, http.StatusUnauthorized)
+		return
+	}
+
+	id, err := request.IDOf(r)
+	if err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+
+	ctx := r.Context()
+
+	scheduled, err := h.find(ctx, account, id)
+	if err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+	if scheduled == nil {
+		httperror.BadRequest(w, errors.New("scheduled status does not exist"))
+		return
+	}
+
+	if err := h.app.Dao.ScheduledStatus().Delete(ctx, id, account.ID); err != nil {
+		if errors.Is(err, repository.ErrScheduledStatusNotFound) {
+			httperror.BadRequest(w, err)
+			return
+		}
+		httperror.InternalServerError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(&struct{}{}); err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+}
@@ -0,0 +1,56 @@
+package scheduled
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/satorunooshie/Yatter/app/domain/object"
+	"github.com/satorunooshie/Yatter/app/handler/auth"
+	"github.com/satorunooshie/Yatter/app/handler/httperror"
+	"github.com/satorunooshie/Yatter/app/handler/request"
+)
+
+// Handle request for `GET /v1/scheduled_statuses/{id}`
+func (h *handler) Get(w http.ResponseWriter, r *http.Request) {
+	account := auth.AccountOf(r)
+	if account == nil {
+		httperror.Error(w, http.StatusUnauthorized)
+		return
+	}
+
+	id, err := request.IDOf(r)
+	if err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+
+	scheduled, err := h.find(r.Context(), account, id)
+	if err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+	if scheduled == nil {
+		httperror.BadRequest(w, errors.New("scheduled status does not exist"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(scheduled); err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+}
+
+// Find the scheduled status, returning nil if it doesn't exist or belongs to another account
+func (h *handler) find(ctx context.Context, account *object.Account, id object.ScheduledStatusID) (*object.ScheduledStatus, error) {
+	scheduled, err := h.app.Dao.ScheduledStatus().FindByID(ctx, id)
+	if err != nil || scheduled == nil {
+		return nil, err
+	}
+	if scheduled.AccountID != account.ID {
+		return nil, nil
+	}
+	return scheduled, nil
+}
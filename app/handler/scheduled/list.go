@@ -0,0 +1,37 @@
+package scheduled
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/satorunooshie/Yatter/app/handler/auth"
+	"github.com/satorunooshie/Yatter/app/handler/httperror"
+	"github.com/satorunooshie/Yatter/app/handler/pagination"
+)
+
+// Handle request for `GET /v1/scheduled_statuses`
+func (h *handler) List(w http.ResponseWriter, r *http.Request) {
+	account := auth.AccountOf(r)
+	if account == nil {
+		httperror.Error(w, http.StatusUnauthorized)
+		return
+	}
+
+	limit, err := pagination.Limit(r, 20, 40)
+	if err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+
+	scheduled, err := h.app.Dao.ScheduledStatus().SelectByAccountID(r.Context(), account.ID, limit)
+	if err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(scheduled); err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+}
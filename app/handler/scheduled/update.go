@@ -0,0 +1,84 @@
+package scheduled
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/satorunooshie/Yatter/app/domain/object"
+	"github.com/satorunooshie/Yatter/app/domain/repository"
+	"github.com/satorunooshie/Yatter/app/handler/auth"
+	"github.com/satorunooshie/Yatter/app/handler/httperror"
+	"github.com/satorunooshie/Yatter/app/handler/request"
+	"github.com/satorunooshie/Yatter/app/publish"
+)
+
+// Request body for `PUT /v1/scheduled_statuses/{id}`
+type UpdateRequest struct {
+	ScheduledAt object.DateTime `json:"scheduled_at"`
+}
+
+// Handle request for `PUT /v1/scheduled_statuses/{id}`
+func (h *handler) Update(w http.ResponseWriter, r *http.Request) {
+	account := auth.AccountOf(r)
+	if account == nil {
+		httperror.Error(w, http.StatusUnauthorized)
+		return
+	}
+
+	id, err := request.IDOf(r)
+	if err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+
+	var req UpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+	if err := publish.CheckSchedule(req.ScheduledAt.Time, time.Now()); err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+
+	ctx := r.Context()
+	scheduledRepo := h.app.Dao.ScheduledStatus() // domain/repository の取得
+
+	scheduled, err := h.find(ctx, account, id)
+	if err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+	if scheduled == nil {
+		httperror.BadRequest(w, errors.New("scheduled status does not exist"))
+		return
+	}
+
+	if err := scheduledRepo.Update(ctx, id, account.ID, req.ScheduledAt.Time); err != nil {
+		if errors.Is(err, repository.ErrScheduledStatusNotFound) {
+			httperror.BadRequest(w, err)
+			return
+		}
+		httperror.InternalServerError(w, err)
+		return
+	}
+
+	/* 更新までの間に公開されていれば存在しない */
+	scheduled, err = scheduledRepo.FindByID(ctx, id)
+	if err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+	if scheduled == nil {
+		httperror.BadRequest(w, errors.New("scheduled status does not exist"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(scheduled); err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+}
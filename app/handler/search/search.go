@@ -0,0 +1,112 @@
+package search
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/satorunooshie/Yatter/app/domain/object"
+	"github.com/satorunooshie/Yatter/app/handler/auth"
+	"github.com/satorunooshie/Yatter/app/handler/fill"
+	"github.com/satorunooshie/Yatter/app/handler/httperror"
+	"github.com/satorunooshie/Yatter/app/handler/pagination"
+)
+
+const (
+	typeAccounts = "accounts"
+	typeStatuses = "statuses"
+	typeHashtags = "hashtags"
+)
+
+type results struct {
+	Accounts []*object.Account `json:"accounts"`
+	Statuses []*object.Status  `json:"statuses"`
+	Hashtags []*object.Tag     `json:"hashtags"`
+}
+
+// Handle request for `GET /v2/search`
+//
+// Each type is paged by its own IDs, so the Link header is given only when `type` is.
+func (h *handler) Search(w http.ResponseWriter, r *http.Request) {
+	query := object.ParseSearchQuery(r.URL.Query().Get("q"))
+	if query.IsEmpty() {
+		httperror.BadRequest(w, errors.New("q is required"))
+		return
+	}
+	typ := r.URL.Query().Get("type")
+	switch typ {
+	case "", typeAccounts, typeStatuses, typeHashtags:
+	default:
+		httperror.BadRequest(w, fmt.Errorf("unknown type: %q", typ))
+		return
+	}
+
+	page, err := pagination.Parse(r, 20, 40)
+	if err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+
+	ctx := r.Context()
+	viewer := auth.AccountOf(r)
+	var viewerID object.AccountID
+	if viewer != nil {
+		viewerID = viewer.ID
+	}
+
+	res := &results{
+		Accounts: make([]*object.Account, 0),
+		Statuses: make([]*object.Status, 0),
+		Hashtags: make([]*object.Tag, 0),
+	}
+	ids := make([]int64, 0, page.Limit)
+
+	searchRepo := h.app.Dao.Search()
+	if typ == "" || typ == typeAccounts {
+		accounts, err := searchRepo.Accounts(ctx, query, page)
+		if err != nil {
+			httperror.InternalServerError(w, err)
+			return
+		}
+		for _, v := range accounts {
+			res.Accounts = append(res.Accounts, v)
+			ids = append(ids, v.ID)
+		}
+	}
+	if typ == "" || typ == typeStatuses {
+		statuses, err := searchRepo.Statuses(ctx, query, viewerID, page)
+		if err != nil {
+			httperror.InternalServerError(w, err)
+			return
+		}
+		if err := fill.Statuses(ctx, h.app.Dao, viewer, statuses); err != nil {
+			httperror.InternalServerError(w, err)
+			return
+		}
+		for _, v := range statuses {
+			res.Statuses = append(res.Statuses, v)
+			ids = append(ids, v.ID)
+		}
+	}
+	if typ == "" || typ == typeHashtags {
+		tags, err := searchRepo.Hashtags(ctx, query, page)
+		if err != nil {
+			httperror.InternalServerError(w, err)
+			return
+		}
+		for _, v := range tags {
+			res.Hashtags = append(res.Hashtags, v)
+			ids = append(ids, v.ID)
+		}
+	}
+
+	if typ != "" && len(ids) != 0 {
+		pagination.SetLink(w, r, pagination.CursorStyle, ids[0], ids[len(ids)-1])
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+}
@@ -0,0 +1,274 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/satorunooshie/Yatter/app/app"
+	"github.com/satorunooshie/Yatter/app/dao"
+	"github.com/satorunooshie/Yatter/app/domain/object"
+	"github.com/satorunooshie/Yatter/app/domain/repository"
+	memsearch "github.com/satorunooshie/Yatter/app/search"
+)
+
+// Dao searching on Memory, with nothing to fill the statuses with but their accounts
+type fakeDao struct {
+	dao.Dao
+	accounts []*object.Account
+	search   *memsearch.Memory
+}
+
+func (d *fakeDao) Search() repository.Search                   { return d.search }
+func (d *fakeDao) Account() repository.Account                 { return fakeAccount{accounts: d.accounts} }
+func (d *fakeDao) MediaAttachment() repository.MediaAttachment { return fakeMediaAttachment{} }
+func (d *fakeDao) Mention() repository.Mention                 { return fakeMention{} }
+func (d *fakeDao) Tag() repository.Tag                         { return fakeTag{} }
+func (d *fakeDao) Poll() repository.Poll                       { return fakePoll{} }
+func (d *fakeDao) PinnedStatus() repository.PinnedStatus       { return fakePinnedStatus{} }
+func (d *fakeDao) Bookmark() repository.Bookmark               { return fakeBookmark{} }
+
+type fakeAccount struct {
+	repository.Account
+	accounts []*object.Account
+}
+
+func (r fakeAccount) FindByUsername(_ context.Context, username string) (*object.Account, error) {
+	for _, v := range r.accounts {
+		if v.Username == username && v.IsLocal() {
+			return v, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r fakeAccount) FindByIDs(_ context.Context, ids []object.AccountID) ([]*object.Account, error) {
+	found := make([]*object.Account, 0, len(ids))
+	for _, v := range r.accounts {
+		for _, id := range ids {
+			if v.ID == id {
+				found = append(found, v)
+				break
+			}
+		}
+	}
+	return found, nil
+}
+
+type fakeMediaAttachment struct{ repository.MediaAttachment }
+
+func (fakeMediaAttachment) FindByStatusIDs(context.Context, []object.StatusID) ([]*object.MediaAttachment, error) {
+	return nil, nil
+}
+
+type fakeMention struct{ repository.Mention }
+
+func (fakeMention) FindByStatusIDs(context.Context, []object.StatusID) ([]*object.Mention, error) {
+	return nil, nil
+}
+
+type fakeTag struct{ repository.Tag }
+
+func (fakeTag) FindByStatusIDs(context.Context, []object.StatusID) ([]*object.Tag, error) {
+	return nil, nil
+}
+
+type fakePoll struct{ repository.Poll }
+
+func (fakePoll) FindByStatusIDs(context.Context, []object.StatusID) ([]*object.Poll, error) {
+	return nil, nil
+}
+
+type fakePinnedStatus struct{ repository.PinnedStatus }
+
+func (fakePinnedStatus) FindPinned(context.Context, []object.StatusID) ([]object.StatusID, error) {
+	return nil, nil
+}
+
+type fakeBookmark struct{ repository.Bookmark }
+
+func (fakeBookmark) FindBookmarked(context.Context, object.AccountID, []object.StatusID) ([]object.StatusID, error) {
+	return nil, nil
+}
+
+// Server of alice (1) following bob (2), who knows carol (3) on a remote server
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	accounts := []*object.Account{
+		{ID: 1, Username: "alice"},
+		{ID: 2, Username: "bob"},
+		{ID: 3, Username: "carol", Domain: "remote.example"},
+	}
+	m := memsearch.NewMemory()
+	for _, v := range accounts {
+		m.AddAccount(v)
+	}
+	m.Follow(1, 2)
+
+	m.AddStatus(&object.Status{ID: 10, AccountID: 2, Text: "Sushi for lunch", Visibility: object.VisibilityPublic})
+	m.AddStatus(&object.Status{ID: 11, AccountID: 2, Text: "sushi for followers", Visibility: object.VisibilityPrivate})
+	m.AddStatus(&object.Status{ID: 12, AccountID: 2, Text: "@alice secret sushi", Visibility: object.VisibilityDirect,
+		Mentions: []*object.Mention{{AccountID: 1}}})
+	m.AddStatus(&object.Status{ID: 13, AccountID: 3, Text: "remote sushi", Visibility: object.VisibilityPublic})
+	m.AddStatus(&object.Status{ID: 14, AccountID: 2, Text: "SUSHI photo", Visibility: object.VisibilityPublic,
+		MediaAttachment: []*object.MediaAttachment{{ID: 1, StatusID: 14}}})
+	m.AddTag(&object.Tag{ID: 1, Name: "sushi"})
+
+	server := httptest.NewServer(NewRouter(&app.App{Dao: &fakeDao{accounts: accounts, search: m}}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+type response struct {
+	Accounts []string
+	Statuses []string
+	Hashtags []string
+	// Path and query of the older page
+	Next string
+}
+
+var nextLink = regexp.MustCompile(`<([^>]*)>; rel="next"`)
+
+func get(t *testing.T, server *httptest.Server, target, username string) (int, *response) {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+target, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if username != "" {
+		req.Header.Set("Authentication", "username "+username)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return resp.StatusCode, nil
+	}
+
+	var body struct {
+		Accounts []struct {
+			Username string `json:"username"`
+		} `json:"accounts"`
+		Statuses []struct {
+			ID string `json:"id"`
+		} `json:"statuses"`
+		Hashtags []struct {
+			Name string `json:"name"`
+		} `json:"hashtags"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	res := &response{Accounts: []string{}, Statuses: []string{}, Hashtags: []string{}}
+	for _, v := range body.Accounts {
+		res.Accounts = append(res.Accounts, v.Username)
+	}
+	for _, v := range body.Statuses {
+		res.Statuses = append(res.Statuses, v.ID)
+	}
+	for _, v := range body.Hashtags {
+		res.Hashtags = append(res.Hashtags, v.Name)
+	}
+	if m := nextLink.FindStringSubmatch(resp.Header.Get("Link")); m != nil {
+		res.Next = m[1]
+	}
+	return resp.StatusCode, res
+}
+
+func TestSearch(t *testing.T) {
+	server := newTestServer(t)
+
+	tests := []struct {
+		name       string
+		target     string
+		username   string
+		wantStatus int
+		want       *response
+	}{
+		{
+			name:       "no q",
+			target:     "/?type=statuses",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "unknown type",
+			target:     "/?q=sushi&type=media",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "every type without Link",
+			target:     "/?q=sushi",
+			wantStatus: http.StatusOK,
+			want:       &response{Accounts: []string{}, Statuses: []string{"14", "13", "10"}, Hashtags: []string{"sushi"}},
+		},
+		{
+			name:       "accounts case insensitive",
+			target:     "/?q=BOB&type=accounts",
+			wantStatus: http.StatusOK,
+			want:       &response{Accounts: []string{"bob"}, Statuses: []string{}, Hashtags: []string{}, Next: "/?cursor=bWF4X2lkOjI&q=BOB&type=accounts"},
+		},
+		{
+			name:       "statuses visible to follower and mentioned",
+			target:     "/?q=sushi&type=statuses",
+			username:   "alice",
+			wantStatus: http.StatusOK,
+			want:       &response{Accounts: []string{}, Statuses: []string{"14", "13", "12", "11", "10"}, Hashtags: []string{}, Next: "/?cursor=bWF4X2lkOjEw&q=sushi&type=statuses"},
+		},
+		{
+			name:       "from remote account",
+			target:     "/?q=sushi+from:carol@remote.example&type=statuses",
+			wantStatus: http.StatusOK,
+			want:       &response{Accounts: []string{}, Statuses: []string{"13"}, Hashtags: []string{}, Next: "/?cursor=bWF4X2lkOjEz&q=sushi+from%3Acarol%40remote.example&type=statuses"},
+		},
+		{
+			name:       "has media",
+			target:     "/?q=has:media&type=statuses",
+			wantStatus: http.StatusOK,
+			want:       &response{Accounts: []string{}, Statuses: []string{"14"}, Hashtags: []string{}, Next: "/?cursor=bWF4X2lkOjE0&q=has%3Amedia&type=statuses"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			status, got := get(t, server, tt.target, tt.username)
+			if status != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", status, tt.wantStatus)
+			}
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Errorf("Search() returned diff (want -> got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSearch_Link(t *testing.T) {
+	server := newTestServer(t)
+
+	/* Link header をたどって古いページを取得する */
+	pages := make([][]string, 0)
+	target := "/?q=sushi&type=statuses&limit=2"
+	for target != "" {
+		status, got := get(t, server, target, "alice")
+		if status != http.StatusOK {
+			t.Fatalf("GET %s responded %d", target, status)
+		}
+		if len(got.Statuses) == 0 {
+			break
+		}
+		pages = append(pages, got.Statuses)
+		target = got.Next
+	}
+	want := [][]string{{"14", "13"}, {"12", "11"}, {"10"}}
+	if diff := cmp.Diff(pages, want); diff != "" {
+		t.Errorf("pages returned diff (want -> got):\n%s", diff)
+	}
+}
@@ -0,0 +1,82 @@
+package statuses
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/satorunooshie/Yatter/app/domain/object"
+	"github.com/satorunooshie/Yatter/app/handler/auth"
+	"github.com/satorunooshie/Yatter/app/handler/fill"
+	"github.com/satorunooshie/Yatter/app/handler/httperror"
+	"github.com/satorunooshie/Yatter/app/handler/request"
+	"github.com/satorunooshie/Yatter/app/handler/visibility"
+)
+
+// Handle request for `POST /v1/statuses/{id}/bookmark`
+func (h *handler) Bookmark(w http.ResponseWriter, r *http.Request) {
+	h.setBookmark(w, r, true)
+}
+
+// Handle request for `POST /v1/statuses/{id}/unbookmark`
+func (h *handler) Unbookmark(w http.ResponseWriter, r *http.Request) {
+	h.setBookmark(w, r, false)
+}
+
+func (h *handler) setBookmark(w http.ResponseWriter, r *http.Request, bookmarked bool) {
+	account := auth.AccountOf(r)
+	if account == nil {
+		httperror.Error(w, http.StatusUnauthorized)
+		return
+	}
+
+	id, err := request.IDOf(r)
+	if err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+
+	ctx := r.Context()
+	bookmarkRepo := h.app.Dao.Bookmark() // domain/repository の取得
+
+	status, err := h.app.Dao.Status().FindByID(ctx, id)
+	if err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+	if status == nil {
+		httperror.BadRequest(w, errors.New("status does not exist"))
+		return
+	}
+	if err := fill.Statuses(ctx, h.app.Dao, account, []*object.Status{status}); err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+
+	visible, err := visibility.IsVisible(ctx, h.app.Dao, account, status)
+	if err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+	if !visible {
+		httperror.BadRequest(w, errors.New("status does not exist"))
+		return
+	}
+
+	if bookmarked {
+		err = bookmarkRepo.Insert(ctx, account.ID, id)
+	} else {
+		err = bookmarkRepo.Delete(ctx, account.ID, id)
+	}
+	if err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+	status.Bookmarked = &bookmarked
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(status); err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+}
@@ -1,18 +1,36 @@
 package statuses
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
+	"time"
 
+	"github.com/satorunooshie/Yatter/app/app"
 	"github.com/satorunooshie/Yatter/app/domain/object"
 	"github.com/satorunooshie/Yatter/app/handler/auth"
+	"github.com/satorunooshie/Yatter/app/handler/fill"
 	"github.com/satorunooshie/Yatter/app/handler/httperror"
+	"github.com/satorunooshie/Yatter/app/handler/request"
+	"github.com/satorunooshie/Yatter/app/publish"
+)
+
+const (
+	PollMaxOptions      = 4
+	PollMaxOptionLength = 50
+	PollMinExpiresIn    = 5 * 60
+	PollMaxExpiresIn    = 31 * 24 * 60 * 60
 )
 
 // Request body for `POST /v1/statuses`
 type StatusCreateRequest struct {
-	Status   string  `json:"status"`
-	MediaIDs []int64 `json:"media_ids"`
+	Status     string             `json:"status"`
+	MediaIDs   request.IDs        `json:"media_ids"`
+	Visibility *object.Visibility `json:"visibility"`
+	Poll       *object.PollParams `json:"poll"`
+	// Publish the status at the time instead of now
+	ScheduledAt *object.DateTime `json:"scheduled_at"`
 }
 
 // Handle request for `POST /v1/statuses`
@@ -28,32 +46,101 @@ func (h *handler) Create(w http.ResponseWriter, r *http.Request) {
 		httperror.BadRequest(w, err)
 		return
 	}
+	if req.Poll != nil {
+		if err := ValidatePoll(req.Poll); err != nil {
+			httperror.BadRequest(w, err)
+			return
+		}
+	}
+
+	params := object.StatusParams{
+		Text:       req.Status,
+		Visibility: object.VisibilityPublic,
+		Poll:       req.Poll,
+	}
+	if req.Visibility != nil {
+		params.Visibility = *req.Visibility
+	}
 
 	ctx := r.Context()
-	statusRepo := h.app.Dao.Status() // domain/repository の取得
 
-	id, err := statusRepo.Insert(ctx, account.ID, req.Status)
+	if req.ScheduledAt != nil {
+		h.schedule(w, r, account, req.ScheduledAt.Time, params)
+		return
+	}
+
+	status, err := Post(ctx, h.app, account, params)
 	if err != nil {
 		httperror.InternalServerError(w, err)
 		return
 	}
 
-	status, err := statusRepo.FindByID(ctx, id)
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(status); err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+}
+
+// Publish the status now, telling streaming clients, webhooks and remote followers about it.
+// The returned status is filled for account as the viewer.
+func Post(ctx context.Context, app *app.App, account *object.Account, params object.StatusParams) (*object.Status, error) {
+	id, err := app.Publisher.Post(ctx, account, params)
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := app.Dao.Status().FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := fill.Statuses(ctx, app.Dao, account, []*object.Status{status}); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// Store the status to publish later, responding with the scheduled status
+func (h *handler) schedule(w http.ResponseWriter, r *http.Request, account *object.Account, scheduledAt time.Time, params object.StatusParams) {
+	if err := publish.CheckSchedule(scheduledAt, time.Now()); err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+
+	ctx := r.Context()
+	scheduledRepo := h.app.Dao.ScheduledStatus() // domain/repository の取得
+
+	id, err := scheduledRepo.Insert(ctx, account.ID, scheduledAt, params)
 	if err != nil {
 		httperror.InternalServerError(w, err)
 		return
 	}
 
-	res := &object.Status{
-		ID:       id,
-		Account:  account,
-		Content:  status.Content,
-		CreateAt: status.CreateAt,
+	scheduled, err := scheduledRepo.FindByID(ctx, id)
+	if err != nil {
+		httperror.InternalServerError(w, err)
+		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(res); err != nil {
+	if err := json.NewEncoder(w).Encode(scheduled); err != nil {
 		httperror.InternalServerError(w, err)
 		return
 	}
 }
+
+// Check the options and the duration of a new poll
+func ValidatePoll(p *object.PollParams) error {
+	if len(p.Options) < 2 || len(p.Options) > PollMaxOptions {
+		return errors.New("poll must have 2 to 4 options")
+	}
+	for _, o := range p.Options {
+		if o == "" || len([]rune(o)) > PollMaxOptionLength {
+			return errors.New("poll option must be 1 to 50 characters")
+		}
+	}
+	if p.ExpiresIn < PollMinExpiresIn || p.ExpiresIn > PollMaxExpiresIn {
+		return errors.New("poll must expire in 5 minutes to 31 days")
+	}
+	return nil
+}
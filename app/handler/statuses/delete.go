@@ -3,8 +3,10 @@ package statuses
 import (
 	"encoding/json"
 	"errors"
+	"log"
 	"net/http"
 
+	"github.com/satorunooshie/Yatter/app/domain/object"
 	"github.com/satorunooshie/Yatter/app/handler/auth"
 	"github.com/satorunooshie/Yatter/app/handler/httperror"
 	"github.com/satorunooshie/Yatter/app/handler/request"
@@ -40,10 +42,27 @@ func (h *handler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	tags, err := h.app.Dao.Tag().FindByStatusIDs(ctx, []object.StatusID{id})
+	if err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+	status.Tags = tags
+	status.Mentions, err = h.app.Dao.Mention().FindByStatusIDs(ctx, []object.StatusID{id})
+	if err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+
 	if err := statusRepo.Delete(ctx, id, account.ID); err != nil {
 		httperror.InternalServerError(w, err)
 		return
 	}
+	h.streamDeleted(ctx, status)
+	/* 削除済みなので配送の失敗はログに残すだけにする */
+	if err := h.app.Federation.PublishDelete(ctx, account, status); err != nil {
+		log.Printf("[WARN] statuses::Delete::PublishDelete(%d): %v", id, err)
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 
@@ -6,8 +6,12 @@ import (
 
 	"github.com/pkg/errors"
 
+	"github.com/satorunooshie/Yatter/app/domain/object"
+	"github.com/satorunooshie/Yatter/app/handler/auth"
+	"github.com/satorunooshie/Yatter/app/handler/fill"
 	"github.com/satorunooshie/Yatter/app/handler/httperror"
 	"github.com/satorunooshie/Yatter/app/handler/request"
+	"github.com/satorunooshie/Yatter/app/handler/visibility"
 )
 
 // Handle request for `GET /v1/statuses/{id}`
@@ -31,18 +35,24 @@ func (h *handler) Get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	accountRepo := h.app.Dao.Account()
-	account, err := accountRepo.FindByID(ctx, status.AccountID)
-	if err != nil {
+	if err := fill.Statuses(ctx, h.app.Dao, auth.AccountOf(r), []*object.Status{status}); err != nil {
 		httperror.InternalServerError(w, err)
 		return
 	}
-	if account == nil {
+	if status.Account == nil {
 		httperror.InternalServerError(w, errors.Errorf("account that has this status (%v) not found", status))
 		return
 	}
 
-	status.Account = account
+	visible, err := visibility.IsVisible(ctx, h.app.Dao, auth.AccountOf(r), status)
+	if err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+	if !visible {
+		httperror.BadRequest(w, errors.New("status does not exist"))
+		return
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(status); err != nil {
@@ -0,0 +1,150 @@
+package statuses
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/satorunooshie/Yatter/app/domain/content"
+	"github.com/satorunooshie/Yatter/app/domain/object"
+	"github.com/satorunooshie/Yatter/app/handler/auth"
+	"github.com/satorunooshie/Yatter/app/handler/fill"
+	"github.com/satorunooshie/Yatter/app/handler/httperror"
+	"github.com/satorunooshie/Yatter/app/handler/request"
+	"github.com/satorunooshie/Yatter/app/handler/visibility"
+)
+
+// Handle request for `GET /v1/statuses/{id}/history`
+func (h *handler) History(w http.ResponseWriter, r *http.Request) {
+	id, err := request.IDOf(r)
+	if err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+
+	ctx := r.Context()
+	statusRepo := h.app.Dao.Status() // domain/repository の取得
+
+	status, err := statusRepo.FindByID(ctx, id)
+	if err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+	if status == nil {
+		httperror.BadRequest(w, errors.New("status does not exist"))
+		return
+	}
+	if err := fill.Statuses(ctx, h.app.Dao, auth.AccountOf(r), []*object.Status{status}); err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+
+	visible, err := visibility.IsVisible(ctx, h.app.Dao, auth.AccountOf(r), status)
+	if err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+	if !visible {
+		httperror.BadRequest(w, errors.New("status does not exist"))
+		return
+	}
+
+	edits, err := h.app.Dao.StatusEdit().FindByStatusID(ctx, id)
+	if err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+
+	/* 過去の版のMediaAttachmentを取得する */
+	var mediaIDs []object.MediaAttachmentID
+	for _, v := range edits {
+		mediaIDs = append(mediaIDs, v.MediaAttachmentIDs...)
+	}
+	mediaAttachmentMap := make(map[object.MediaAttachmentID]*object.MediaAttachment, len(mediaIDs))
+	if len(mediaIDs) != 0 {
+		media, err := h.app.Dao.MediaAttachment().FindByIDs(ctx, mediaIDs)
+		if err != nil {
+			httperror.InternalServerError(w, err)
+			return
+		}
+		for _, v := range media {
+			mediaAttachmentMap[v.ID] = v
+		}
+	}
+
+	/* 過去の版のメンションとタグは保存していないので本文から解決する */
+	accounts := make(map[string]*object.Account)
+	history := make([]*object.StatusEdit, 0, len(edits)+1)
+	for _, v := range edits {
+		mentions, err := h.mentionsIn(ctx, v.Text, accounts)
+		if err != nil {
+			httperror.InternalServerError(w, err)
+			return
+		}
+		v.Account = status.Account
+		v.Content = content.Render(&object.Status{Text: v.Text, Mentions: mentions, Tags: tagsIn(v.Text)})
+		for _, mediaID := range v.MediaAttachmentIDs {
+			if m, ok := mediaAttachmentMap[mediaID]; ok {
+				v.MediaAttachment = append(v.MediaAttachment, m)
+			}
+		}
+		history = append(history, v)
+	}
+
+	/* 最新の版 */
+	current := &object.StatusEdit{
+		StatusID:        status.ID,
+		Text:            status.Text,
+		Content:         status.Content,
+		CreateAt:        status.CreateAt,
+		Account:         status.Account,
+		MediaAttachment: status.MediaAttachment,
+	}
+	if status.EditedAt != nil {
+		current.CreateAt = *status.EditedAt
+	}
+	history = append(history, current)
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(&history); err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+}
+
+// Resolve mentions in the text of a previous version, caching the accounts found by username
+func (h *handler) mentionsIn(ctx context.Context, text string, accounts map[string]*object.Account) ([]*object.Mention, error) {
+	usernames := content.Mentions(text)
+	mentions := make([]*object.Mention, 0, len(usernames))
+	for _, username := range usernames {
+		account, ok := accounts[username]
+		if !ok {
+			var err error
+			account, err = h.app.Dao.Account().FindByUsername(ctx, username)
+			if err != nil {
+				return nil, err
+			}
+			accounts[username] = account
+		}
+		if account == nil {
+			continue
+		}
+		m := &object.Mention{AccountID: account.ID, Username: username}
+		m.SetURL()
+		mentions = append(mentions, m)
+	}
+	return mentions, nil
+}
+
+// List tags in the text of a previous version
+func tagsIn(text string) []*object.Tag {
+	names := content.Tags(text)
+	tags := make([]*object.Tag, 0, len(names))
+	for _, name := range names {
+		t := &object.Tag{Name: name}
+		t.SetURL()
+		tags = append(tags, t)
+	}
+	return tags
+}
@@ -0,0 +1,84 @@
+package statuses
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/satorunooshie/Yatter/app/domain/object"
+	"github.com/satorunooshie/Yatter/app/domain/repository"
+	"github.com/satorunooshie/Yatter/app/handler/auth"
+	"github.com/satorunooshie/Yatter/app/handler/fill"
+	"github.com/satorunooshie/Yatter/app/handler/httperror"
+	"github.com/satorunooshie/Yatter/app/handler/request"
+)
+
+// Handle request for `POST /v1/statuses/{id}/pin`
+func (h *handler) Pin(w http.ResponseWriter, r *http.Request) {
+	h.setPinned(w, r, true)
+}
+
+// Handle request for `POST /v1/statuses/{id}/unpin`
+func (h *handler) Unpin(w http.ResponseWriter, r *http.Request) {
+	h.setPinned(w, r, false)
+}
+
+func (h *handler) setPinned(w http.ResponseWriter, r *http.Request, pinned bool) {
+	account := auth.AccountOf(r)
+	if account == nil {
+		httperror.Error(w, http.StatusUnauthorized)
+		return
+	}
+
+	id, err := request.IDOf(r)
+	if err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+
+	ctx := r.Context()
+	pinRepo := h.app.Dao.PinnedStatus() // domain/repository の取得
+
+	status, err := h.app.Dao.Status().FindByID(ctx, id)
+	if err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+	if status == nil {
+		httperror.BadRequest(w, errors.New("status does not exist"))
+		return
+	}
+	if status.AccountID != account.ID {
+		httperror.Error(w, http.StatusUnauthorized)
+		return
+	}
+
+	if pinned {
+		if status.Visibility == object.VisibilityDirect {
+			httperror.BadRequest(w, errors.New("direct status cannot be pinned"))
+			return
+		}
+		err = pinRepo.Insert(ctx, account.ID, id, object.MaxPinnedStatuses)
+	} else {
+		err = pinRepo.Delete(ctx, account.ID, id)
+	}
+	if err != nil {
+		if errors.Is(err, repository.ErrPinLimit) {
+			httperror.BadRequest(w, err)
+			return
+		}
+		httperror.InternalServerError(w, err)
+		return
+	}
+
+	if err := fill.Statuses(ctx, h.app.Dao, account, []*object.Status{status}); err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(status); err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+}
@@ -20,8 +20,14 @@ func NewRouter(app *app.App) http.Handler {
 
 	h := &handler{app: app}
 	r.With(auth.Middleware(app)).Post("/", h.Create)
-	r.Get("/{id}", h.Get)
+	r.With(auth.OptionalMiddleware(app)).Get("/{id}", h.Get)
+	r.With(auth.Middleware(app)).Put("/{id}", h.Update)
 	r.With(auth.Middleware(app)).Delete("/{id}", h.Delete)
+	r.With(auth.OptionalMiddleware(app)).Get("/{id}/history", h.History)
+	r.With(auth.Middleware(app)).Post("/{id}/bookmark", h.Bookmark)
+	r.With(auth.Middleware(app)).Post("/{id}/unbookmark", h.Unbookmark)
+	r.With(auth.Middleware(app)).Post("/{id}/pin", h.Pin)
+	r.With(auth.Middleware(app)).Post("/{id}/unpin", h.Unpin)
 
 	return r
 }
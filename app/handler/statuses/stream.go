@@ -0,0 +1,19 @@
+package statuses
+
+import (
+	"context"
+	"log"
+
+	"github.com/satorunooshie/Yatter/app/domain/object"
+	"github.com/satorunooshie/Yatter/app/stream"
+)
+
+// Tell streaming clients the status was deleted.
+// status.Tags must be loaded beforehand so the hashtag streams are told too,
+// and status.Mentions so the user streams are told only when the status was visible.
+func (h *handler) streamDeleted(ctx context.Context, status *object.Status) {
+	event := stream.Event{Type: stream.EventDelete, StatusID: status.ID, Status: status}
+	if err := h.app.Bus.Publish(ctx, event, stream.StatusTopics(status)...); err != nil {
+		log.Printf("[WARN] statuses::streamDeleted::Publish(%d): %v", status.ID, err)
+	}
+}
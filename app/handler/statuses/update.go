@@ -0,0 +1,112 @@
+package statuses
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/satorunooshie/Yatter/app/domain/object"
+	"github.com/satorunooshie/Yatter/app/domain/repository"
+	"github.com/satorunooshie/Yatter/app/handler/auth"
+	"github.com/satorunooshie/Yatter/app/handler/fill"
+	"github.com/satorunooshie/Yatter/app/handler/httperror"
+	"github.com/satorunooshie/Yatter/app/handler/request"
+	"github.com/satorunooshie/Yatter/app/publish"
+)
+
+// Request body for `PUT /v1/statuses/{id}`
+type StatusUpdateRequest struct {
+	Status string `json:"status"`
+	// Media attachments to keep, or nil to keep all of them
+	MediaIDs request.IDs `json:"media_ids"`
+}
+
+// Handle request for `PUT /v1/statuses/{id}`
+func (h *handler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := request.IDOf(r)
+	if err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+
+	account := auth.AccountOf(r)
+	if account == nil {
+		httperror.Error(w, http.StatusUnauthorized)
+		return
+	}
+
+	var req StatusUpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+
+	ctx := r.Context()
+	statusRepo := h.app.Dao.Status() // domain/repository の取得
+	mediaRepo := h.app.Dao.MediaAttachment()
+
+	status, err := statusRepo.FindByID(ctx, id)
+	if err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+	if status == nil {
+		httperror.BadRequest(w, errors.New("status does not exist"))
+		return
+	}
+	if status.AccountID != account.ID {
+		httperror.Error(w, http.StatusUnauthorized)
+		return
+	}
+
+	media, err := mediaRepo.FindByStatusIDs(ctx, []object.StatusID{id})
+	if err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+	mediaIDs := make([]object.MediaAttachmentID, 0, len(media))
+	attached := make(map[object.MediaAttachmentID]bool, len(media))
+	for _, v := range media {
+		mediaIDs = append(mediaIDs, v.ID)
+		attached[v.ID] = true
+	}
+	for _, v := range req.MediaIDs {
+		if !attached[v] {
+			httperror.BadRequest(w, errors.New("media attachment is not attached to the status"))
+			return
+		}
+	}
+
+	/* 本文から抽出し直す */
+	extras, err := publish.Edited(ctx, h.app.Dao, account.ID, req.Status)
+	if err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+
+	if err := statusRepo.Update(ctx, id, account.ID, req.Status, mediaIDs, req.MediaIDs, extras); err != nil {
+		/* 確認のあとに削除されていた場合 */
+		if errors.Is(err, repository.ErrStatusNotFound) {
+			httperror.BadRequest(w, err)
+			return
+		}
+		httperror.InternalServerError(w, err)
+		return
+	}
+
+	status, err = statusRepo.FindByID(ctx, id)
+	if err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+	if err := fill.Statuses(ctx, h.app.Dao, auth.AccountOf(r), []*object.Status{status}); err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(status); err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+}
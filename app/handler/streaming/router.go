@@ -0,0 +1,32 @@
+package streaming
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi"
+
+	"github.com/satorunooshie/Yatter/app/app"
+	"github.com/satorunooshie/Yatter/app/handler/auth"
+)
+
+// Implementation of handler
+type handler struct {
+	app *app.App
+}
+
+// Create Handler for `/v1/streaming/`
+//
+// Connections last long, so it must be mounted outside the request timeout.
+func NewRouter(app *app.App) http.Handler {
+	r := chi.NewRouter()
+
+	h := &handler{app: app}
+	r.Use(auth.OptionalMiddleware(app))
+	r.Get("/", h.WebSocket)
+	r.Get("/public", h.sse(streamPublic))
+	r.Get("/hashtag", h.sse(streamHashtag))
+	r.Get("/user", h.sse(streamUser))
+	r.Get("/user/notification", h.sse(streamNotification))
+
+	return r
+}
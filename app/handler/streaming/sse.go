@@ -0,0 +1,72 @@
+package streaming
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/satorunooshie/Yatter/app/handler/httperror"
+	"github.com/satorunooshie/Yatter/app/stream"
+)
+
+// Handle request for `GET /v1/streaming/{stream}` over Server-Sent Events
+func (h *handler) sse(name string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			httperror.InternalServerError(w, errors.New("streaming is not supported"))
+			return
+		}
+
+		src, err := h.subscribe(r, name, r.URL.Query().Get("tag"))
+		if err != nil {
+			subscribeError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+		flusher.Flush()
+
+		if err := h.serve(r.Context(), src, &sseSender{w: w, flusher: flusher}); err != nil {
+			log.Printf("[WARN] streaming::sse(%s): %v", strings.Join(src.names, ":"), err)
+		}
+	}
+}
+
+type sseSender struct {
+	w       http.ResponseWriter
+	flusher http.Flusher
+}
+
+func (s *sseSender) send(_ []string, event stream.EventType, payload string) error {
+	if _, err := fmt.Fprintf(s.w, "event: %s\ndata: %s\n\n", event, payload); err != nil {
+		return err
+	}
+	s.flusher.Flush()
+	return nil
+}
+
+func (s *sseSender) ping() error {
+	if _, err := fmt.Fprint(s.w, ":thump\n\n"); err != nil {
+		return err
+	}
+	s.flusher.Flush()
+	return nil
+}
+
+// Respond to a failed subscription
+func subscribeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, errUnauthorized):
+		httperror.Error(w, http.StatusUnauthorized)
+	case errors.Is(err, errUnknownStream), errors.Is(err, errNoTag):
+		httperror.BadRequest(w, err)
+	default:
+		httperror.InternalServerError(w, err)
+	}
+}
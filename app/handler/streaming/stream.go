@@ -0,0 +1,159 @@
+package streaming
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/satorunooshie/Yatter/app/config"
+	"github.com/satorunooshie/Yatter/app/domain/object"
+	"github.com/satorunooshie/Yatter/app/handler/auth"
+	"github.com/satorunooshie/Yatter/app/handler/visibility"
+	"github.com/satorunooshie/Yatter/app/stream"
+)
+
+// Names of streams as given in `?stream=`
+const (
+	streamPublic       = "public"
+	streamHashtag      = "hashtag"
+	streamUser         = "user"
+	streamNotification = "user:notification"
+)
+
+var (
+	errUnknownStream = errors.New("unknown stream")
+	errNoTag         = errors.New("tag is required for hashtag stream")
+	errUnauthorized  = errors.New("unauthorized")
+)
+
+// Events of a stream subscribed for a client
+type source struct {
+	// Stream name followed by its parameter, telling WebSocket clients where events come from
+	names  []string
+	viewer *object.Account
+	sub    stream.Subscription
+}
+
+// Way to deliver events to a client
+type sender interface {
+	send(names []string, event stream.EventType, payload string) error
+	ping() error
+}
+
+// Subscribe to the stream for the client of the request
+func (h *handler) subscribe(r *http.Request, name, tag string) (*source, error) {
+	viewer := auth.AccountOf(r)
+
+	var topics []stream.Topic
+	names := []string{name}
+	switch name {
+	case streamPublic:
+		topics = []stream.Topic{stream.TopicPublic}
+	case streamHashtag:
+		if tag == "" {
+			return nil, errNoTag
+		}
+		topics = []stream.Topic{stream.TopicHashtag(tag)}
+		names = append(names, tag)
+	case streamUser, streamNotification:
+		if viewer == nil {
+			return nil, errUnauthorized
+		}
+		topics = []stream.Topic{stream.TopicNotification(viewer.ID)}
+		if name == streamUser {
+			topics = append(topics, stream.TopicStatuses)
+		}
+	default:
+		return nil, errUnknownStream
+	}
+
+	sub, err := h.app.Bus.Subscribe(r.Context(), topics...)
+	if err != nil {
+		return nil, err
+	}
+	return &source{names: names, viewer: viewer, sub: sub}, nil
+}
+
+// Deliver events to the client until ctx is done, the client goes away or falls behind
+func (h *handler) serve(ctx context.Context, src *source, s sender) error {
+	defer src.sub.Close()
+
+	ticker := time.NewTicker(config.Streaming.Heartbeat())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := s.ping(); err != nil {
+				return err
+			}
+		case event, ok := <-src.sub.Events():
+			if !ok {
+				if src.sub.Overflowed() {
+					return errors.New("client fell behind")
+				}
+				return nil
+			}
+			deliver, err := h.deliverable(ctx, src, event)
+			if err != nil {
+				return err
+			}
+			if !deliver {
+				continue
+			}
+			payload, err := encode(event)
+			if err != nil {
+				return err
+			}
+			if err := s.send(src.names, event.Type, payload); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Check if the event is for the client
+//
+// Only the user stream needs filtering as it receives every status to pick the home timeline ones.
+// Deletions are filtered the same way so that nobody learns of statuses they could not read.
+func (h *handler) deliverable(ctx context.Context, src *source, event stream.Event) (bool, error) {
+	if src.names[0] != streamUser || event.Type == stream.EventNotification {
+		return true, nil
+	}
+
+	status := event.Status
+	if status.AccountID == src.viewer.ID {
+		return true, nil
+	}
+	following, err := h.app.Dao.Relationship().IsFollowing(ctx, src.viewer.ID, status.AccountID)
+	if err != nil {
+		return false, err
+	}
+	if !following {
+		return false, nil
+	}
+	return visibility.IsVisible(ctx, h.app.Dao, src.viewer, status)
+}
+
+// Encode the payload of the event, which is a JSON document except for the ID of a deleted status
+func encode(event stream.Event) (string, error) {
+	var v interface{}
+	switch event.Type {
+	case stream.EventUpdate:
+		v = event.Status
+	case stream.EventNotification:
+		v = event.Notification
+	case stream.EventDelete:
+		return strconv.FormatInt(event.StatusID, 10), nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
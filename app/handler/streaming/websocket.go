@@ -0,0 +1,100 @@
+package streaming
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/satorunooshie/Yatter/app/stream"
+)
+
+const (
+	// Clients only send control frames, so anything larger is not worth reading
+	wsMaxPayload = 1 << 12
+	wsWriteWait  = 10 * time.Second
+)
+
+var upgrader = websocket.Upgrader{
+	/* 認証はトークンで行い Cookie を使わないので、どのオリジンからの接続も受け付ける */
+	CheckOrigin: func(*http.Request) bool { return true },
+}
+
+// Message sent to WebSocket clients, carrying the stream as one connection can be for any of them
+type wsMessage struct {
+	Stream  []string         `json:"stream"`
+	Event   stream.EventType `json:"event"`
+	Payload string           `json:"payload"`
+}
+
+// Handle request for `GET /v1/streaming?stream={stream}` over WebSocket
+func (h *handler) WebSocket(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	src, err := h.subscribe(r, q.Get("stream"), q.Get("tag"))
+	if err != nil {
+		subscribeError(w, err)
+		return
+	}
+
+	/* 失敗したときは Upgrade がエラーを返答している */
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		src.sub.Close()
+		log.Printf("[WARN] streaming::WebSocket::Upgrade(): %v", err)
+		return
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			log.Printf("[WARN] streaming::WebSocket::conn.Close(): %v", err)
+		}
+	}()
+	conn.SetReadLimit(wsMaxPayload)
+
+	/* クライアントが切断したら配信をやめる */
+	ctx, cancel := context.WithCancel(r.Context())
+	go func() {
+		defer cancel()
+		if err := readLoop(conn); err != nil && !websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+			log.Printf("[WARN] streaming::WebSocket::readLoop(): %v", err)
+		}
+	}()
+
+	ws := &wsSender{conn: conn}
+	if err := h.serve(ctx, src, ws); err != nil {
+		log.Printf("[WARN] streaming::WebSocket(%s): %v", strings.Join(src.names, ":"), err)
+	}
+	_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(wsWriteWait))
+}
+
+// Read messages until the client closes the connection.
+// Pings and closes are answered by the handlers of the connection while reading.
+func readLoop(conn *websocket.Conn) error {
+	for {
+		if _, _, err := conn.NextReader(); err != nil {
+			return err
+		}
+	}
+}
+
+type wsSender struct {
+	conn *websocket.Conn
+}
+
+func (s *wsSender) send(names []string, event stream.EventType, payload string) error {
+	b, err := json.Marshal(&wsMessage{Stream: names, Event: event, Payload: payload})
+	if err != nil {
+		return err
+	}
+	if err := s.conn.SetWriteDeadline(time.Now().Add(wsWriteWait)); err != nil {
+		return err
+	}
+	return s.conn.WriteMessage(websocket.TextMessage, b)
+}
+
+func (s *wsSender) ping() error {
+	return s.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteWait))
+}
@@ -0,0 +1,222 @@
+package streaming
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/gorilla/websocket"
+
+	"github.com/satorunooshie/Yatter/app/app"
+	"github.com/satorunooshie/Yatter/app/dao"
+	"github.com/satorunooshie/Yatter/app/domain/object"
+	"github.com/satorunooshie/Yatter/app/domain/repository"
+	"github.com/satorunooshie/Yatter/app/stream"
+)
+
+type fakeDao struct {
+	dao.Dao
+	accounts map[string]*object.Account
+	// Pairs of follower and followee
+	follows map[[2]object.AccountID]bool
+}
+
+func (d *fakeDao) Account() repository.Account           { return fakeAccount{byName: d.accounts} }
+func (d *fakeDao) Relationship() repository.Relationship { return fakeRelationship{follows: d.follows} }
+
+type fakeAccount struct {
+	repository.Account
+	byName map[string]*object.Account
+}
+
+func (r fakeAccount) FindByUsername(_ context.Context, username string) (*object.Account, error) {
+	return r.byName[username], nil
+}
+
+type fakeRelationship struct {
+	repository.Relationship
+	follows map[[2]object.AccountID]bool
+}
+
+func (r fakeRelationship) IsFollowing(_ context.Context, followerID, followeeID object.AccountID) (bool, error) {
+	return r.follows[[2]object.AccountID{followerID, followeeID}], nil
+}
+
+// Server streaming to alice (1), who follows bob (2) but not carol (3)
+func newTestServer(t *testing.T) (*httptest.Server, stream.Bus) {
+	t.Helper()
+
+	bus := stream.NewLocalBus(16)
+	d := &fakeDao{
+		accounts: map[string]*object.Account{"alice": {ID: 1, Username: "alice"}},
+		follows:  map[[2]object.AccountID]bool{{1, 2}: true},
+	}
+	server := httptest.NewServer(NewRouter(&app.App{Dao: d, Bus: bus}))
+	t.Cleanup(server.Close)
+	return server, bus
+}
+
+func dial(server *httptest.Server, query, username string) (*websocket.Conn, *http.Response, error) {
+	header := http.Header{}
+	if username != "" {
+		header.Set("Authentication", "username "+username)
+	}
+	return websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/?"+query, header)
+}
+
+// ID of the status in an update payload, or the payload itself for a deletion
+func payloadID(t *testing.T, m wsMessage) string {
+	t.Helper()
+
+	if m.Event == stream.EventDelete {
+		return m.Payload
+	}
+	var s struct {
+		ID json.Number `json:"id"`
+	}
+	if err := json.Unmarshal([]byte(m.Payload), &s); err != nil {
+		t.Fatal(err)
+	}
+	return s.ID.String()
+}
+
+// Read messages until the update of the status marking the end
+func readUntil(t *testing.T, conn *websocket.Conn, last string) []wsMessage {
+	t.Helper()
+
+	if err := conn.SetReadDeadline(time.Now().Add(5 * time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	messages := make([]wsMessage, 0)
+	for {
+		var m wsMessage
+		if err := conn.ReadJSON(&m); err != nil {
+			t.Fatal(err)
+		}
+		messages = append(messages, m)
+		if m.Event == stream.EventUpdate && payloadID(t, m) == last {
+			return messages
+		}
+	}
+}
+
+func TestWebSocket_Subscribe(t *testing.T) {
+	server, _ := newTestServer(t)
+
+	tests := []struct {
+		name       string
+		query      string
+		username   string
+		wantStatus int
+	}{
+		{name: "unknown stream", query: "stream=local", wantStatus: http.StatusBadRequest},
+		{name: "hashtag without tag", query: "stream=hashtag", wantStatus: http.StatusBadRequest},
+		{name: "user without auth", query: "stream=user", wantStatus: http.StatusUnauthorized},
+		{name: "user", query: "stream=user", username: "alice", wantStatus: http.StatusSwitchingProtocols},
+		{name: "public", query: "stream=public", wantStatus: http.StatusSwitchingProtocols},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			conn, resp, err := dial(server, tt.query, tt.username)
+			if conn != nil {
+				defer conn.Close()
+			}
+			if resp == nil {
+				t.Fatalf("no response: %v", err)
+			}
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d (%v)", resp.StatusCode, tt.wantStatus, err)
+			}
+		})
+	}
+}
+
+func TestWebSocket_User(t *testing.T) {
+	server, bus := newTestServer(t)
+
+	conn, _, err := dial(server, "stream=user", "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	ctx := context.Background()
+	events := []stream.Event{
+		/* フォローしている相手の投稿は届く */
+		{Type: stream.EventUpdate, Status: &object.Status{ID: 10, AccountID: 2, Visibility: object.VisibilityPublic}},
+		/* フォローしていない相手の投稿は届かない */
+		{Type: stream.EventUpdate, Status: &object.Status{ID: 11, AccountID: 3, Visibility: object.VisibilityPublic}},
+		/* 削除も読めたステータスのものだけ届く */
+		{Type: stream.EventDelete, StatusID: 12, Status: &object.Status{ID: 12, AccountID: 2, Visibility: object.VisibilityPrivate}},
+		{Type: stream.EventDelete, StatusID: 13, Status: &object.Status{ID: 13, AccountID: 2, Visibility: object.VisibilityDirect}},
+		{Type: stream.EventDelete, StatusID: 14, Status: &object.Status{ID: 14, AccountID: 2, Visibility: object.VisibilityDirect,
+			Mentions: []*object.Mention{{AccountID: 1}}}},
+		{Type: stream.EventDelete, StatusID: 15, Status: &object.Status{ID: 15, AccountID: 3, Visibility: object.VisibilityPublic}},
+		/* 自分の投稿は常に届く */
+		{Type: stream.EventUpdate, Status: &object.Status{ID: 16, AccountID: 1, Visibility: object.VisibilityDirect}},
+	}
+	for _, e := range events {
+		if err := bus.Publish(ctx, e, stream.TopicStatuses); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got := make([]string, 0)
+	for _, m := range readUntil(t, conn, "16") {
+		got = append(got, string(m.Event)+":"+payloadID(t, m))
+		if diff := cmp.Diff(m.Stream, []string{"user"}); diff != "" {
+			t.Errorf("stream returned diff (want -> got):\n%s", diff)
+		}
+	}
+	want := []string{"update:10", "delete:12", "delete:14", "update:16"}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("delivered events diff (want -> got):\n%s", diff)
+	}
+}
+
+func TestWebSocket_Hashtag(t *testing.T) {
+	server, bus := newTestServer(t)
+
+	conn, _, err := dial(server, "stream=hashtag&tag=Go", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	status := &object.Status{ID: 20, AccountID: 3, Visibility: object.VisibilityPublic, Tags: []*object.Tag{{Name: "go"}}}
+	if err := bus.Publish(context.Background(), stream.Event{Type: stream.EventUpdate, Status: status}, stream.StatusTopics(status)...); err != nil {
+		t.Fatal(err)
+	}
+
+	messages := readUntil(t, conn, "20")
+	if diff := cmp.Diff(messages[0].Stream, []string{"hashtag", "Go"}); diff != "" {
+		t.Errorf("stream returned diff (want -> got):\n%s", diff)
+	}
+}
+
+func TestWebSocket_Close(t *testing.T) {
+	server, _ := newTestServer(t)
+
+	conn, _, err := dial(server, "stream=public", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	/* クライアントが閉じたらサーバーも閉じて応える */
+	if err := conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")); err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.SetReadDeadline(time.Now().Add(5 * time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := conn.ReadMessage(); !websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+		t.Errorf("ReadMessage() error = %v, want normal closure", err)
+	}
+}
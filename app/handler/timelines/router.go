@@ -6,6 +6,7 @@ import (
 	"github.com/go-chi/chi"
 
 	"github.com/satorunooshie/Yatter/app/app"
+	"github.com/satorunooshie/Yatter/app/handler/auth"
 )
 
 // Implementation of handler
@@ -18,7 +19,9 @@ func NewRouter(app *app.App) http.Handler {
 	r := chi.NewRouter()
 
 	h := &handler{app: app}
+	r.Use(auth.OptionalMiddleware(app))
 	r.Get("/public", h.GetPublic)
+	r.Get("/tag/{hashtag}", h.GetTag)
 
 	return r
 }
@@ -0,0 +1,59 @@
+package timelines
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/go-chi/chi"
+
+	"github.com/satorunooshie/Yatter/app/domain/content"
+	"github.com/satorunooshie/Yatter/app/handler/auth"
+	"github.com/satorunooshie/Yatter/app/handler/fill"
+	"github.com/satorunooshie/Yatter/app/handler/httperror"
+	"github.com/satorunooshie/Yatter/app/handler/pagination"
+)
+
+// Handle request for `GET /v1/timelines/tag/{hashtag}`
+func (h *handler) GetTag(w http.ResponseWriter, r *http.Request) {
+	hashtag, err := url.PathUnescape(chi.URLParam(r, "hashtag"))
+	if err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+	hashtag = content.NormalizeTag(hashtag)
+	if !content.IsValidTag(hashtag) {
+		httperror.BadRequest(w, errors.New("invalid hashtag"))
+		return
+	}
+
+	page, selectType, err := h.validateQuery(r)
+	if err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+
+	ctx := r.Context()
+	statusRepo := h.app.Dao.Status()
+
+	statuses, err := statusRepo.SelectByTag(ctx, hashtag, selectType == OnlyMedia, page)
+	if err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+
+	if err := fill.Statuses(ctx, h.app.Dao, auth.AccountOf(r), statuses); err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+
+	if len(statuses) != 0 {
+		pagination.SetLink(w, r, pagination.CursorStyle, statuses[0].ID, statuses[len(statuses)-1].ID)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(&statuses); err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+}
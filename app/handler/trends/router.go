@@ -0,0 +1,27 @@
+package trends
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi"
+
+	"github.com/satorunooshie/Yatter/app/app"
+	"github.com/satorunooshie/Yatter/app/handler/auth"
+)
+
+// Implementation of handler
+type handler struct {
+	app *app.App
+}
+
+// Create Handler for `/v1/trends/`
+func NewRouter(app *app.App) http.Handler {
+	r := chi.NewRouter()
+
+	h := &handler{app: app}
+	r.Use(auth.OptionalMiddleware(app))
+	r.Get("/tags", h.GetTags)
+	r.Get("/statuses", h.GetStatuses)
+
+	return r
+}
@@ -0,0 +1,54 @@
+package trends
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/satorunooshie/Yatter/app/domain/object"
+	"github.com/satorunooshie/Yatter/app/handler/auth"
+	"github.com/satorunooshie/Yatter/app/handler/fill"
+	"github.com/satorunooshie/Yatter/app/handler/httperror"
+	"github.com/satorunooshie/Yatter/app/handler/pagination"
+)
+
+// Handle request for `GET /v1/trends/statuses`
+func (h *handler) GetStatuses(w http.ResponseWriter, r *http.Request) {
+	limit, err := pagination.Limit(r, 20, 40)
+	if err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+
+	ctx := r.Context()
+	statuses := make([]*object.Status, 0, limit)
+
+	ids := h.app.Trends.Statuses(int(limit))
+	if len(ids) != 0 {
+		found, err := h.app.Dao.Status().FindByIDs(ctx, ids)
+		if err != nil {
+			httperror.InternalServerError(w, err)
+			return
+		}
+		if err := fill.Statuses(ctx, h.app.Dao, auth.AccountOf(r), found); err != nil {
+			httperror.InternalServerError(w, err)
+			return
+		}
+
+		/* トレンドの順に並べ, 削除・凍結されたものを除く */
+		statusMap := make(map[object.StatusID]*object.Status, len(found))
+		for _, v := range found {
+			statusMap[v.ID] = v
+		}
+		for _, id := range ids {
+			if s, ok := statusMap[id]; ok && s.Visibility == object.VisibilityPublic && s.Account != nil && s.Account.SuspendAt == nil {
+				statuses = append(statuses, s)
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(&statuses); err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+}
@@ -0,0 +1,26 @@
+package trends
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/satorunooshie/Yatter/app/handler/httperror"
+	"github.com/satorunooshie/Yatter/app/handler/pagination"
+)
+
+// Handle request for `GET /v1/trends/tags`
+func (h *handler) GetTags(w http.ResponseWriter, r *http.Request) {
+	limit, err := pagination.Limit(r, 10, 20)
+	if err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+
+	tags := h.app.Trends.Tags(int(limit))
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(&tags); err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+}
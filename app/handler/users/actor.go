@@ -0,0 +1,15 @@
+package users
+
+import (
+	"net/http"
+)
+
+// Handle request for `GET /users/{username}`
+func (h *handler) Get(w http.ResponseWriter, r *http.Request) {
+	account := h.find(w, r)
+	if account == nil {
+		return
+	}
+
+	encode(w, h.app.Federation.Actor(account))
+}
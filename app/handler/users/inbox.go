@@ -0,0 +1,60 @@
+package users
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/satorunooshie/Yatter/app/activitypub"
+	"github.com/satorunooshie/Yatter/app/handler/httperror"
+)
+
+// Largest activity accepted
+const maxActivitySize = 1 << 20
+
+// Handle request for `POST /users/{username}/inbox`
+func (h *handler) Inbox(w http.ResponseWriter, r *http.Request) {
+	account := h.find(w, r)
+	if account == nil {
+		return
+	}
+
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxActivitySize))
+	if err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+
+	ctx := r.Context()
+
+	signer, err := h.app.Federation.VerifyRequest(ctx, r, body)
+	if err != nil {
+		if errors.Is(err, activitypub.ErrUnauthorized) {
+			httperror.Error(w, http.StatusUnauthorized)
+			return
+		}
+		httperror.InternalServerError(w, err)
+		return
+	}
+
+	var activity activitypub.Activity
+	if err := json.Unmarshal(body, &activity); err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+
+	if err := h.app.Federation.HandleInbox(ctx, account, signer, &activity); err != nil {
+		switch {
+		case errors.Is(err, activitypub.ErrInvalidActivity):
+			httperror.BadRequest(w, err)
+		case errors.Is(err, activitypub.ErrForbidden):
+			httperror.Error(w, http.StatusForbidden)
+		default:
+			httperror.InternalServerError(w, err)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
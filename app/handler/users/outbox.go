@@ -0,0 +1,61 @@
+package users
+
+import (
+	"net/http"
+
+	"github.com/satorunooshie/Yatter/app/activitypub"
+	"github.com/satorunooshie/Yatter/app/domain/object"
+	"github.com/satorunooshie/Yatter/app/handler/httperror"
+)
+
+// Number of the latest statuses in the outbox
+const outboxLimit = 20
+
+// Handle request for `GET /users/{username}/outbox`
+func (h *handler) Outbox(w http.ResponseWriter, r *http.Request) {
+	account := h.find(w, r)
+	if account == nil {
+		return
+	}
+
+	statuses, err := h.app.Dao.Status().SelectByAccountID(r.Context(), account.ID, []object.Visibility{object.VisibilityPublic}, false, false, object.NewestPage(outboxLimit))
+	if err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+
+	items := make([]interface{}, 0, len(statuses))
+	for _, s := range statuses {
+		activity, err := h.app.Federation.CreateActivity(account, s)
+		if err != nil {
+			httperror.InternalServerError(w, err)
+			return
+		}
+		activity.Context = ""
+		items = append(items, activity)
+	}
+
+	encode(w, &activitypub.OrderedCollection{
+		Context:      activitypub.ContextURL,
+		ID:           h.app.Federation.OutboxURI(account.Username),
+		Type:         "OrderedCollection",
+		TotalItems:   account.StatusesCount,
+		OrderedItems: items,
+	})
+}
+
+// Handle request for `GET /users/{username}/followers`
+func (h *handler) Followers(w http.ResponseWriter, r *http.Request) {
+	account := h.find(w, r)
+	if account == nil {
+		return
+	}
+
+	/* フォロワーの一覧は公開せず数だけを返す */
+	encode(w, &activitypub.OrderedCollection{
+		Context:    activitypub.ContextURL,
+		ID:         h.app.Federation.FollowersURI(account.Username),
+		Type:       "OrderedCollection",
+		TotalItems: account.FollowersCount,
+	})
+}
@@ -0,0 +1,55 @@
+package users
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi"
+
+	"github.com/satorunooshie/Yatter/app/activitypub"
+	"github.com/satorunooshie/Yatter/app/app"
+	"github.com/satorunooshie/Yatter/app/domain/object"
+	"github.com/satorunooshie/Yatter/app/handler/httperror"
+)
+
+// Implementation of handler
+type handler struct {
+	app *app.App
+}
+
+// Create Handler for `/users/`, the ActivityPub actors of local accounts
+func NewRouter(app *app.App) http.Handler {
+	r := chi.NewRouter()
+
+	h := &handler{app: app}
+	r.Get("/{username}", h.Get)
+	r.Post("/{username}/inbox", h.Inbox)
+	r.Get("/{username}/outbox", h.Outbox)
+	r.Get("/{username}/followers", h.Followers)
+	r.Get("/{username}/statuses/{id}", h.Status)
+
+	return r
+}
+
+// Find the local account in the path, responding with Not Found (404) if it does not exist
+func (h *handler) find(w http.ResponseWriter, r *http.Request) *object.Account {
+	account, err := h.app.Dao.Account().FindByUsername(r.Context(), chi.URLParam(r, "username"))
+	if err != nil {
+		httperror.InternalServerError(w, err)
+		return nil
+	}
+	if account == nil {
+		httperror.Error(w, http.StatusNotFound)
+		return nil
+	}
+	return account
+}
+
+// Response with the ActivityPub object
+func encode(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", activitypub.ContentType)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+}
@@ -0,0 +1,38 @@
+package users
+
+import (
+	"net/http"
+
+	"github.com/satorunooshie/Yatter/app/activitypub"
+	"github.com/satorunooshie/Yatter/app/handler/httperror"
+	"github.com/satorunooshie/Yatter/app/handler/request"
+)
+
+// Handle request for `GET /users/{username}/statuses/{id}`
+func (h *handler) Status(w http.ResponseWriter, r *http.Request) {
+	id, err := request.IDOf(r)
+	if err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+
+	account := h.find(w, r)
+	if account == nil {
+		return
+	}
+
+	status, err := h.app.Dao.Status().FindByID(r.Context(), id)
+	if err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+	/* 署名付きの取得には未対応なので、誰でも読めるものだけを返す */
+	if status == nil || status.AccountID != account.ID || !status.IsVisibleTo(nil, false, false) {
+		httperror.Error(w, http.StatusNotFound)
+		return
+	}
+
+	note := h.app.Federation.Note(account, status)
+	note.Context = activitypub.ContextURL
+	encode(w, note)
+}
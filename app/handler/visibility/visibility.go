@@ -0,0 +1,78 @@
+package visibility
+
+import (
+	"context"
+
+	"github.com/satorunooshie/Yatter/app/dao"
+	"github.com/satorunooshie/Yatter/app/domain/object"
+)
+
+// Check if viewer (nil for anonymous) is allowed to read the status.
+// status.Mentions must be loaded beforehand.
+func IsVisible(ctx context.Context, d dao.Dao, viewer *object.Account, status *object.Status) (bool, error) {
+	if viewer == nil || viewer.ID == status.AccountID {
+		return status.IsVisibleTo(viewer, false, false), nil
+	}
+
+	var following bool
+	if status.Visibility == object.VisibilityPrivate {
+		var err error
+		following, err = d.Relationship().IsFollowing(ctx, viewer.ID, status.AccountID)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	return status.IsVisibleTo(viewer, following, status.IsMentioned(viewer.ID)), nil
+}
+
+// List visibilities of the author's statuses viewer (nil for anonymous) is allowed to read
+// without being mentioned in them
+func Visibilities(ctx context.Context, d dao.Dao, viewer *object.Account, authorID object.AccountID) ([]object.Visibility, error) {
+	if viewer != nil && viewer.ID == authorID {
+		return []object.Visibility{object.VisibilityPublic, object.VisibilityUnlisted, object.VisibilityPrivate, object.VisibilityDirect}, nil
+	}
+
+	visibilities := []object.Visibility{object.VisibilityPublic, object.VisibilityUnlisted}
+	if viewer != nil {
+		following, err := d.Relationship().IsFollowing(ctx, viewer.ID, authorID)
+		if err != nil {
+			return nil, err
+		}
+		if following {
+			visibilities = append(visibilities, object.VisibilityPrivate)
+		}
+	}
+	return visibilities, nil
+}
+
+// Find statuses pinned by the account with the visibilities, most recently pinned first
+func PinnedStatuses(ctx context.Context, d dao.Dao, accountID object.AccountID, visibilities []object.Visibility) ([]*object.Status, error) {
+	ids, err := d.PinnedStatus().FindByAccountID(ctx, accountID)
+	if err != nil || len(ids) == 0 {
+		return nil, err
+	}
+	found, err := d.Status().FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	visible := make(map[object.Visibility]bool, len(visibilities))
+	for _, v := range visibilities {
+		visible[v] = true
+	}
+	statusMap := make(map[object.StatusID]*object.Status, len(found))
+	for _, v := range found {
+		if visible[v.Visibility] {
+			statusMap[v.ID] = v
+		}
+	}
+
+	statuses := make([]*object.Status, 0, len(ids))
+	for _, id := range ids {
+		if s, ok := statusMap[id]; ok {
+			statuses = append(statuses, s)
+		}
+	}
+	return statuses, nil
+}
@@ -0,0 +1,70 @@
+package webhooks
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+
+	"github.com/satorunooshie/Yatter/app/domain/object"
+	"github.com/satorunooshie/Yatter/app/handler/httperror"
+)
+
+// Request body for `POST /v1/admin/webhooks`
+type WebhookCreateRequest struct {
+	URL string `json:"url"`
+	// Generated if empty
+	Secret string               `json:"secret"`
+	Events object.WebhookEvents `json:"events"`
+}
+
+// Response for `POST /v1/admin/webhooks`, the only one showing the secret
+type webhookCreated struct {
+	*object.Webhook
+	Secret string `json:"secret"`
+}
+
+// Handle request for `POST /v1/admin/webhooks`
+func (h *handler) Create(w http.ResponseWriter, r *http.Request) {
+	var req WebhookCreateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+	if err := validateURL(req.URL); err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+	if err := validateEvents(req.Events); err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+
+	if req.Secret == "" {
+		b := make([]byte, 32)
+		if _, err := rand.Read(b); err != nil {
+			httperror.InternalServerError(w, err)
+			return
+		}
+		req.Secret = hex.EncodeToString(b)
+	}
+
+	ctx := r.Context()
+
+	id, err := h.app.Dao.Webhook().Insert(ctx, req.URL, req.Secret, req.Events)
+	if err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+	webhook, err := h.find(ctx, id)
+	if err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(&webhookCreated{Webhook: webhook, Secret: webhook.Secret}); err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+}
@@ -0,0 +1,53 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/satorunooshie/Yatter/app/handler/httperror"
+	"github.com/satorunooshie/Yatter/app/handler/pagination"
+	"github.com/satorunooshie/Yatter/app/handler/request"
+)
+
+// Handle request for `GET /v1/admin/webhooks/{id}/deliveries`
+func (h *handler) Deliveries(w http.ResponseWriter, r *http.Request) {
+	id, err := request.IDOf(r)
+	if err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+
+	page, err := pagination.Parse(r, 40, 80)
+	if err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+
+	ctx := r.Context()
+
+	webhook, err := h.find(ctx, id)
+	if err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+	if webhook == nil {
+		httperror.BadRequest(w, errors.New("webhook does not exist"))
+		return
+	}
+
+	deliveries, err := h.app.Dao.WebhookDelivery().Select(ctx, id, page)
+	if err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+
+	if len(deliveries) != 0 {
+		pagination.SetLink(w, r, pagination.CursorStyle, deliveries[0].ID, deliveries[len(deliveries)-1].ID)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(&deliveries); err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+}
@@ -0,0 +1,35 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/satorunooshie/Yatter/app/handler/httperror"
+	"github.com/satorunooshie/Yatter/app/handler/request"
+)
+
+// Handle request for `GET /v1/admin/webhooks/{id}`
+func (h *handler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := request.IDOf(r)
+	if err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+
+	webhook, err := h.find(r.Context(), id)
+	if err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+	if webhook == nil {
+		httperror.BadRequest(w, errors.New("webhook does not exist"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(webhook); err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+}
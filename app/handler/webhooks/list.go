@@ -0,0 +1,23 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/satorunooshie/Yatter/app/handler/httperror"
+)
+
+// Handle request for `GET /v1/admin/webhooks`
+func (h *handler) List(w http.ResponseWriter, r *http.Request) {
+	webhooks, err := h.app.Dao.Webhook().Select(r.Context())
+	if err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(&webhooks); err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+}
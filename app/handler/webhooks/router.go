@@ -0,0 +1,63 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/go-chi/chi"
+
+	"github.com/satorunooshie/Yatter/app/app"
+	"github.com/satorunooshie/Yatter/app/domain/object"
+	"github.com/satorunooshie/Yatter/app/handler/auth"
+)
+
+// Implementation of handler
+type handler struct {
+	app *app.App
+}
+
+// Create Handler for `/v1/admin/webhooks/`
+func NewRouter(app *app.App) http.Handler {
+	r := chi.NewRouter()
+
+	h := &handler{app: app}
+	r.Use(auth.AdminMiddleware(app))
+	r.Get("/", h.List)
+	r.Post("/", h.Create)
+	r.Get("/{id}", h.Get)
+	r.Put("/{id}", h.Update)
+	r.Delete("/{id}", h.Delete)
+	r.Get("/{id}/deliveries", h.Deliveries)
+
+	return r
+}
+
+// Find the webhook, nil if it does not exist
+func (h *handler) find(ctx context.Context, id object.WebhookID) (*object.Webhook, error) {
+	return h.app.Dao.Webhook().FindByID(ctx, id)
+}
+
+func validateURL(s string) error {
+	u, err := url.Parse(s)
+	if err != nil {
+		return err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("url must be an absolute http or https URL")
+	}
+	return nil
+}
+
+func validateEvents(events object.WebhookEvents) error {
+	if len(events) == 0 {
+		return errors.New("events must not be empty")
+	}
+	for _, e := range events {
+		if !e.IsValid() {
+			return errors.New("unknown event: " + string(e))
+		}
+	}
+	return nil
+}
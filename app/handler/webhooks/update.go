@@ -0,0 +1,80 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/satorunooshie/Yatter/app/domain/object"
+	"github.com/satorunooshie/Yatter/app/handler/httperror"
+	"github.com/satorunooshie/Yatter/app/handler/request"
+)
+
+// Request body for `PUT /v1/admin/webhooks/{id}`, leaving absent fields unchanged
+type WebhookUpdateRequest struct {
+	URL    *string              `json:"url"`
+	Events object.WebhookEvents `json:"events"`
+	// Re-enabling a webhook disabled after repeated failures clears its failure count
+	Enabled *bool `json:"enabled"`
+}
+
+// Handle request for `PUT /v1/admin/webhooks/{id}`
+func (h *handler) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := request.IDOf(r)
+	if err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+
+	var req WebhookUpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		httperror.BadRequest(w, err)
+		return
+	}
+
+	ctx := r.Context()
+
+	webhook, err := h.find(ctx, id)
+	if err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+	if webhook == nil {
+		httperror.BadRequest(w, errors.New("webhook does not exist"))
+		return
+	}
+
+	if req.URL != nil {
+		if err := validateURL(*req.URL); err != nil {
+			httperror.BadRequest(w, err)
+			return
+		}
+		webhook.URL = *req.URL
+	}
+	if req.Events != nil {
+		if err := validateEvents(req.Events); err != nil {
+			httperror.BadRequest(w, err)
+			return
+		}
+		webhook.Events = req.Events
+	}
+	if req.Enabled != nil {
+		webhook.Enabled = *req.Enabled
+	}
+
+	if err := h.app.Dao.Webhook().Update(ctx, id, webhook.URL, webhook.Events, webhook.Enabled); err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+	webhook, err = h.find(ctx, id)
+	if err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(webhook); err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+}
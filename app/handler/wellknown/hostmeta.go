@@ -0,0 +1,40 @@
+package wellknown
+
+import (
+	"encoding/xml"
+	"io"
+	"net/http"
+
+	"github.com/satorunooshie/Yatter/app/handler/httperror"
+)
+
+type (
+	// Extensible Resource Descriptor of host-meta (RFC 6415)
+	xrd struct {
+		XMLName xml.Name  `xml:"http://docs.oasis-open.org/ns/xri/xrd-1.0 XRD"`
+		Links   []xrdLink `xml:"Link"`
+	}
+
+	xrdLink struct {
+		Rel      string `xml:"rel,attr"`
+		Type     string `xml:"type,attr,omitempty"`
+		Template string `xml:"template,attr,omitempty"`
+	}
+)
+
+// Handle request for `GET /.well-known/host-meta`, pointing to WebFinger
+func (h *handler) HostMeta(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/xrd+xml; charset=utf-8")
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+	if err := xml.NewEncoder(w).Encode(&xrd{
+		Links: []xrdLink{
+			{Rel: "lrdd", Type: "application/jrd+json", Template: h.app.Federation.BaseURL() + "/.well-known/webfinger?resource={uri}"},
+		},
+	}); err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+}
@@ -0,0 +1,26 @@
+package wellknown
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/satorunooshie/Yatter/app/handler/httperror"
+)
+
+// Handle request for `GET /.well-known/nodeinfo`, listing the NodeInfo documents
+func (h *handler) NodeInfo(w http.ResponseWriter, r *http.Request) {
+	base := h.app.Federation.BaseURL()
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(&struct {
+		Links []Link `json:"links"`
+	}{
+		Links: []Link{
+			{Rel: "http://nodeinfo.diaspora.software/ns/schema/2.0", Href: base + "/nodeinfo/2.0"},
+			{Rel: "http://nodeinfo.diaspora.software/ns/schema/2.1", Href: base + "/nodeinfo/2.1"},
+		},
+	}); err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+}
@@ -0,0 +1,26 @@
+package wellknown
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi"
+
+	"github.com/satorunooshie/Yatter/app/app"
+)
+
+// Implementation of handler
+type handler struct {
+	app *app.App
+}
+
+// Create Handler for `/.well-known/`, which other servers discover accounts and the instance by
+func NewRouter(app *app.App) http.Handler {
+	r := chi.NewRouter()
+
+	h := &handler{app: app}
+	r.Get("/webfinger", h.WebFinger)
+	r.Get("/host-meta", h.HostMeta)
+	r.Get("/nodeinfo", h.NodeInfo)
+
+	return r
+}
@@ -0,0 +1,94 @@
+package wellknown
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/satorunooshie/Yatter/app/activitypub"
+	"github.com/satorunooshie/Yatter/app/config"
+	"github.com/satorunooshie/Yatter/app/handler/httperror"
+)
+
+type (
+	// JSON Resource Descriptor (RFC 7033)
+	JRD struct {
+		Subject string   `json:"subject"`
+		Aliases []string `json:"aliases,omitempty"`
+		Links   []Link   `json:"links"`
+	}
+
+	Link struct {
+		Rel      string `json:"rel"`
+		Type     string `json:"type,omitempty"`
+		Href     string `json:"href,omitempty"`
+		Template string `json:"template,omitempty"`
+	}
+)
+
+// Handle request for `GET /.well-known/webfinger`
+func (h *handler) WebFinger(w http.ResponseWriter, r *http.Request) {
+	resource := r.URL.Query().Get("resource")
+	if resource == "" {
+		httperror.BadRequest(w, errors.New("resource is required"))
+		return
+	}
+
+	domain := config.Federation.Domain()
+	username, ok := usernameOf(resource, domain, h.app.Federation.BaseURL())
+	if !ok {
+		httperror.Error(w, http.StatusNotFound)
+		return
+	}
+
+	account, err := h.app.Dao.Account().FindByUsername(r.Context(), username)
+	if err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+	if account == nil {
+		httperror.Error(w, http.StatusNotFound)
+		return
+	}
+
+	actor := h.app.Federation.ActorURI(account.Username)
+	w.Header().Set("Content-Type", "application/jrd+json; charset=utf-8")
+	if err := json.NewEncoder(w).Encode(&JRD{
+		Subject: "acct:" + account.Username + "@" + domain,
+		Aliases: []string{actor},
+		Links: []Link{
+			{Rel: "self", Type: activitypub.ContentType, Href: actor},
+		},
+	}); err != nil {
+		httperror.InternalServerError(w, err)
+		return
+	}
+}
+
+// Username of the local account the resource refers to, either `acct:username@domain` or the actor ID
+func usernameOf(resource, domain, baseURL string) (string, bool) {
+	if strings.HasPrefix(resource, "acct:") {
+		acct := strings.TrimPrefix(strings.TrimPrefix(resource, "acct:"), "@")
+		i := strings.LastIndexByte(acct, '@')
+		if i <= 0 || !strings.EqualFold(acct[i+1:], domain) {
+			return "", false
+		}
+		return acct[:i], true
+	}
+
+	prefix := baseURL + "/users/"
+	if !strings.HasPrefix(resource, prefix) {
+		return "", false
+	}
+	escaped := strings.TrimPrefix(resource, prefix)
+	if escaped == "" || strings.ContainsAny(escaped, "/?#") {
+		return "", false
+	}
+	username, err := url.PathUnescape(escaped)
+	if err != nil {
+		return "", false
+	}
+	return username, true
+}
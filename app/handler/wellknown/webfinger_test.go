@@ -0,0 +1,39 @@
+package wellknown
+
+import (
+	"testing"
+)
+
+func TestUsernameOf(t *testing.T) {
+	const (
+		domain  = "yatter.example"
+		baseURL = "https://yatter.example"
+	)
+	tests := []struct {
+		resource string
+		want     string
+		wantOK   bool
+	}{
+		{resource: "acct:john@yatter.example", want: "john", wantOK: true},
+		{resource: "acct:@john@yatter.example", want: "john", wantOK: true},
+		{resource: "acct:john@YATTER.example", want: "john", wantOK: true},
+		{resource: "acct:john@remote.example", wantOK: false},
+		{resource: "acct:john", wantOK: false},
+		{resource: "acct:@yatter.example", wantOK: false},
+		{resource: "https://yatter.example/users/john", want: "john", wantOK: true},
+		{resource: "https://yatter.example/users/j%C3%B6rg", want: "jörg", wantOK: true},
+		{resource: "https://yatter.example/users/john/outbox", wantOK: false},
+		{resource: "https://yatter.example/users/", wantOK: false},
+		{resource: "https://remote.example/users/john", wantOK: false},
+		{resource: "mailto:john@yatter.example", wantOK: false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.resource, func(t *testing.T) {
+			got, ok := usernameOf(tt.resource, domain, baseURL)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("usernameOf() = (%q, %v), want (%q, %v)", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
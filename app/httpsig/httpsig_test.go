@@ -0,0 +1,175 @@
+package httpsig
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestSignature_Verify(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	body := []byte(`{"type":"Create"}`)
+
+	newRequest := func(t *testing.T, signedAt time.Time) *http.Request {
+		req, err := http.NewRequest(http.MethodPost, "https://example.com/users/john/inbox", bytes.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := Sign(req, body, "https://remote.example/users/jane#main-key", key, signedAt); err != nil {
+			t.Fatal(err)
+		}
+		return req
+	}
+
+	tests := []struct {
+		name    string
+		request func(t *testing.T) *http.Request
+		body    []byte
+		key     *rsa.PublicKey
+		wantErr bool
+	}{
+		{
+			name:    "valid",
+			request: func(t *testing.T) *http.Request { return newRequest(t, now) },
+			body:    body,
+			key:     &key.PublicKey,
+			wantErr: false,
+		},
+		{
+			name:    "other key",
+			request: func(t *testing.T) *http.Request { return newRequest(t, now) },
+			body:    body,
+			key:     &other.PublicKey,
+			wantErr: true,
+		},
+		{
+			name:    "tampered body",
+			request: func(t *testing.T) *http.Request { return newRequest(t, now) },
+			body:    []byte(`{"type":"Delete"}`),
+			key:     &key.PublicKey,
+			wantErr: true,
+		},
+		{
+			name: "tampered digest",
+			request: func(t *testing.T) *http.Request {
+				req := newRequest(t, now)
+				req.Header.Set("Digest", Digest([]byte(`{"type":"Delete"}`)))
+				return req
+			},
+			body:    []byte(`{"type":"Delete"}`),
+			key:     &key.PublicKey,
+			wantErr: true,
+		},
+		{
+			name: "other inbox",
+			request: func(t *testing.T) *http.Request {
+				req := newRequest(t, now)
+				req.URL.Path = "/users/jane/inbox"
+				return req
+			},
+			body:    body,
+			key:     &key.PublicKey,
+			wantErr: true,
+		},
+		{
+			name:    "stale",
+			request: func(t *testing.T) *http.Request { return newRequest(t, now.Add(-time.Hour)) },
+			body:    body,
+			key:     &key.PublicKey,
+			wantErr: true,
+		},
+		{
+			name: "digest not signed",
+			request: func(t *testing.T) *http.Request {
+				req := newRequest(t, now)
+				sig := req.Header.Get("Signature")
+				req.Header.Set("Signature", string(bytes.Replace([]byte(sig), []byte(" digest"), nil, 1)))
+				return req
+			},
+			body:    body,
+			key:     &key.PublicKey,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			req := tt.request(t)
+			sig, err := Parse(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if sig.KeyID != "https://remote.example/users/jane#main-key" {
+				t.Errorf("Parse() keyId = %s", sig.KeyID)
+			}
+			err = sig.Verify(req, tt.body, tt.key, now.Add(time.Minute), 5*time.Minute)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Signature.Verify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidSignature) {
+				t.Errorf("Signature.Verify() error = %v, want ErrInvalidSignature", err)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  string
+		wantErr bool
+	}{
+		{
+			name:    "mastodon",
+			header:  `keyId="https://remote.example/users/jane#main-key",algorithm="rsa-sha256",headers="(request-target) host date digest content-type",signature="c2lnbmF0dXJl"`,
+			wantErr: false,
+		},
+		{
+			name:    "hs2019",
+			header:  `keyId="https://remote.example/users/jane#main-key",algorithm="hs2019",headers="(request-target) host date digest",signature="c2lnbmF0dXJl"`,
+			wantErr: false,
+		},
+		{
+			name:    "missing",
+			header:  "",
+			wantErr: true,
+		},
+		{
+			name:    "no keyId",
+			header:  `algorithm="rsa-sha256",signature="c2lnbmF0dXJl"`,
+			wantErr: true,
+		},
+		{
+			name:    "unsupported algorithm",
+			header:  `keyId="key",algorithm="hmac-sha256",signature="c2lnbmF0dXJl"`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost, "https://example.com/users/john/inbox", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.header != "" {
+				req.Header.Set("Signature", tt.header)
+			}
+			if _, err := Parse(req); (err != nil) != tt.wantErr {
+				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package publish
+
+import (
+	"context"
+	"time"
+
+	"github.com/satorunooshie/Yatter/app/dao"
+	"github.com/satorunooshie/Yatter/app/domain/content"
+	"github.com/satorunooshie/Yatter/app/domain/object"
+)
+
+// Insert a status with its mentions, tags and poll
+func Status(ctx context.Context, d dao.Dao, accountID object.AccountID, params object.StatusParams) (object.StatusID, error) {
+	extras, err := Extras(ctx, d, accountID, params, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	return d.Status().Insert(ctx, accountID, params.Text, params.Visibility, extras)
+}
+
+// Resolve mentions, tags and the poll of a status the account posts at now
+func Extras(ctx context.Context, d dao.Dao, accountID object.AccountID, params object.StatusParams, now time.Time) (object.StatusExtras, error) {
+	extras, err := extract(ctx, d, accountID, params.Text)
+	if err != nil {
+		return extras, err
+	}
+
+	if p := params.Poll; p != nil {
+		extras.Poll = p
+		extras.PollExpireAt = now.Add(time.Duration(p.ExpiresIn) * time.Second)
+	}
+	return extras, nil
+}
+
+// Resolve mentions and tags in the new text of a status the account edits.
+// The poll of a status is never replaced.
+func Edited(ctx context.Context, d dao.Dao, accountID object.AccountID, text string) (object.StatusExtras, error) {
+	return extract(ctx, d, accountID, text)
+}
+
+// Resolve accounts mentioned in the text and tags in it, ignoring unknown usernames
+//
+// An account is mentioned once even if usernames written differently resolve to it.
+func extract(ctx context.Context, d dao.Dao, accountID object.AccountID, text string) (object.StatusExtras, error) {
+	extras := object.StatusExtras{Tags: content.Tags(text)}
+
+	usernames := content.Mentions(text)
+	if len(usernames) == 0 {
+		return extras, nil
+	}
+
+	accountRepo := d.Account()
+	mentioned := make(map[object.AccountID]bool, len(usernames))
+	for _, username := range usernames {
+		account, err := accountRepo.FindByUsername(ctx, username)
+		if err != nil {
+			return extras, err
+		}
+		if account == nil || mentioned[account.ID] {
+			continue
+		}
+		mentioned[account.ID] = true
+		extras.MentionedAccountIDs = append(extras.MentionedAccountIDs, account.ID)
+		/* 自分へのメンションは通知しない */
+		if account.ID != accountID {
+			extras.NotifiedAccountIDs = append(extras.NotifiedAccountIDs, account.ID)
+		}
+	}
+	return extras, nil
+}
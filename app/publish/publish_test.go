@@ -0,0 +1,35 @@
+package publish
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/satorunooshie/Yatter/app/domain/object"
+)
+
+func TestExtras(t *testing.T) {
+	d := &fakeDao{accounts: map[string]*object.Account{
+		"john": {ID: 1, Username: "john"},
+		"jane": {ID: 2, Username: "jane"},
+	}}
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	poll := &object.PollParams{Options: []string{"a", "b"}, ExpiresIn: 300}
+
+	got, err := Extras(context.Background(), d, 1, object.StatusParams{Text: "@jane @Jane @john @nobody #Go", Poll: poll}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := object.StatusExtras{
+		MentionedAccountIDs: []object.AccountID{2, 1},
+		NotifiedAccountIDs:  []object.AccountID{2},
+		Tags:                []string{"go"},
+		Poll:                poll,
+		PollExpireAt:        now.Add(5 * time.Minute),
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("Extras() returned diff (want -> got):\n%s", diff)
+	}
+}
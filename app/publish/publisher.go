@@ -0,0 +1,94 @@
+package publish
+
+import (
+	"context"
+	"log"
+
+	"github.com/satorunooshie/Yatter/app/activitypub"
+	"github.com/satorunooshie/Yatter/app/dao"
+	"github.com/satorunooshie/Yatter/app/domain/object"
+	"github.com/satorunooshie/Yatter/app/handler/fill"
+	"github.com/satorunooshie/Yatter/app/stream"
+	"github.com/satorunooshie/Yatter/app/webhook"
+)
+
+// Tells others about statuses once they are stored
+type Announcer interface {
+	Announce(ctx context.Context, account *object.Account, id object.StatusID) error
+}
+
+// Publishes statuses, telling streaming clients, webhooks and remote followers about them
+type Publisher struct {
+	dao        dao.Dao
+	bus        stream.Bus
+	federation *activitypub.Federation
+}
+
+var _ Announcer = (*Publisher)(nil)
+
+// Create Publisher
+func NewPublisher(d dao.Dao, bus stream.Bus, federation *activitypub.Federation) *Publisher {
+	return &Publisher{
+		dao:        d,
+		bus:        bus,
+		federation: federation,
+	}
+}
+
+// Publish the status of the account now
+func (p *Publisher) Post(ctx context.Context, account *object.Account, params object.StatusParams) (object.StatusID, error) {
+	id, err := Status(ctx, p.dao, account.ID, params)
+	if err != nil {
+		return 0, err
+	}
+	if err := p.Announce(ctx, account, id); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// Tell streaming clients, webhooks and remote followers about the status the account has just published.
+// Failures to tell them are only logged as the status is already stored.
+func (p *Publisher) Announce(ctx context.Context, account *object.Account, id object.StatusID) error {
+	status, err := p.dao.Status().FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	/* 閲覧者ごとに異なる項目を含めずに配信する */
+	if err := fill.Statuses(ctx, p.dao, nil, []*object.Status{status}); err != nil {
+		return err
+	}
+
+	p.stream(ctx, status)
+	/* 宛先が閲覧を許されているとは限らないので公開投稿だけ送る */
+	if status.Visibility == object.VisibilityPublic {
+		if err := webhook.Enqueue(ctx, p.dao, object.WebhookStatusCreated, status); err != nil {
+			log.Printf("[WARN] publish::Announce::webhook.Enqueue(%d): %v", id, err)
+		}
+	}
+	if err := p.federation.PublishCreate(ctx, account, status); err != nil {
+		log.Printf("[WARN] publish::Announce::PublishCreate(%d): %v", id, err)
+	}
+	return nil
+}
+
+// Publish the new status and the notifications it caused to streaming clients
+func (p *Publisher) stream(ctx context.Context, status *object.Status) {
+	if err := p.bus.Publish(ctx, stream.Event{Type: stream.EventUpdate, Status: status}, stream.StatusTopics(status)...); err != nil {
+		log.Printf("[WARN] publish::stream::Publish(%d): %v", status.ID, err)
+		return
+	}
+
+	notifications, err := p.dao.Notification().FindByStatusID(ctx, status.ID)
+	if err != nil {
+		log.Printf("[WARN] publish::stream::FindByStatusID(%d): %v", status.ID, err)
+		return
+	}
+	for _, n := range notifications {
+		n.Account = status.Account
+		n.Status = status
+		if err := p.bus.Publish(ctx, stream.Event{Type: stream.EventNotification, Notification: n}, stream.TopicNotification(n.AccountID)); err != nil {
+			log.Printf("[WARN] publish::stream::Publish(notification %d): %v", n.ID, err)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package publish
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/satorunooshie/Yatter/app/dao"
+	"github.com/satorunooshie/Yatter/app/domain/object"
+)
+
+// Maximum number of scheduled statuses published at once
+const batchSize = 100
+
+// Publishes scheduled statuses in background once they are due
+type Scheduler struct {
+	dao       dao.Dao
+	announcer Announcer
+	now       func() time.Time
+}
+
+// Create Scheduler announcing the statuses it publishes with announcer
+func NewScheduler(d dao.Dao, announcer Announcer) *Scheduler {
+	return &Scheduler{
+		dao:       d,
+		announcer: announcer,
+		now:       time.Now,
+	}
+}
+
+// Publish due statuses every interval until ctx is done
+func (s *Scheduler) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.PublishDue(ctx); err != nil {
+			log.Printf("[WARN] publish::Run::PublishDue(): %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Publish scheduled statuses which are due, returning the IDs of the statuses published.
+//
+// Each scheduled status is published exactly once even if several instances run,
+// since only the one which removes it from the schedule publishes it.
+func (s *Scheduler) PublishDue(ctx context.Context) ([]object.StatusID, error) {
+	now := s.now()
+	repo := s.dao.ScheduledStatus()
+
+	due, err := repo.SelectDue(ctx, now, batchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	published := make([]object.StatusID, 0, len(due))
+	for _, v := range due {
+		/* 直接の投稿と同じくメンション、タグ、投票もステータスと一緒に保存する */
+		extras, err := Extras(ctx, s.dao, v.AccountID, v.Params, now)
+		if err != nil {
+			return published, err
+		}
+		id, err := repo.Publish(ctx, v.ID, now, extras)
+		if err != nil {
+			return published, err
+		}
+		if id == 0 {
+			/* 他のインスタンスが公開済み */
+			continue
+		}
+		published = append(published, id)
+
+		/* 公開済みなのでエラーがあっても残りの公開を続ける */
+		if err := s.announce(ctx, v.AccountID, id); err != nil {
+			log.Printf("[WARN] publish::PublishDue::announce(%d): %v", id, err)
+		}
+	}
+	return published, nil
+}
+
+func (s *Scheduler) announce(ctx context.Context, accountID object.AccountID, id object.StatusID) error {
+	account, err := s.dao.Account().FindByID(ctx, accountID)
+	if err != nil {
+		return err
+	}
+	if account == nil {
+		return errors.New("account does not exist")
+	}
+	return s.announcer.Announce(ctx, account, id)
+}
+
+// Minimum time ahead a status can be scheduled at, so that it can still be edited or canceled
+const MinScheduleDelay = 5 * time.Minute
+
+// Check if a status can be scheduled at the time
+func CheckSchedule(scheduledAt, now time.Time) error {
+	if scheduledAt.Before(now.Add(MinScheduleDelay)) {
+		return errors.New("scheduled_at must be at least 5 minutes ahead")
+	}
+	return nil
+}
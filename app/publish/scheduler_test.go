@@ -0,0 +1,136 @@
+package publish
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/satorunooshie/Yatter/app/dao"
+	"github.com/satorunooshie/Yatter/app/domain/object"
+	"github.com/satorunooshie/Yatter/app/domain/repository"
+)
+
+type fakeDao struct {
+	dao.Dao
+	accounts  map[string]*object.Account
+	scheduled *fakeScheduledStatus
+}
+
+func (d *fakeDao) Account() repository.Account                 { return fakeAccount{byName: d.accounts} }
+func (d *fakeDao) ScheduledStatus() repository.ScheduledStatus { return d.scheduled }
+
+// Accounts found by username case insensitively
+type fakeAccount struct {
+	repository.Account
+	byName map[string]*object.Account
+}
+
+func (r fakeAccount) FindByID(_ context.Context, id object.AccountID) (*object.Account, error) {
+	return &object.Account{ID: id}, nil
+}
+
+func (r fakeAccount) FindByUsername(_ context.Context, username string) (*object.Account, error) {
+	return r.byName[strings.ToLower(username)], nil
+}
+
+// Schedule shared by instances, publishing each entry once
+type fakeScheduledStatus struct {
+	repository.ScheduledStatus
+	due       []*object.ScheduledStatus
+	published map[object.ScheduledStatusID]bool
+	nextID    object.StatusID
+	// Polls stored with the statuses published
+	polls map[object.StatusID]*object.PollParams
+}
+
+func (r *fakeScheduledStatus) SelectDue(_ context.Context, _ time.Time, _ int64) ([]*object.ScheduledStatus, error) {
+	return r.due, nil
+}
+
+func (r *fakeScheduledStatus) Publish(_ context.Context, id object.ScheduledStatusID, _ time.Time, extras object.StatusExtras) (object.StatusID, error) {
+	if r.published[id] {
+		return 0, nil
+	}
+	r.published[id] = true
+	r.nextID++
+	if extras.Poll != nil {
+		r.polls[r.nextID] = extras.Poll
+	}
+	return r.nextID, nil
+}
+
+type fakeAnnouncer struct {
+	announced []object.StatusID
+}
+
+func (a *fakeAnnouncer) Announce(_ context.Context, _ *object.Account, id object.StatusID) error {
+	a.announced = append(a.announced, id)
+	return nil
+}
+
+func TestScheduler_PublishDue(t *testing.T) {
+	poll := &object.PollParams{Options: []string{"a", "b"}, ExpiresIn: 300}
+	scheduled := &fakeScheduledStatus{
+		due: []*object.ScheduledStatus{
+			{ID: 1, Params: object.StatusParams{Text: "one"}},
+			{ID: 2, Params: object.StatusParams{Text: "two", Poll: poll}},
+			{ID: 3, Params: object.StatusParams{Text: "three", Poll: poll}},
+		},
+		published: map[object.ScheduledStatusID]bool{3: true},
+		polls:     make(map[object.StatusID]*object.PollParams),
+	}
+	d := &fakeDao{scheduled: scheduled}
+	announcer := &fakeAnnouncer{}
+
+	/* 複数インスタンスが同時に動いても一度だけ公開される */
+	first := NewScheduler(d, announcer)
+	second := NewScheduler(d, announcer)
+
+	got, err := first.PublishDue(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(got, []object.StatusID{1, 2}); diff != "" {
+		t.Errorf("PublishDue() returned diff (want -> got):\n%s", diff)
+	}
+
+	got, err = second.PublishDue(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(got, []object.StatusID{}); diff != "" {
+		t.Errorf("PublishDue() returned diff (want -> got):\n%s", diff)
+	}
+
+	if diff := cmp.Diff(scheduled.polls, map[object.StatusID]*object.PollParams{2: poll}); diff != "" {
+		t.Errorf("polls of published statuses returned diff (want -> got):\n%s", diff)
+	}
+	if diff := cmp.Diff(announcer.announced, []object.StatusID{1, 2}); diff != "" {
+		t.Errorf("announced statuses returned diff (want -> got):\n%s", diff)
+	}
+}
+
+func TestCheckSchedule(t *testing.T) {
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		scheduledAt time.Time
+		wantErr     bool
+	}{
+		{name: "past", scheduledAt: now.Add(-time.Minute), wantErr: true},
+		{name: "too soon", scheduledAt: now.Add(MinScheduleDelay - time.Second), wantErr: true},
+		{name: "ok", scheduledAt: now.Add(MinScheduleDelay), wantErr: false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			if err := CheckSchedule(tt.scheduledAt, now); (err != nil) != tt.wantErr {
+				t.Errorf("CheckSchedule() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,241 @@
+package search
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/satorunooshie/Yatter/app/domain/content"
+	"github.com/satorunooshie/Yatter/app/domain/object"
+	"github.com/satorunooshie/Yatter/app/domain/repository"
+)
+
+// Search keeping the entities in memory, for tests.
+//
+// Terms match as case-insensitive substrings as the ngram parser does with the default collation,
+// except that terms shorter than ngram_token_size match as well.
+type Memory struct {
+	mu       sync.RWMutex
+	accounts map[object.AccountID]*object.Account
+	statuses map[object.StatusID]*object.Status
+	tags     map[object.TagID]*object.Tag
+	follows  map[[2]object.AccountID]bool
+}
+
+var _ repository.Search = (*Memory)(nil)
+
+// Create empty Memory
+func NewMemory() *Memory {
+	return &Memory{
+		accounts: make(map[object.AccountID]*object.Account),
+		statuses: make(map[object.StatusID]*object.Status),
+		tags:     make(map[object.TagID]*object.Tag),
+		follows:  make(map[[2]object.AccountID]bool),
+	}
+}
+
+// Add or replace the account
+func (m *Memory) AddAccount(account *object.Account) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.accounts[account.ID] = account
+}
+
+// Add or replace the status, whose MediaAttachment and Mentions are searched as well
+func (m *Memory) AddStatus(status *object.Status) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.statuses[status.ID] = status
+}
+
+// Add or replace the tag
+func (m *Memory) AddTag(tag *object.Tag) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tags[tag.ID] = tag
+}
+
+// Let the follower read the followers-only statuses of the followee
+func (m *Memory) Follow(followerID, followeeID object.AccountID) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.follows[[2]object.AccountID{followerID, followeeID}] = true
+}
+
+func (m *Memory) Statuses(_ context.Context, query object.SearchQuery, viewerID object.AccountID, page object.Page) ([]*object.Status, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var viewer *object.Account
+	if viewerID != 0 {
+		viewer = &object.Account{ID: viewerID}
+	}
+
+	ids := make([]int64, 0)
+	for id, s := range m.statuses {
+		if s.DeleteAt != nil || !containsAll(query.Terms, s.Text) {
+			continue
+		}
+		following := m.follows[[2]object.AccountID{viewerID, s.AccountID}]
+		if !s.IsVisibleTo(viewer, following, viewer != nil && s.IsMentioned(viewerID)) {
+			continue
+		}
+		if query.FromUsername != "" {
+			a, ok := m.accounts[s.AccountID]
+			if !ok || a.Username != query.FromUsername || a.Domain != query.FromDomain {
+				continue
+			}
+		}
+		if query.HasMedia && !hasMedia(s) {
+			continue
+		}
+		ids = append(ids, id)
+	}
+
+	entities := make([]*object.Status, 0, page.Limit)
+	for _, id := range selectPage(ids, page) {
+		entity := *m.statuses[id]
+		entities = append(entities, &entity)
+	}
+	return entities, nil
+}
+
+func (m *Memory) Accounts(_ context.Context, query object.SearchQuery, page object.Page) ([]*object.Account, error) {
+	if len(query.Terms) == 0 {
+		return nil, nil
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	ids := make([]int64, 0)
+	for id, a := range m.accounts {
+		if a.DeleteAt != nil || a.SuspendAt != nil {
+			continue
+		}
+		text := a.Username
+		if a.DisplayName != nil {
+			text += "\n" + *a.DisplayName
+		}
+		if containsAll(query.Terms, text) {
+			ids = append(ids, id)
+		}
+	}
+
+	entities := make([]*object.Account, 0, page.Limit)
+	for _, id := range selectPage(ids, page) {
+		entity := *m.accounts[id]
+		entities = append(entities, &entity)
+	}
+	return entities, nil
+}
+
+func (m *Memory) Hashtags(_ context.Context, query object.SearchQuery, page object.Page) ([]*object.Tag, error) {
+	if len(query.Terms) == 0 {
+		return nil, nil
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	prefix := content.NormalizeTag(query.Terms[0])
+	ids := make([]int64, 0)
+	for id, t := range m.tags {
+		if strings.HasPrefix(t.Name, prefix) {
+			ids = append(ids, id)
+		}
+	}
+
+	entities := make([]*object.Tag, 0, page.Limit)
+	for _, id := range selectPage(ids, page) {
+		entity := *m.tags[id]
+		entity.SetURL()
+		entities = append(entities, &entity)
+	}
+	return entities, nil
+}
+
+func (m *Memory) AccountsByPrefix(_ context.Context, prefix string, viewerID object.AccountID, limit int64) ([]*object.Account, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	entities := make([]*object.Account, 0)
+	for _, a := range m.accounts {
+		if a.DeleteAt != nil || a.SuspendAt != nil {
+			continue
+		}
+		if hasPrefixFold(a.Username, prefix) || a.DisplayName != nil && hasPrefixFold(*a.DisplayName, prefix) {
+			entity := *a
+			entities = append(entities, &entity)
+		}
+	}
+
+	sort.Slice(entities, func(i, j int) bool {
+		fi := m.follows[[2]object.AccountID{viewerID, entities[i].ID}]
+		fj := m.follows[[2]object.AccountID{viewerID, entities[j].ID}]
+		if fi != fj {
+			return fi
+		}
+		if entities[i].Username != entities[j].Username {
+			return entities[i].Username < entities[j].Username
+		}
+		return entities[i].ID < entities[j].ID
+	})
+	if int64(len(entities)) > limit {
+		entities = entities[:limit]
+	}
+	return entities, nil
+}
+
+func containsAll(terms []string, text string) bool {
+	text = strings.ToLower(text)
+	for _, term := range terms {
+		if !strings.Contains(text, strings.ToLower(term)) {
+			return false
+		}
+	}
+	return true
+}
+
+// Check the prefix as LIKE does with the default collation
+func hasPrefixFold(s, prefix string) bool {
+	return strings.HasPrefix(strings.ToLower(s), strings.ToLower(prefix))
+}
+
+func hasMedia(status *object.Status) bool {
+	for _, m := range status.MediaAttachment {
+		if m.DeleteAt == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// Take the IDs of the page, newest first as the MySQL implementation does
+func selectPage(ids []int64, page object.Page) []int64 {
+	sort.Slice(ids, func(i, j int) bool {
+		if page.Ascending {
+			return ids[i] < ids[j]
+		}
+		return ids[i] > ids[j]
+	})
+
+	selected := make([]int64, 0, page.Limit)
+	for _, id := range ids {
+		if int64(len(selected)) == page.Limit {
+			break
+		}
+		if id > page.MinID && id < page.MaxID {
+			selected = append(selected, id)
+		}
+	}
+
+	/* 昇順に取ったページも新しい順に並べる */
+	if page.Ascending {
+		for i, j := 0, len(selected)-1; i < j; i, j = i+1, j-1 {
+			selected[i], selected[j] = selected[j], selected[i]
+		}
+	}
+	return selected
+}
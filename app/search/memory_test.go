@@ -0,0 +1,263 @@
+package search
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/satorunooshie/Yatter/app/domain/object"
+)
+
+func newTestMemory() *Memory {
+	var (
+		now         = object.DateTime{}
+		displayName = "ジョン"
+	)
+	m := NewMemory()
+	m.AddAccount(&object.Account{ID: 1, Username: "john", DisplayName: &displayName})
+	m.AddAccount(&object.Account{ID: 2, Username: "jane", Domain: "remote.example"})
+	m.AddAccount(&object.Account{ID: 3, Username: "johnny", SuspendAt: &now})
+	m.AddAccount(&object.Account{ID: 4, Username: "viewer"})
+	m.Follow(4, 2)
+
+	m.AddStatus(&object.Status{ID: 10, AccountID: 1, Text: "寿司を食べた", Visibility: object.VisibilityPublic})
+	m.AddStatus(&object.Status{ID: 11, AccountID: 1, Text: "寿司の写真", Visibility: object.VisibilityUnlisted,
+		MediaAttachment: []*object.MediaAttachment{{ID: 1, StatusID: 11}}})
+	m.AddStatus(&object.Status{ID: 12, AccountID: 1, Text: "消した寿司", Visibility: object.VisibilityPublic, DeleteAt: &now})
+	m.AddStatus(&object.Status{ID: 13, AccountID: 2, Text: "フォロワー限定の寿司", Visibility: object.VisibilityPrivate})
+	m.AddStatus(&object.Status{ID: 14, AccountID: 1, Text: "寿司 @viewer", Visibility: object.VisibilityDirect,
+		Mentions: []*object.Mention{{AccountID: 4}}})
+	m.AddStatus(&object.Status{ID: 15, AccountID: 1, Text: "秘密の寿司", Visibility: object.VisibilityDirect})
+	m.AddStatus(&object.Status{ID: 16, AccountID: 1, Text: "削除された写真の寿司", Visibility: object.VisibilityPublic,
+		MediaAttachment: []*object.MediaAttachment{{ID: 2, StatusID: 16, DeleteAt: &now}}})
+	m.AddStatus(&object.Status{ID: 17, AccountID: 1, Text: "Sushi Time", Visibility: object.VisibilityPublic})
+
+	m.AddTag(&object.Tag{ID: 1, Name: "golang"})
+	m.AddTag(&object.Tag{ID: 2, Name: "gopher"})
+	m.AddTag(&object.Tag{ID: 3, Name: "rust"})
+	return m
+}
+
+func TestMemory_Statuses(t *testing.T) {
+	m := newTestMemory()
+
+	tests := []struct {
+		name     string
+		q        string
+		viewerID object.AccountID
+		page     object.Page
+		want     []object.StatusID
+	}{
+		{
+			name: "anonymous",
+			q:    "寿司",
+			page: object.NewestPage(20),
+			want: []object.StatusID{16, 11, 10},
+		},
+		{
+			name:     "follower and mentioned",
+			q:        "寿司",
+			viewerID: 4,
+			page:     object.NewestPage(20),
+			want:     []object.StatusID{16, 14, 13, 11, 10},
+		},
+		{
+			name:     "author",
+			q:        "寿司",
+			viewerID: 1,
+			page:     object.NewestPage(20),
+			want:     []object.StatusID{16, 15, 14, 11, 10},
+		},
+		{
+			name: "all terms",
+			q:    "寿司 写真",
+			page: object.NewestPage(20),
+			want: []object.StatusID{16, 11},
+		},
+		{
+			name: "case insensitive",
+			q:    "SUSHI time",
+			page: object.NewestPage(20),
+			want: []object.StatusID{17},
+		},
+		{
+			name: "has:media",
+			q:    "寿司 has:media",
+			page: object.NewestPage(20),
+			want: []object.StatusID{11},
+		},
+		{
+			name:     "from remote account",
+			q:        "from:jane@remote.example",
+			viewerID: 4,
+			page:     object.NewestPage(20),
+			want:     []object.StatusID{13},
+		},
+		{
+			name: "from other account",
+			q:    "寿司 from:jane",
+			page: object.NewestPage(20),
+			want: []object.StatusID{},
+		},
+		{
+			name: "older page",
+			q:    "寿司",
+			page: object.Page{MinID: 0, MaxID: 16, Limit: 1},
+			want: []object.StatusID{11},
+		},
+		{
+			name: "newer page",
+			q:    "寿司",
+			page: object.Page{MinID: 10, MaxID: math.MaxInt64, Limit: 1, Ascending: true},
+			want: []object.StatusID{11},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			statuses, err := m.Statuses(context.Background(), object.ParseSearchQuery(tt.q), tt.viewerID, tt.page)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := make([]object.StatusID, 0, len(statuses))
+			for _, v := range statuses {
+				got = append(got, v.ID)
+			}
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Errorf("Statuses() returned diff (want -> got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMemory_Accounts(t *testing.T) {
+	m := newTestMemory()
+
+	tests := []struct {
+		name string
+		q    string
+		want []object.AccountID
+	}{
+		{
+			name: "username",
+			q:    "jo",
+			want: []object.AccountID{1},
+		},
+		{
+			name: "case insensitive",
+			q:    "JOHN",
+			want: []object.AccountID{1},
+		},
+		{
+			name: "display name",
+			q:    "ジョン",
+			want: []object.AccountID{1},
+		},
+		{
+			name: "across username and display name",
+			q:    "ja ジョン",
+			want: []object.AccountID{},
+		},
+		{
+			name: "no terms",
+			q:    "has:media",
+			want: []object.AccountID{},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			accounts, err := m.Accounts(context.Background(), object.ParseSearchQuery(tt.q), object.NewestPage(20))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := make([]object.AccountID, 0, len(accounts))
+			for _, v := range accounts {
+				got = append(got, v.ID)
+			}
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Errorf("Accounts() returned diff (want -> got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMemory_Hashtags(t *testing.T) {
+	m := newTestMemory()
+
+	tags, err := m.Hashtags(context.Background(), object.ParseSearchQuery("#Go"), object.NewestPage(20))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*object.Tag{
+		{ID: 2, Name: "gopher", URL: "/v1/timelines/tag/gopher"},
+		{ID: 1, Name: "golang", URL: "/v1/timelines/tag/golang"},
+	}
+	if diff := cmp.Diff(tags, want); diff != "" {
+		t.Errorf("Hashtags() returned diff (want -> got):\n%s", diff)
+	}
+}
+
+func TestMemory_AccountsByPrefix(t *testing.T) {
+	m := newTestMemory()
+	m.AddAccount(&object.Account{ID: 5, Username: "joe"})
+	m.Follow(1, 5)
+
+	tests := []struct {
+		name     string
+		prefix   string
+		viewerID object.AccountID
+		limit    int64
+		want     []object.AccountID
+	}{
+		{
+			name:   "username",
+			prefix: "j",
+			limit:  40,
+			want:   []object.AccountID{2, 5, 1},
+		},
+		{
+			name:   "display name",
+			prefix: "ジョ",
+			limit:  40,
+			want:   []object.AccountID{1},
+		},
+		{
+			name:     "followed first",
+			prefix:   "j",
+			viewerID: 1,
+			limit:    2,
+			want:     []object.AccountID{5, 2},
+		},
+		{
+			name:   "case insensitive",
+			prefix: "JO",
+			limit:  40,
+			want:   []object.AccountID{5, 1},
+		},
+		{
+			name:   "not in the middle",
+			prefix: "ohn",
+			limit:  40,
+			want:   []object.AccountID{},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			accounts, err := m.AccountsByPrefix(context.Background(), tt.prefix, tt.viewerID, tt.limit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := make([]object.AccountID, 0, len(accounts))
+			for _, v := range accounts {
+				got = append(got, v.ID)
+			}
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Errorf("AccountsByPrefix() returned diff (want -> got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package snowflake
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+/*
+ * ID は上位から 41 bit のエポックからのミリ秒、10 bit のノード、12 bit のシーケンス
+ * 符号ビットは使わないので正の int64 に収まる
+ */
+const (
+	nodeBits     = 10
+	sequenceBits = 12
+
+	// Largest node number
+	MaxNode = 1<<nodeBits - 1
+
+	maxSequence = 1<<sequenceBits - 1
+	timeShift   = nodeBits + sequenceBits
+)
+
+// Time IDs count milliseconds from
+var Epoch = time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// Generator of 64-bit IDs ordered by the time they are generated at
+type Generator struct {
+	node int64
+	now  func() time.Time
+
+	mu       sync.Mutex
+	last     int64
+	sequence int64
+}
+
+// Create Generator for the node, which must be unique among the servers sharing the database
+func New(node int64) (*Generator, error) {
+	if node < 0 || node > MaxNode {
+		return nil, fmt.Errorf("node must be between 0 and %d: %d", MaxNode, node)
+	}
+	return &Generator{node: node, now: time.Now}, nil
+}
+
+// Generate a new ID greater than any generated before by the generator
+func (g *Generator) Next() int64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	/* 時計が戻っても最後に使ったミリ秒から進める */
+	ms := millis(g.now())
+	if ms > g.last {
+		g.last, g.sequence = ms, 0
+	} else if g.sequence < maxSequence {
+		g.sequence++
+	} else {
+		/* 同じミリ秒のシーケンスを使い切ったら次のミリ秒を先取りする */
+		g.last, g.sequence = g.last+1, 0
+	}
+	return g.last<<timeShift | g.node<<sequenceBits | g.sequence
+}
+
+// Smallest ID generated at the time or later, which is used as a bound of IDs to page by time
+func FromTime(t time.Time) int64 {
+	ms := millis(t)
+	if ms < 0 {
+		return 0
+	}
+	return ms << timeShift
+}
+
+// Time the ID was generated at, in milliseconds
+func TimeOf(id int64) time.Time {
+	return Epoch.Add(time.Duration(id>>timeShift) * time.Millisecond)
+}
+
+func millis(t time.Time) int64 {
+	return t.Sub(Epoch).Milliseconds()
+}
@@ -0,0 +1,102 @@
+package snowflake
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGenerator_Next(t *testing.T) {
+	now := Epoch.Add(time.Second)
+	g, err := New(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.now = func() time.Time { return now }
+
+	first := g.Next()
+	if diff := cmp.Diff(first, int64(1000<<22|3<<12)); diff != "" {
+		t.Errorf("Next() returned diff (want -> got):\n%s", diff)
+	}
+	if diff := cmp.Diff(g.Next(), first+1); diff != "" {
+		t.Errorf("Next() in the same millisecond returned diff (want -> got):\n%s", diff)
+	}
+
+	/* 時計が戻っても ID は増え続ける */
+	now = now.Add(-time.Minute)
+	if got := g.Next(); got <= first+1 {
+		t.Errorf("Next() after the clock went back = %d, want greater than %d", got, first+1)
+	}
+
+	/* シーケンスを使い切ったら次のミリ秒に進む */
+	g.sequence = maxSequence
+	got := g.Next()
+	if diff := cmp.Diff(TimeOf(got), Epoch.Add(time.Second+time.Millisecond)); diff != "" {
+		t.Errorf("TimeOf(Next()) after the sequence ran out returned diff (want -> got):\n%s", diff)
+	}
+}
+
+func TestGenerator_Next_concurrent(t *testing.T) {
+	g, err := New(MaxNode)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		wg  sync.WaitGroup
+		mu  sync.Mutex
+		ids = make(map[int64]bool)
+	)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			prev := int64(0)
+			for j := 0; j < 10000; j++ {
+				id := g.Next()
+				if id <= prev {
+					t.Errorf("Next() = %d, want greater than %d", id, prev)
+				}
+				prev = id
+				mu.Lock()
+				ids[id] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(ids) != 80000 {
+		t.Errorf("Next() generated %d unique IDs, want 80000", len(ids))
+	}
+}
+
+func TestNew(t *testing.T) {
+	for _, node := range []int64{-1, MaxNode + 1} {
+		if _, err := New(node); err == nil {
+			t.Errorf("New(%d) returned no error", node)
+		}
+	}
+}
+
+func TestFromTime(t *testing.T) {
+	g, err := New(MaxNode)
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := Epoch.Add(42 * time.Hour)
+	g.now = func() time.Time { return at }
+
+	id := g.Next()
+	if bound := FromTime(at); bound > id || FromTime(at.Add(time.Millisecond)) <= id {
+		t.Errorf("FromTime() does not bound %d: %d", id, bound)
+	}
+	if diff := cmp.Diff(TimeOf(id), at); diff != "" {
+		t.Errorf("TimeOf() returned diff (want -> got):\n%s", diff)
+	}
+	if diff := cmp.Diff(FromTime(Epoch.Add(-time.Hour)), int64(0)); diff != "" {
+		t.Errorf("FromTime() before Epoch returned diff (want -> got):\n%s", diff)
+	}
+}
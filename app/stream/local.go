@@ -0,0 +1,112 @@
+package stream
+
+import (
+	"context"
+	"sync"
+)
+
+// Bus delivering events within the process
+type LocalBus struct {
+	buffer int
+
+	mu     sync.RWMutex
+	topics map[Topic]map[*localSubscription]struct{}
+}
+
+var _ Bus = (*LocalBus)(nil)
+
+// Create LocalBus queueing up to buffer events for each subscriber
+func NewLocalBus(buffer int) *LocalBus {
+	return &LocalBus{
+		buffer: buffer,
+		topics: make(map[Topic]map[*localSubscription]struct{}),
+	}
+}
+
+// Deliver the event without blocking, dropping subscribers whose queue is full
+func (b *LocalBus) Publish(_ context.Context, event Event, topics ...Topic) error {
+	b.mu.RLock()
+	delivered := make(map[*localSubscription]struct{})
+	lagging := make([]*localSubscription, 0)
+	for _, topic := range topics {
+		for s := range b.topics[topic] {
+			if _, ok := delivered[s]; ok {
+				continue
+			}
+			delivered[s] = struct{}{}
+			select {
+			case s.events <- event:
+			default:
+				lagging = append(lagging, s)
+			}
+		}
+	}
+	b.mu.RUnlock()
+
+	/* 追いつけない購読者は切断する */
+	for _, s := range lagging {
+		b.remove(s, true)
+	}
+	return nil
+}
+
+func (b *LocalBus) Subscribe(_ context.Context, topics ...Topic) (Subscription, error) {
+	s := &localSubscription{
+		bus:    b,
+		topics: topics,
+		events: make(chan Event, b.buffer),
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, topic := range topics {
+		if b.topics[topic] == nil {
+			b.topics[topic] = make(map[*localSubscription]struct{})
+		}
+		b.topics[topic][s] = struct{}{}
+	}
+	return s, nil
+}
+
+// Unsubscribe and close the channel unless done already
+func (b *LocalBus) remove(s *localSubscription, overflowed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if s.closed {
+		return
+	}
+	s.closed = true
+	s.overflowed = overflowed
+	for _, topic := range s.topics {
+		delete(b.topics[topic], s)
+		if len(b.topics[topic]) == 0 {
+			delete(b.topics, topic)
+		}
+	}
+	close(s.events)
+}
+
+type localSubscription struct {
+	bus    *LocalBus
+	topics []Topic
+	events chan Event
+
+	// guarded by bus.mu
+	closed     bool
+	overflowed bool
+}
+
+func (s *localSubscription) Events() <-chan Event {
+	return s.events
+}
+
+func (s *localSubscription) Overflowed() bool {
+	s.bus.mu.RLock()
+	defer s.bus.mu.RUnlock()
+	return s.overflowed
+}
+
+func (s *localSubscription) Close() {
+	s.bus.remove(s, false)
+}
@@ -0,0 +1,101 @@
+package stream
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/satorunooshie/Yatter/app/domain/object"
+)
+
+func TestLocalBus_Publish(t *testing.T) {
+	ctx := context.Background()
+	bus := NewLocalBus(1)
+
+	public, _ := bus.Subscribe(ctx, TopicPublic)
+	tagged, _ := bus.Subscribe(ctx, TopicPublic, TopicHashtag("Go"))
+	other, _ := bus.Subscribe(ctx, TopicNotification(1))
+	defer public.Close()
+	defer tagged.Close()
+	defer other.Close()
+
+	/* 複数のトピックに購読していても一度だけ届く */
+	event := Event{Type: EventDelete, StatusID: 1}
+	if err := bus.Publish(ctx, event, TopicPublic, TopicHashtag("go")); err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff(<-public.Events(), event); diff != "" {
+		t.Errorf("public received diff (want -> got):\n%s", diff)
+	}
+	if diff := cmp.Diff(<-tagged.Events(), event); diff != "" {
+		t.Errorf("tagged received diff (want -> got):\n%s", diff)
+	}
+	select {
+	case e := <-tagged.Events():
+		t.Errorf("tagged received twice: %v", e)
+	case e := <-other.Events():
+		t.Errorf("other received: %v", e)
+	default:
+	}
+}
+
+func TestLocalBus_Overflow(t *testing.T) {
+	ctx := context.Background()
+	bus := NewLocalBus(1)
+
+	slow, _ := bus.Subscribe(ctx, TopicPublic)
+	fast, _ := bus.Subscribe(ctx, TopicPublic)
+	defer fast.Close()
+
+	for i := object.StatusID(1); i <= 2; i++ {
+		if err := bus.Publish(ctx, Event{Type: EventDelete, StatusID: i}, TopicPublic); err != nil {
+			t.Fatal(err)
+		}
+		<-fast.Events()
+	}
+
+	/* 溢れた購読者はキューを読み切ったあとチャネルが閉じる */
+	if e := <-slow.Events(); e.StatusID != 1 {
+		t.Errorf("slow received %d, want 1", e.StatusID)
+	}
+	if _, ok := <-slow.Events(); ok {
+		t.Error("slow channel is not closed")
+	}
+	if !slow.Overflowed() {
+		t.Error("slow is not overflowed")
+	}
+	if fast.Overflowed() {
+		t.Error("fast is overflowed")
+	}
+	slow.Close()
+}
+
+func TestStatusTopics(t *testing.T) {
+	tags := []*object.Tag{{Name: "Go"}}
+	tests := []struct {
+		name   string
+		status *object.Status
+		want   []Topic
+	}{
+		{
+			name:   "public",
+			status: &object.Status{Visibility: object.VisibilityPublic, Tags: tags},
+			want:   []Topic{TopicStatuses, TopicPublic, "hashtag:go"},
+		},
+		{
+			name:   "private",
+			status: &object.Status{Visibility: object.VisibilityPrivate, Tags: tags},
+			want:   []Topic{TopicStatuses},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := cmp.Diff(StatusTopics(tt.status), tt.want); diff != "" {
+				t.Errorf("StatusTopics() returned diff (want -> got):\n%s", diff)
+			}
+		})
+	}
+}
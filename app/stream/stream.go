@@ -0,0 +1,82 @@
+package stream
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/satorunooshie/Yatter/app/domain/object"
+)
+
+const (
+	// New or updated status, carrying Event.Status
+	EventUpdate EventType = "update"
+	// Deleted status, carrying Event.StatusID and Event.Status for subscribers to filter by viewer
+	EventDelete EventType = "delete"
+	// New notification, carrying Event.Notification
+	EventNotification EventType = "notification"
+)
+
+const (
+	// Public statuses
+	TopicPublic Topic = "public"
+	// Every status regardless of visibility, left to subscribers to filter by viewer
+	TopicStatuses Topic = "statuses"
+)
+
+type (
+	// Name of a stream events are published to
+	Topic string
+
+	EventType string
+
+	// Something that happened, delivered to subscribers as it is
+	Event struct {
+		Type         EventType
+		Status       *object.Status
+		StatusID     object.StatusID
+		Notification *object.Notification
+	}
+
+	// Fan-out of events from publishers to subscribers
+	//
+	// The in-process implementation is LocalBus; one backed by a broker lets instances share events.
+	Bus interface {
+		// Deliver the event to the subscribers of any of the topics, once for each subscriber
+		Publish(ctx context.Context, event Event, topics ...Topic) error
+		// Start receiving events published to any of the topics
+		Subscribe(ctx context.Context, topics ...Topic) (Subscription, error)
+	}
+
+	Subscription interface {
+		// Channel of events, closed when the subscription is closed or dropped for falling behind
+		Events() <-chan Event
+		// Whether the subscription was dropped because the subscriber could not keep up
+		Overflowed() bool
+		Close()
+	}
+)
+
+// Public statuses tagged with the name
+func TopicHashtag(name string) Topic {
+	return Topic("hashtag:" + strings.ToLower(name))
+}
+
+// Notifications of the account
+func TopicNotification(accountID object.AccountID) Topic {
+	return Topic("notification:" + strconv.FormatInt(accountID, 10))
+}
+
+// Topics a status is published to; status.Tags must be loaded beforehand
+func StatusTopics(status *object.Status) []Topic {
+	topics := []Topic{TopicStatuses}
+	if status.Visibility != object.VisibilityPublic {
+		return topics
+	}
+
+	topics = append(topics, TopicPublic)
+	for _, t := range status.Tags {
+		topics = append(topics, TopicHashtag(t.Name))
+	}
+	return topics
+}
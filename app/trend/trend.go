@@ -0,0 +1,184 @@
+package trend
+
+import (
+	"context"
+	"log"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/satorunooshie/Yatter/app/domain/object"
+	"github.com/satorunooshie/Yatter/app/domain/repository"
+)
+
+// Maximum number of trending tags and statuses kept in memory
+const maxTrends = 100
+
+// Trending tags and statuses, refreshed in background
+type Trends struct {
+	tagRepo  repository.Tag
+	window   time.Duration
+	halfLife time.Duration
+	now      func() time.Time
+
+	mu       sync.RWMutex
+	tags     []*object.TrendTag
+	statuses []object.StatusID
+}
+
+// Create Trends which counts tag usages within window, halving their weights every halfLife
+func New(tagRepo repository.Tag, window, halfLife time.Duration) *Trends {
+	return &Trends{
+		tagRepo:  tagRepo,
+		window:   window,
+		halfLife: halfLife,
+		now:      time.Now,
+	}
+}
+
+// Refresh trends every interval until ctx is done
+func (t *Trends) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := t.Refresh(ctx); err != nil {
+			log.Printf("[WARN] trend::Run::Refresh(): %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Recompute trends from recent tag usages
+func (t *Trends) Refresh(ctx context.Context) error {
+	now := t.now()
+	usages, err := t.tagRepo.SelectUsages(ctx, now.Add(-t.window))
+	if err != nil {
+		return err
+	}
+
+	tags, statuses := Compute(usages, now, t.halfLife)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.tags, t.statuses = tags, statuses
+	return nil
+}
+
+// Read at most limit trending tags, highest score first
+func (t *Trends) Tags(limit int) []*object.TrendTag {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if limit > len(t.tags) {
+		limit = len(t.tags)
+	}
+	return append([]*object.TrendTag{}, t.tags[:limit]...)
+}
+
+// Read at most limit trending status IDs, highest score first
+func (t *Trends) Statuses(limit int) []object.StatusID {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if limit > len(t.statuses) {
+		limit = len(t.statuses)
+	}
+	return append([]object.StatusID{}, t.statuses[:limit]...)
+}
+
+// Compute trending tags and statuses from tag usages.
+//
+// Each use weighs 1 when made at now and halves every halfLife.
+// A tag scores the sum of the latest use by each account, so that one account repeating a tag can't make it trend.
+// Until statuses can be favourited or reblogged, a status scores its own weight times the scores of its tags.
+func Compute(usages []*object.TagUsage, now time.Time, halfLife time.Duration) ([]*object.TrendTag, []object.StatusID) {
+	type key struct {
+		tagID     object.TagID
+		accountID object.AccountID
+	}
+	latest := make(map[key]float64)
+	tagMap := make(map[object.TagID]*object.TrendTag)
+	statusWeight := make(map[object.StatusID]float64)
+	statusTags := make(map[object.StatusID][]object.TagID)
+
+	for _, u := range usages {
+		w := weight(now.Sub(u.CreateAt.Time), halfLife)
+
+		tag, ok := tagMap[u.TagID]
+		if !ok {
+			tag = &object.TrendTag{Name: u.Name}
+			tagMap[u.TagID] = tag
+		}
+		tag.Uses++
+
+		k := key{tagID: u.TagID, accountID: u.AccountID}
+		if prev, ok := latest[k]; !ok {
+			tag.Accounts++
+			latest[k] = w
+		} else if w > prev {
+			latest[k] = w
+		}
+
+		statusWeight[u.StatusID] = w
+		statusTags[u.StatusID] = append(statusTags[u.StatusID], u.TagID)
+	}
+
+	for k, w := range latest {
+		tagMap[k.tagID].Score += w
+	}
+
+	tags := make([]*object.TrendTag, 0, len(tagMap))
+	for _, tag := range tagMap {
+		t := &object.Tag{Name: tag.Name}
+		t.SetURL()
+		tag.URL = t.URL
+		tags = append(tags, tag)
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if tags[i].Score != tags[j].Score {
+			return tags[i].Score > tags[j].Score
+		}
+		return tags[i].Name < tags[j].Name
+	})
+	if len(tags) > maxTrends {
+		tags = tags[:maxTrends]
+	}
+
+	tagScore := make(map[object.TagID]float64, len(tagMap))
+	for id, tag := range tagMap {
+		tagScore[id] = tag.Score
+	}
+	statusScore := make(map[object.StatusID]float64, len(statusWeight))
+	statuses := make([]object.StatusID, 0, len(statusWeight))
+	for id, w := range statusWeight {
+		var s float64
+		for _, tagID := range statusTags[id] {
+			s += tagScore[tagID]
+		}
+		statusScore[id] = w * s
+		statuses = append(statuses, id)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		si, sj := statusScore[statuses[i]], statusScore[statuses[j]]
+		if si != sj {
+			return si > sj
+		}
+		return statuses[i] > statuses[j]
+	})
+	if len(statuses) > maxTrends {
+		statuses = statuses[:maxTrends]
+	}
+
+	return tags, statuses
+}
+
+func weight(age, halfLife time.Duration) float64 {
+	if age < 0 {
+		age = 0
+	}
+	return math.Exp2(-float64(age) / float64(halfLife))
+}
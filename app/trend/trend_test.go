@@ -0,0 +1,134 @@
+package trend
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/satorunooshie/Yatter/app/domain/object"
+	"github.com/satorunooshie/Yatter/app/domain/repository"
+)
+
+func usage(tagID object.TagID, name string, statusID object.StatusID, accountID object.AccountID, createAt time.Time) *object.TagUsage {
+	return &object.TagUsage{
+		TagID:     tagID,
+		Name:      name,
+		StatusID:  statusID,
+		AccountID: accountID,
+		CreateAt:  object.DateTime{Time: createAt},
+	}
+}
+
+func TestCompute(t *testing.T) {
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	halfLife := time.Hour
+
+	tests := []struct {
+		name         string
+		usages       []*object.TagUsage
+		wantTags     []*object.TrendTag
+		wantStatuses []object.StatusID
+	}{
+		{
+			name:         "empty",
+			usages:       nil,
+			wantTags:     []*object.TrendTag{},
+			wantStatuses: []object.StatusID{},
+		},
+		{
+			name: "decayed",
+			usages: []*object.TagUsage{
+				usage(1, "old", 1, 1, now.Add(-2*time.Hour)),
+				usage(2, "new", 2, 2, now),
+			},
+			wantTags: []*object.TrendTag{
+				{Name: "new", URL: "/v1/timelines/tag/new", Score: 1, Uses: 1, Accounts: 1},
+				{Name: "old", URL: "/v1/timelines/tag/old", Score: 0.25, Uses: 1, Accounts: 1},
+			},
+			wantStatuses: []object.StatusID{2, 1},
+		},
+		{
+			name: "one account counts once",
+			usages: []*object.TagUsage{
+				usage(1, "spam", 1, 1, now),
+				usage(1, "spam", 2, 1, now),
+				usage(1, "spam", 3, 1, now),
+				usage(2, "popular", 4, 2, now.Add(-time.Hour)),
+				usage(2, "popular", 5, 3, now.Add(-time.Hour)),
+				usage(2, "popular", 6, 4, now.Add(-time.Hour)),
+			},
+			wantTags: []*object.TrendTag{
+				{Name: "popular", URL: "/v1/timelines/tag/popular", Score: 1.5, Uses: 3, Accounts: 3},
+				{Name: "spam", URL: "/v1/timelines/tag/spam", Score: 1, Uses: 3, Accounts: 1},
+			},
+			wantStatuses: []object.StatusID{3, 2, 1, 6, 5, 4},
+		},
+		{
+			name: "status with several tags",
+			usages: []*object.TagUsage{
+				usage(1, "a", 1, 1, now),
+				usage(2, "b", 1, 1, now),
+				usage(1, "a", 2, 2, now),
+			},
+			wantTags: []*object.TrendTag{
+				{Name: "a", URL: "/v1/timelines/tag/a", Score: 2, Uses: 2, Accounts: 2},
+				{Name: "b", URL: "/v1/timelines/tag/b", Score: 1, Uses: 1, Accounts: 1},
+			},
+			wantStatuses: []object.StatusID{1, 2},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tags, statuses := Compute(tt.usages, now, halfLife)
+			if diff := cmp.Diff(tags, tt.wantTags); diff != "" {
+				t.Errorf("Compute() tags returned diff (want -> got):\n%s", diff)
+			}
+			if diff := cmp.Diff(statuses, tt.wantStatuses); diff != "" {
+				t.Errorf("Compute() statuses returned diff (want -> got):\n%s", diff)
+			}
+		})
+	}
+}
+
+type tagRepo struct {
+	repository.Tag
+
+	since  time.Time
+	usages []*object.TagUsage
+}
+
+func (r *tagRepo) SelectUsages(_ context.Context, since time.Time) ([]*object.TagUsage, error) {
+	r.since = since
+	return r.usages, nil
+}
+
+func TestTrends_Refresh(t *testing.T) {
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	repo := &tagRepo{
+		usages: []*object.TagUsage{
+			usage(1, "a", 1, 1, now),
+			usage(2, "b", 2, 2, now.Add(-time.Hour)),
+		},
+	}
+	trends := New(repo, 24*time.Hour, time.Hour)
+	trends.now = func() time.Time { return now }
+
+	if got := trends.Tags(10); len(got) != 0 {
+		t.Errorf("Trends.Tags() before Refresh() = %v, want empty", got)
+	}
+	if err := trends.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if want := now.Add(-24 * time.Hour); !repo.since.Equal(want) {
+		t.Errorf("SelectUsages() since = %v, want %v", repo.since, want)
+	}
+	if got := trends.Tags(1); len(got) != 1 || got[0].Name != "a" {
+		t.Errorf("Trends.Tags(1) = %v, want [a]", got)
+	}
+	if diff := cmp.Diff(trends.Statuses(10), []object.StatusID{1, 2}); diff != "" {
+		t.Errorf("Trends.Statuses() returned diff (want -> got):\n%s", diff)
+	}
+}
@@ -0,0 +1,169 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/satorunooshie/Yatter/app/dao"
+	"github.com/satorunooshie/Yatter/app/domain/object"
+)
+
+const (
+	// Maximum number of deliveries attempted at once
+	batchSize = 100
+	// Attempts of a delivery before giving up
+	maxAttempts = 8
+	// Wait before the first retry, doubled for each of the following ones
+	retryBase    = 30 * time.Second
+	maxRetryWait = time.Hour
+	// Failed attempts in a row which disable a webhook
+	disableAfter = 20
+	// Time allowed for a receiver to respond
+	requestTimeout = 10 * time.Second
+)
+
+// Delivers queued webhook events in background, retrying failed ones with exponential backoff
+type Deliverer struct {
+	dao    dao.Dao
+	client *http.Client
+	now    func() time.Time
+}
+
+// Create Deliverer
+func NewDeliverer(d dao.Dao) *Deliverer {
+	return &Deliverer{
+		dao:    d,
+		client: &http.Client{Timeout: requestTimeout},
+		now:    time.Now,
+	}
+}
+
+// Deliver due events every interval until ctx is done
+func (d *Deliverer) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := d.DeliverDue(ctx); err != nil {
+			log.Printf("[WARN] webhook::Run::DeliverDue(): %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Attempt deliveries whose next attempt is due.
+//
+// Each attempt is made by one instance even if several run, since only the one which claims it delivers it.
+func (d *Deliverer) DeliverDue(ctx context.Context) error {
+	now := d.now()
+	repo := d.dao.WebhookDelivery()
+
+	due, err := repo.SelectDue(ctx, now, batchSize)
+	if err != nil {
+		return err
+	}
+
+	webhooks := make(map[object.WebhookID]*object.Webhook)
+	for _, v := range due {
+		/* 応答がないまま落ちても、期限が過ぎれば再試行される */
+		claimed, err := repo.Claim(ctx, v.ID, v.Attempts, now.Add(2*requestTimeout))
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			continue
+		}
+		v.Attempts++
+
+		w, ok := webhooks[v.WebhookID]
+		if !ok {
+			w, err = d.dao.Webhook().FindByID(ctx, v.WebhookID)
+			if err != nil {
+				return err
+			}
+			webhooks[v.WebhookID] = w
+		}
+		if w == nil {
+			continue
+		}
+
+		if err := d.attempt(ctx, w, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Post the delivery to the webhook and record the result
+func (d *Deliverer) attempt(ctx context.Context, w *object.Webhook, v *object.WebhookDelivery) error {
+	repo := d.dao.WebhookDelivery()
+
+	status, err := d.post(ctx, w, v)
+	if err == nil {
+		return repo.Succeed(ctx, v.ID, w.ID, status)
+	}
+
+	var responseStatus *int
+	if status != 0 {
+		responseStatus = &status
+	}
+	var next *time.Time
+	if v.Attempts < maxAttempts {
+		t := d.now().Add(Backoff(v.Attempts))
+		next = &t
+	}
+	return repo.Fail(ctx, v.ID, w.ID, responseStatus, err.Error(), next, disableAfter)
+}
+
+// Post the delivery, returning the response status, or 0 if no response was received
+func (d *Deliverer) post(ctx context.Context, w *object.Webhook, v *object.WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(v.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Yatter-Webhook")
+	req.Header.Set(HeaderEvent, string(v.Event))
+	req.Header.Set(HeaderDelivery, strconv.FormatInt(v.ID, 10))
+	req.Header.Set(HeaderSignature, Sign(w.Secret, v.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		/* コネクションを再利用できるように読み切る */
+		_, _ = io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 1<<16))
+		if err := resp.Body.Close(); err != nil {
+			log.Printf("[WARN] webhook::post::resp.Body.Close(): %v", err)
+		}
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected response: %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// Wait after the attempts before the next one
+func Backoff(attempts int64) time.Duration {
+	wait := retryBase
+	for i := int64(1); i < attempts; i++ {
+		wait *= 2
+		if wait >= maxRetryWait {
+			return maxRetryWait
+		}
+	}
+	return wait
+}
@@ -0,0 +1,201 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/satorunooshie/Yatter/app/dao"
+	"github.com/satorunooshie/Yatter/app/domain/object"
+	"github.com/satorunooshie/Yatter/app/domain/repository"
+)
+
+type fakeDao struct {
+	dao.Dao
+	webhooks   *fakeWebhook
+	deliveries *fakeWebhookDelivery
+}
+
+func (d *fakeDao) Webhook() repository.Webhook                 { return d.webhooks }
+func (d *fakeDao) WebhookDelivery() repository.WebhookDelivery { return d.deliveries }
+
+type fakeWebhook struct {
+	repository.Webhook
+	webhooks []*object.Webhook
+}
+
+func (r *fakeWebhook) FindByID(_ context.Context, id object.WebhookID) (*object.Webhook, error) {
+	for _, v := range r.webhooks {
+		if v.ID == id {
+			return v, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeWebhook) SelectByEvent(_ context.Context, event object.WebhookEvent) ([]*object.Webhook, error) {
+	webhooks := make([]*object.Webhook, 0)
+	for _, v := range r.webhooks {
+		if v.Enabled && v.Events.Contains(event) {
+			webhooks = append(webhooks, v)
+		}
+	}
+	return webhooks, nil
+}
+
+// Result of an attempt recorded by Succeed or Fail
+type result struct {
+	ID             object.WebhookDeliveryID
+	ResponseStatus *int
+	NextAttemptAt  *time.Time
+	Succeeded      bool
+}
+
+type fakeWebhookDelivery struct {
+	repository.WebhookDelivery
+	due      []*object.WebhookDelivery
+	claimed  map[object.WebhookDeliveryID]bool
+	results  []result
+	inserted map[object.WebhookID][]byte
+}
+
+func (r *fakeWebhookDelivery) SelectDue(_ context.Context, _ time.Time, _ int64) ([]*object.WebhookDelivery, error) {
+	return r.due, nil
+}
+
+func (r *fakeWebhookDelivery) Claim(_ context.Context, id object.WebhookDeliveryID, _ int64, _ time.Time) (bool, error) {
+	if r.claimed[id] {
+		return false, nil
+	}
+	r.claimed[id] = true
+	return true, nil
+}
+
+func (r *fakeWebhookDelivery) Succeed(_ context.Context, id object.WebhookDeliveryID, _ object.WebhookID, responseStatus int) error {
+	r.results = append(r.results, result{ID: id, ResponseStatus: &responseStatus, Succeeded: true})
+	return nil
+}
+
+func (r *fakeWebhookDelivery) Fail(_ context.Context, id object.WebhookDeliveryID, _ object.WebhookID, responseStatus *int, _ string, nextAttemptAt *time.Time, _ int64) error {
+	r.results = append(r.results, result{ID: id, ResponseStatus: responseStatus, NextAttemptAt: nextAttemptAt})
+	return nil
+}
+
+func (r *fakeWebhookDelivery) Insert(_ context.Context, _ object.WebhookEvent, payload []byte, webhookIDs []object.WebhookID) error {
+	for _, id := range webhookIDs {
+		r.inserted[id] = payload
+	}
+	return nil
+}
+
+func TestDeliverer_DeliverDue(t *testing.T) {
+	now, _ := time.Parse("2006-01-02", "2020-01-01")
+	payload := []byte(`{"event":"status.created"}`)
+
+	/* 署名を検証し、/fail には 500 を返す受信側 */
+	received := make([]string, 0)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if got, want := r.Header.Get(HeaderSignature), Sign("secret", body); got != want {
+			t.Errorf("signature = %v, want %v", got, want)
+		}
+		if got := r.Header.Get(HeaderEvent); got != string(object.WebhookStatusCreated) {
+			t.Errorf("event = %v", got)
+		}
+		received = append(received, r.URL.Path+"#"+r.Header.Get(HeaderDelivery))
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer receiver.Close()
+
+	ok := &object.Webhook{ID: 1, URL: receiver.URL + "/ok", Secret: "secret", Enabled: true}
+	failing := &object.Webhook{ID: 2, URL: receiver.URL + "/fail", Secret: "secret", Enabled: true}
+	deliveries := &fakeWebhookDelivery{
+		due: []*object.WebhookDelivery{
+			{ID: 1, WebhookID: 1, Event: object.WebhookStatusCreated, Payload: payload},
+			{ID: 2, WebhookID: 2, Event: object.WebhookStatusCreated, Payload: payload, Attempts: 2},
+			{ID: 3, WebhookID: 2, Event: object.WebhookStatusCreated, Payload: payload, Attempts: maxAttempts - 1},
+			{ID: 4, WebhookID: 1, Event: object.WebhookStatusCreated, Payload: payload},
+		},
+		/* 他のインスタンスが試行中 */
+		claimed: map[object.WebhookDeliveryID]bool{4: true},
+	}
+	d := NewDeliverer(&fakeDao{webhooks: &fakeWebhook{webhooks: []*object.Webhook{ok, failing}}, deliveries: deliveries})
+	d.now = func() time.Time { return now }
+
+	if err := d.DeliverDue(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff(received, []string{"/ok#1", "/fail#2", "/fail#3"}); diff != "" {
+		t.Errorf("received returned diff (want -> got):\n%s", diff)
+	}
+
+	okStatus, failStatus := http.StatusOK, http.StatusInternalServerError
+	retryAt := now.Add(120 * time.Second)
+	want := []result{
+		{ID: 1, ResponseStatus: &okStatus, Succeeded: true},
+		{ID: 2, ResponseStatus: &failStatus, NextAttemptAt: &retryAt},
+		{ID: 3, ResponseStatus: &failStatus},
+	}
+	if diff := cmp.Diff(deliveries.results, want); diff != "" {
+		t.Errorf("results returned diff (want -> got):\n%s", diff)
+	}
+}
+
+func TestEnqueue(t *testing.T) {
+	webhooks := &fakeWebhook{
+		webhooks: []*object.Webhook{
+			{ID: 1, Events: object.WebhookEvents{object.WebhookAccountCreated}, Enabled: true},
+			{ID: 2, Events: object.WebhookEvents{object.WebhookAccountCreated, object.WebhookStatusCreated}, Enabled: true},
+			{ID: 3, Events: object.WebhookEvents{object.WebhookStatusCreated}, Enabled: false},
+		},
+	}
+	deliveries := &fakeWebhookDelivery{inserted: make(map[object.WebhookID][]byte)}
+	d := &fakeDao{webhooks: webhooks, deliveries: deliveries}
+
+	if err := Enqueue(context.Background(), d, object.WebhookStatusCreated, &object.Status{ID: 5}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(deliveries.inserted) != 1 || deliveries.inserted[2] == nil {
+		t.Fatalf("inserted for %v, want only 2", deliveries.inserted)
+	}
+	var got struct {
+		Event  object.WebhookEvent `json:"event"`
+		Object struct {
+			ID int64 `json:"id,string"`
+		} `json:"object"`
+	}
+	if err := json.Unmarshal(deliveries.inserted[2], &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Event != object.WebhookStatusCreated || got.Object.ID != 5 {
+		t.Errorf("payload = %s", deliveries.inserted[2])
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int64
+		want     time.Duration
+	}{
+		{attempts: 1, want: 30 * time.Second},
+		{attempts: 2, want: time.Minute},
+		{attempts: 3, want: 2 * time.Minute},
+		{attempts: 7, want: 32 * time.Minute},
+		{attempts: 8, want: time.Hour},
+	}
+	for _, tt := range tests {
+		if got := Backoff(tt.attempts); got != tt.want {
+			t.Errorf("Backoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
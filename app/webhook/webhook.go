@@ -0,0 +1,56 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/satorunooshie/Yatter/app/dao"
+	"github.com/satorunooshie/Yatter/app/domain/object"
+)
+
+// Headers of delivery requests
+const (
+	HeaderEvent     = "X-Yatter-Event"
+	HeaderDelivery  = "X-Yatter-Delivery"
+	HeaderSignature = "X-Yatter-Signature"
+)
+
+// Body of delivery requests
+type Payload struct {
+	Event    object.WebhookEvent `json:"event"`
+	CreateAt object.DateTime     `json:"create_at"`
+	Object   interface{}         `json:"object"`
+}
+
+// Queue the event for the webhooks subscribed to it, to be delivered by Deliverer
+func Enqueue(ctx context.Context, d dao.Dao, event object.WebhookEvent, obj interface{}) error {
+	webhooks, err := d.Webhook().SelectByEvent(ctx, event)
+	if err != nil {
+		return err
+	}
+	if len(webhooks) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(&Payload{Event: event, CreateAt: object.DateTime{Time: time.Now()}, Object: obj})
+	if err != nil {
+		return err
+	}
+
+	ids := make([]object.WebhookID, 0, len(webhooks))
+	for _, v := range webhooks {
+		ids = append(ids, v.ID)
+	}
+	return d.WebhookDelivery().Insert(ctx, event, payload, ids)
+}
+
+// Compute the X-Yatter-Signature header, HMAC-SHA256 of body keyed by secret
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -1,6 +1,13 @@
 CREATE TABLE `account` (
-  `id` bigint(20) NOT NULL AUTO_INCREMENT,
-  `username` varchar(255) NOT NULL UNIQUE,
+  `id` bigint(20) NOT NULL,
+  `username` varchar(255) NOT NULL,
+  `domain` varchar(255) NOT NULL DEFAULT '' COMMENT 'empty for local accounts',
+  `uri` varchar(255) DEFAULT NULL UNIQUE COMMENT 'ActivityPub actor ID of remote accounts',
+  `inbox_url` text DEFAULT NULL,
+  `shared_inbox_url` text DEFAULT NULL,
+  `private_key` text DEFAULT NULL COMMENT 'PEM of the key signing ActivityPub requests, local accounts only',
+  `public_key` text DEFAULT NULL COMMENT 'PEM',
+  `public_key_id` text DEFAULT NULL COMMENT 'ID of the public key of remote accounts',
   `password_hash` varchar(255) NOT NULL,
   `display_name` varchar(255),
   `avatar` text,
@@ -8,33 +15,47 @@ CREATE TABLE `account` (
   `note` text,
   `create_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
   `delete_at` datetime DEFAULT NULL,
-  PRIMARY KEY (`id`)
+  `suspend_at` datetime DEFAULT NULL,
+  `statuses_count` bigint(20) NOT NULL DEFAULT 0,
+  `followers_count` bigint(20) NOT NULL DEFAULT 0,
+  `following_count` bigint(20) NOT NULL DEFAULT 0,
+  `last_status_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE `uq_username_domain` (`username`, `domain`),
+  INDEX `idx_display_name` (`display_name`),
+  FULLTEXT INDEX `ft_username_display_name` (`username`, `display_name`) WITH PARSER ngram
 );
 
 CREATE TABLE `status` (
-  `id` bigint(20) NOT NULL AUTO_INCREMENT,
+  `id` bigint(20) NOT NULL,
   `account_id` bigint(20) NOT NULL,
   `content` text NOT NULL,
+  `visibility` tinyint(3) NOT NULL DEFAULT 0 COMMENT '0->public, 1->unlisted, 2->private, 3->direct',
+  `uri` varchar(255) DEFAULT NULL UNIQUE COMMENT 'ActivityPub object ID of remote statuses',
   `create_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `edit_at` datetime DEFAULT NULL,
   `delete_at` datetime DEFAULT NULL,
   PRIMARY KEY (`id`),
   INDEX `idx_account_id` (`account_id`),
+  INDEX `idx_visibility_id` (`visibility`, `id`),
+  INDEX `idx_create_at` (`create_at`),
+  FULLTEXT INDEX `ft_content` (`content`) WITH PARSER ngram,
   CONSTRAINT `fk_status_account_id` FOREIGN KEY (`account_id`) REFERENCES `account` (`id`)
 );
 
 CREATE TABLE `follow` (
-  `id` bigint(20) NOT NULL AUTO_INCREMENT,
+  `id` bigint(20) NOT NULL,
   `follower_id` bigint(20) NOT NULL,
   `followee_id` bigint(20) NOT NULL,
   `create_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
   `delete_at` datetime DEFAULT NULL,
   PRIMARY KEY (`id`),
-  INDEX `idx_follower_id` (`follower_id`),
+  UNIQUE `uq_follower_id_followee_id` (`follower_id`, `followee_id`),
   INDEX `idx_followee_id` (`followee_id`)
 );
 
 CREATE TABLE `media_attachment` (
-  `id` bigint(20) NOT NULL AUTO_INCREMENT,
+  `id` bigint(20) NOT NULL,
   `status_id` bigint(20) NOT NULL,
   `type` tinyint(3) DEFAULT 1 COMMENT '1->画像',
   `url` varchar(255) NOT NULL,
@@ -45,3 +66,176 @@ CREATE TABLE `media_attachment` (
   INDEX `idx_status_id` (`status_id`),
   CONSTRAINT `fk_media_attachments_status_id` FOREIGN KEY (`status_id`) REFERENCES `status` (`id`)
 );
+
+CREATE TABLE `mention` (
+  `id` bigint(20) NOT NULL,
+  `status_id` bigint(20) NOT NULL,
+  `account_id` bigint(20) NOT NULL,
+  `create_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE `uq_status_id_account_id` (`status_id`, `account_id`),
+  INDEX `idx_account_id` (`account_id`),
+  CONSTRAINT `fk_mention_status_id` FOREIGN KEY (`status_id`) REFERENCES `status` (`id`),
+  CONSTRAINT `fk_mention_account_id` FOREIGN KEY (`account_id`) REFERENCES `account` (`id`)
+);
+
+CREATE TABLE `tag` (
+  `id` bigint(20) NOT NULL,
+  `name` varchar(255) COLLATE utf8mb4_bin NOT NULL UNIQUE COMMENT 'NFKC normalized and lowercased, compared as is',
+  `create_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`)
+);
+
+CREATE TABLE `status_tag` (
+  `status_id` bigint(20) NOT NULL,
+  `tag_id` bigint(20) NOT NULL,
+  PRIMARY KEY (`status_id`, `tag_id`),
+  INDEX `idx_tag_id_status_id` (`tag_id`, `status_id`),
+  CONSTRAINT `fk_status_tag_status_id` FOREIGN KEY (`status_id`) REFERENCES `status` (`id`),
+  CONSTRAINT `fk_status_tag_tag_id` FOREIGN KEY (`tag_id`) REFERENCES `tag` (`id`)
+);
+
+CREATE TABLE `status_edit` (
+  `id` bigint(20) NOT NULL,
+  `status_id` bigint(20) NOT NULL,
+  `content` text NOT NULL,
+  `media_attachment_ids` text NOT NULL COMMENT 'JSON array of media_attachment.id',
+  `create_at` datetime NOT NULL COMMENT 'the time this version was posted',
+  PRIMARY KEY (`id`),
+  INDEX `idx_status_id` (`status_id`),
+  CONSTRAINT `fk_status_edit_status_id` FOREIGN KEY (`status_id`) REFERENCES `status` (`id`)
+);
+
+CREATE TABLE `poll` (
+  `id` bigint(20) NOT NULL,
+  `status_id` bigint(20) NOT NULL UNIQUE,
+  `expire_at` datetime NOT NULL,
+  `multiple` tinyint(1) NOT NULL DEFAULT 0,
+  `hide_totals` tinyint(1) NOT NULL DEFAULT 0 COMMENT 'hide counts from who have not voted until expired',
+  `create_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  CONSTRAINT `fk_poll_status_id` FOREIGN KEY (`status_id`) REFERENCES `status` (`id`)
+);
+
+CREATE TABLE `poll_option` (
+  `id` bigint(20) NOT NULL,
+  `poll_id` bigint(20) NOT NULL,
+  `position` int NOT NULL,
+  `title` varchar(255) NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE `uq_poll_id_position` (`poll_id`, `position`),
+  CONSTRAINT `fk_poll_option_poll_id` FOREIGN KEY (`poll_id`) REFERENCES `poll` (`id`)
+);
+
+CREATE TABLE `poll_vote` (
+  `id` bigint(20) NOT NULL,
+  `poll_id` bigint(20) NOT NULL,
+  `account_id` bigint(20) NOT NULL,
+  `choice` int NOT NULL COMMENT 'poll_option.position',
+  `create_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE `uq_poll_id_account_id_choice` (`poll_id`, `account_id`, `choice`),
+  INDEX `idx_account_id` (`account_id`),
+  CONSTRAINT `fk_poll_vote_poll_id` FOREIGN KEY (`poll_id`) REFERENCES `poll` (`id`),
+  CONSTRAINT `fk_poll_vote_account_id` FOREIGN KEY (`account_id`) REFERENCES `account` (`id`)
+);
+
+CREATE TABLE `scheduled_status` (
+  `id` bigint(20) NOT NULL,
+  `account_id` bigint(20) NOT NULL,
+  `scheduled_at` datetime NOT NULL,
+  `params` text NOT NULL COMMENT 'JSON of text, visibility and poll to create the status with',
+  `create_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  INDEX `idx_scheduled_at` (`scheduled_at`),
+  INDEX `idx_account_id_scheduled_at` (`account_id`, `scheduled_at`),
+  CONSTRAINT `fk_scheduled_status_account_id` FOREIGN KEY (`account_id`) REFERENCES `account` (`id`)
+);
+
+CREATE TABLE `bookmark` (
+  `id` bigint(20) NOT NULL,
+  `account_id` bigint(20) NOT NULL,
+  `status_id` bigint(20) NOT NULL,
+  `create_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE `uq_account_id_status_id` (`account_id`, `status_id`),
+  CONSTRAINT `fk_bookmark_account_id` FOREIGN KEY (`account_id`) REFERENCES `account` (`id`),
+  CONSTRAINT `fk_bookmark_status_id` FOREIGN KEY (`status_id`) REFERENCES `status` (`id`)
+);
+
+CREATE TABLE `pinned_status` (
+  `id` bigint(20) NOT NULL,
+  `account_id` bigint(20) NOT NULL,
+  `status_id` bigint(20) NOT NULL UNIQUE,
+  `position` int NOT NULL COMMENT 'larger is pinned later and shown first',
+  `create_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  INDEX `idx_account_id_position` (`account_id`, `position`),
+  CONSTRAINT `fk_pinned_status_account_id` FOREIGN KEY (`account_id`) REFERENCES `account` (`id`),
+  CONSTRAINT `fk_pinned_status_status_id` FOREIGN KEY (`status_id`) REFERENCES `status` (`id`)
+);
+
+CREATE TABLE `notification` (
+  `id` bigint(20) NOT NULL,
+  `account_id` bigint(20) NOT NULL COMMENT 'notified account',
+  `type` tinyint NOT NULL COMMENT '0: mention, 1: follow, 2: favourite, 3: reblog',
+  `from_account_id` bigint(20) NOT NULL,
+  `status_id` bigint(20) NOT NULL DEFAULT 0 COMMENT '0 for notifications not about a status',
+  `create_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE `uq_account_id_type_from_account_id_status_id` (`account_id`, `type`, `from_account_id`, `status_id`),
+  INDEX `idx_status_id` (`status_id`),
+  CONSTRAINT `fk_notification_account_id` FOREIGN KEY (`account_id`) REFERENCES `account` (`id`),
+  CONSTRAINT `fk_notification_from_account_id` FOREIGN KEY (`from_account_id`) REFERENCES `account` (`id`)
+);
+
+CREATE TABLE `webhook` (
+  `id` bigint(20) NOT NULL,
+  `url` text NOT NULL,
+  `secret` varchar(255) NOT NULL COMMENT 'key of HMAC-SHA256 signatures',
+  `events` text NOT NULL COMMENT 'JSON array of event types subscribed to',
+  `enabled` tinyint(1) NOT NULL DEFAULT 1,
+  `failure_count` int NOT NULL DEFAULT 0 COMMENT 'failed attempts in a row',
+  `create_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`)
+);
+
+CREATE TABLE `webhook_delivery` (
+  `id` bigint(20) NOT NULL,
+  `webhook_id` bigint(20) NOT NULL,
+  `event` varchar(255) NOT NULL,
+  `payload` mediumtext NOT NULL,
+  `status` tinyint NOT NULL DEFAULT 0 COMMENT '0: pending, 1: succeeded, 2: failed',
+  `attempts` int NOT NULL DEFAULT 0,
+  `next_attempt_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `response_status` int DEFAULT NULL,
+  `error` text DEFAULT NULL,
+  `create_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `delivered_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_status_next_attempt_at` (`status`, `next_attempt_at`),
+  INDEX `idx_webhook_id` (`webhook_id`),
+  CONSTRAINT `fk_webhook_delivery_webhook_id` FOREIGN KEY (`webhook_id`) REFERENCES `webhook` (`id`)
+);
+
+CREATE TABLE `activity_delivery` (
+  `id` bigint(20) NOT NULL,
+  `account_id` bigint(20) NOT NULL COMMENT 'local account signing the delivery',
+  `inbox_url` text NOT NULL,
+  `activity` mediumtext NOT NULL,
+  `status` tinyint NOT NULL DEFAULT 0 COMMENT '0: pending, 1: succeeded, 2: failed',
+  `attempts` int NOT NULL DEFAULT 0,
+  `next_attempt_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `error` text DEFAULT NULL,
+  `create_at` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `delivered_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_status_next_attempt_at` (`status`, `next_attempt_at`)
+);
+
+-- 統計のカラムを追加する前からあるデータベースでは、この文だけを実行して数え直す
+UPDATE `account` AS `a` SET
+  `statuses_count` = (SELECT COUNT(*) FROM `status` AS `s` WHERE `s`.`account_id` = `a`.`id` AND `s`.`delete_at` IS NULL),
+  `followers_count` = (SELECT COUNT(*) FROM `follow` AS `f` WHERE `f`.`followee_id` = `a`.`id` AND `f`.`delete_at` IS NULL),
+  `following_count` = (SELECT COUNT(*) FROM `follow` AS `f` WHERE `f`.`follower_id` = `a`.`id` AND `f`.`delete_at` IS NULL),
+  `last_status_at` = (SELECT MAX(`s`.`create_at`) FROM `status` AS `s` WHERE `s`.`account_id` = `a`.`id` AND `s`.`delete_at` IS NULL);
@@ -20,6 +20,11 @@ func serve(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	go app.Trends.Run(ctx, config.Trends.Interval())
+	go app.Scheduler.Run(ctx, config.Scheduler.Interval())
+	go app.Webhooks.Run(ctx, config.Webhook.Interval())
+	go app.Federation.Run(ctx, config.Federation.Interval())
+
 	addr := ":" + strconv.Itoa(config.Port())
 	log.Printf("Serve on http://%s", addr)
 